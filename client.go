@@ -54,10 +54,21 @@ type Client interface {
 	// Spans
 	GetSpans(ctx context.Context, request *GetSpansRequest) (*GetSpansResponse, error)
 	GetSpansIter(ctx context.Context, request *GetSpansRequest) *Iter[ResourceSpans]
+	GetSpansIterBackward(ctx context.Context, request *GetSpansRequest) *Iter[ResourceSpans]
+	GetSpansPages(ctx context.Context, request *GetSpansRequest) *Iter[GetSpansResponse]
+	GetSpansPagesBackward(ctx context.Context, request *GetSpansRequest) *Iter[GetSpansResponse]
+	GetSpansWindowedIter(ctx context.Context, request *GetSpansRequest, opts ...WindowOption) *Iter[ResourceSpans]
+	GetTrace(ctx context.Context, traceID string, timeRange TimeReferenceRange, dataset *string) (*Trace, error)
+	TailSpans(ctx context.Context, request *GetSpansRequest, fn func(*SpanEntry) error, opts ...TailOption) error
 
 	// Logs
 	GetLogRecords(ctx context.Context, request *GetLogRecordsRequest) (*GetLogRecordsResponse, error)
 	GetLogRecordsIter(ctx context.Context, request *GetLogRecordsRequest) *Iter[ResourceLogs]
+	GetLogRecordsIterBackward(ctx context.Context, request *GetLogRecordsRequest) *Iter[ResourceLogs]
+	GetLogRecordsPages(ctx context.Context, request *GetLogRecordsRequest) *Iter[GetLogRecordsResponse]
+	GetLogRecordsPagesBackward(ctx context.Context, request *GetLogRecordsRequest) *Iter[GetLogRecordsResponse]
+	GetLogRecordsWindowedIter(ctx context.Context, request *GetLogRecordsRequest, opts ...WindowOption) *Iter[ResourceLogs]
+	TailLogRecords(ctx context.Context, request *GetLogRecordsRequest, fn func(*LogRecordEntry) error, opts ...TailOption) error
 
 	// Import
 	ImportCheckRule(ctx context.Context, rule *PostApiImportCheckRuleJSONRequestBody, dataset *string) (*PrometheusAlertRule, error)
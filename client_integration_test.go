@@ -416,7 +416,7 @@ func TestClient_Integration(t *testing.T) {
 
 		// Use withIdempotent to allow retrying this POST request
 		ctx := withIdempotent(context.Background())
-		_, err = client.GetSpans(ctx, &GetSpansRequest{})
+		_, err = client.GetSpans(ctx, &GetSpansRequest{TimeRange: TimeRangeLast(time.Hour)})
 		if err != nil {
 			t.Fatalf("expected request to succeed after retry, got: %v", err)
 		}
@@ -455,4 +455,132 @@ func TestClient_Integration(t *testing.T) {
 			t.Errorf("expected 1 attempt (no retries for 4xx), got %d", attempts)
 		}
 	})
+
+	t.Run("GetSpansPages exposes cursors and resumes from a saved cursor", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var req GetSpansRequest
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				t.Errorf("failed to decode request: %v", err)
+			}
+
+			// Pages are "", "c1" and "c2"; the last page has no next cursor
+			var cursor string
+			if req.Pagination != nil && req.Pagination.Cursor != nil {
+				cursor = *req.Pagination.Cursor
+			}
+			resp := GetSpansResponse{ExecutionTime: Ptr(time.Unix(0, 0).UTC())}
+			switch cursor {
+			case "":
+				resp.Cursors = &NextCursors{After: Ptr("c1")}
+			case "c1":
+				resp.Cursors = &NextCursors{After: Ptr("c2")}
+			}
+			resp.ResourceSpans = []ResourceSpans{{SchemaUrl: Ptr("page-" + cursor)}}
+
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusOK)
+			_ = json.NewEncoder(w).Encode(resp)
+		}))
+		defer server.Close()
+
+		client, err := NewClient(
+			WithApiUrl(server.URL),
+			WithAuthToken("auth_test123"),
+		)
+		if err != nil {
+			t.Fatalf("failed to create client: %v", err)
+		}
+
+		request := &GetSpansRequest{
+			TimeRange:  TimeReferenceRange{From: "now-1h", To: "now"},
+			Pagination: &CursorPagination{Limit: Int64(10)},
+		}
+		pages := client.GetSpansPages(context.Background(), request)
+
+		var cursors []string
+		for pages.Next() {
+			if pages.Current().ExecutionTime == nil {
+				t.Error("expected page to include execution time")
+			}
+			cursors = append(cursors, StringValue(pages.Cursor()))
+		}
+		if err := pages.Err(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(cursors) != 3 || cursors[0] != "c1" || cursors[1] != "c2" || cursors[2] != "" {
+			t.Errorf("unexpected cursors: %v", cursors)
+		}
+		if request.Pagination.Cursor != nil {
+			t.Error("original request must not be modified")
+		}
+
+		var schemaURLs []string
+		for resourceSpans, err := range client.GetSpansIter(context.Background(), request.WithCursor("c2")).All() {
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			schemaURLs = append(schemaURLs, StringValue(resourceSpans.SchemaUrl))
+		}
+		if len(schemaURLs) != 1 || schemaURLs[0] != "page-c2" {
+			t.Errorf("expected to resume at page-c2, got %v", schemaURLs)
+		}
+	})
+
+	t.Run("GetLogRecordsIterBackward follows before cursors", func(t *testing.T) {
+		var requested []string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var req GetLogRecordsRequest
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				t.Errorf("failed to decode request: %v", err)
+			}
+
+			// Pages are "anchor", "older" and "oldest"
+			var cursor string
+			if req.Pagination != nil && req.Pagination.Cursor != nil {
+				cursor = *req.Pagination.Cursor
+			}
+			requested = append(requested, cursor)
+			resp := GetLogRecordsResponse{
+				ResourceLogs: []ResourceLogs{{SchemaUrl: Ptr(cursor)}},
+				Cursors:      &NextCursors{After: Ptr("newer")},
+			}
+			switch cursor {
+			case "anchor":
+				resp.Cursors.Before = Ptr("older")
+			case "older":
+				resp.Cursors.Before = Ptr("oldest")
+			}
+
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusOK)
+			_ = json.NewEncoder(w).Encode(resp)
+		}))
+		defer server.Close()
+
+		client, err := NewClient(
+			WithApiUrl(server.URL),
+			WithAuthToken("auth_test123"),
+		)
+		if err != nil {
+			t.Fatalf("failed to create client: %v", err)
+		}
+
+		request := &GetLogRecordsRequest{TimeRange: TimeReferenceRange{From: "now-1h", To: "now"}}
+		iter := client.GetLogRecordsIterBackward(context.Background(), request.WithCursor("anchor"))
+
+		var pages []string
+		for resourceLogs, err := range iter.All() {
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			pages = append(pages, StringValue(resourceLogs.SchemaUrl))
+		}
+
+		if len(pages) != 3 || pages[0] != "anchor" || pages[1] != "older" || pages[2] != "oldest" {
+			t.Errorf("unexpected pages: %v", pages)
+		}
+		if len(requested) != 3 {
+			t.Errorf("expected 3 requests, got %v", requested)
+		}
+	})
 }
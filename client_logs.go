@@ -8,7 +8,11 @@ import (
 
 // GetLogRecords retrieves log records based on the provided request.
 // This is a POST endpoint but is idempotent (read-only query).
+// The time range of the request is checked with ValidateTimeRange before sending.
 func (c *client) GetLogRecords(ctx context.Context, request *GetLogRecordsRequest) (*GetLogRecordsResponse, error) {
+	if err := ValidateTimeRange(request.TimeRange); err != nil {
+		return nil, err
+	}
 	ctx = withIdempotent(ctx)
 	resp, err := c.inner.PostApiLogsWithResponse(ctx, *request)
 	if err != nil {
@@ -37,41 +41,207 @@ func (c *client) GetLogRecords(ctx context.Context, request *GetLogRecordsReques
 //	    // handle error
 //	}
 func (c *client) GetLogRecordsIter(ctx context.Context, request *GetLogRecordsRequest) *Iter[ResourceLogs] {
+	return c.getLogRecordsIter(ctx, request, afterCursor)
+}
+
+// GetLogRecordsIterBackward is like GetLogRecordsIter, but follows the Before cursors of
+// the responses instead of the After cursors. Combine it with WithCursor to
+// walk away from an anchor page in the opposite direction.
+//
+// Example:
+//
+//	// Load the pages preceding an anchor page
+//	iter := client.GetLogRecordsIterBackward(ctx, request.WithCursor(*anchor.Cursors.Before))
+//	for resourceLog, err := range iter.All() {
+//	    if err != nil {
+//	        // handle error
+//	    }
+//	    // process resourceLog
+//	}
+func (c *client) GetLogRecordsIterBackward(ctx context.Context, request *GetLogRecordsRequest) *Iter[ResourceLogs] {
+	return c.getLogRecordsIter(ctx, request, beforeCursor)
+}
+
+// getLogRecordsIter returns an iterator over log records that follows the cursors
+// selected by next.
+func (c *client) getLogRecordsIter(ctx context.Context, request *GetLogRecordsRequest, next func(*NextCursors) *string) *Iter[ResourceLogs] {
 	// Make initial request
 	resp, err := c.GetLogRecords(ctx, request)
 	if err != nil {
 		return newIterWithError[ResourceLogs](err)
 	}
 
-	items := toPointerSlice(resp.ResourceLogs)
-	var cursor *string
-	hasMore := false
-	if resp.Cursors != nil && resp.Cursors.After != nil {
-		cursor = (*string)(resp.Cursors.After)
-		hasMore = true
-	}
-
-	return newIter(items, hasMore, cursor, func(cur *string) ([]*ResourceLogs, *string, error) {
-		// Create a copy of the request with the cursor
-		nextReq := *request
-		if nextReq.Pagination == nil {
-			nextReq.Pagination = &CursorPagination{}
-		} else {
-			paginationCopy := *nextReq.Pagination
-			nextReq.Pagination = &paginationCopy
+	cursor := next(resp.Cursors)
+	return newIter(toPointerSlice(resp.ResourceLogs), cursor != nil, cursor, func(cur *string) ([]*ResourceLogs, *string, error) {
+		resp, err := c.GetLogRecords(ctx, request.WithCursor(*cur))
+		if err != nil {
+			return nil, nil, err
 		}
-		nextReq.Pagination.Cursor = (*Cursor)(cur)
+		return toPointerSlice(resp.ResourceLogs), next(resp.Cursors), nil
+	})
+}
 
-		resp, err := c.GetLogRecords(ctx, &nextReq)
+// GetLogRecordsPages returns an iterator over the pages of log records matching the request.
+// Each item is a complete response, including ExecutionTime and TimeRange.
+// After processing a page, Cursor() returns the cursor of the next page, which
+// can be persisted and later passed to WithCursor to resume from that position.
+//
+// Example:
+//
+//	pages := client.GetLogRecordsPages(ctx, request)
+//	for pages.Next() {
+//	    page := pages.Current()
+//	    // process page.ResourceLogs
+//	    if cursor := pages.Cursor(); cursor != nil {
+//	        // checkpoint *cursor
+//	    }
+//	}
+//	if err := pages.Err(); err != nil {
+//	    // handle error, then resume later with request.WithCursor(checkpoint)
+//	}
+func (c *client) GetLogRecordsPages(ctx context.Context, request *GetLogRecordsRequest) *Iter[GetLogRecordsResponse] {
+	return c.getLogRecordsPages(ctx, request, afterCursor)
+}
+
+// GetLogRecordsPagesBackward is like GetLogRecordsPages, but follows the Before cursors
+// of the responses instead of the After cursors. Cursor() then returns the
+// Before cursor of the current page.
+func (c *client) GetLogRecordsPagesBackward(ctx context.Context, request *GetLogRecordsRequest) *Iter[GetLogRecordsResponse] {
+	return c.getLogRecordsPages(ctx, request, beforeCursor)
+}
+
+// getLogRecordsPages returns an iterator over pages of log records that follows the
+// cursors selected by next.
+func (c *client) getLogRecordsPages(ctx context.Context, request *GetLogRecordsRequest, next func(*NextCursors) *string) *Iter[GetLogRecordsResponse] {
+	resp, err := c.GetLogRecords(ctx, request)
+	if err != nil {
+		return newIterWithError[GetLogRecordsResponse](err)
+	}
+
+	cursor := next(resp.Cursors)
+	return newIter([]*GetLogRecordsResponse{resp}, cursor != nil, cursor, func(cur *string) ([]*GetLogRecordsResponse, *string, error) {
+		resp, err := c.GetLogRecords(ctx, request.WithCursor(*cur))
 		if err != nil {
 			return nil, nil, err
 		}
+		return []*GetLogRecordsResponse{resp}, next(resp.Cursors), nil
+	})
+}
 
-		items := toPointerSlice(resp.ResourceLogs)
-		var nextCursor *string
-		if resp.Cursors != nil && resp.Cursors.After != nil {
-			nextCursor = (*string)(resp.Cursors.After)
+// GetLogRecordsWindowedIter returns an iterator over log records matching the request
+// that splits the time range into sub-windows and queries them in parallel.
+// This keeps individual queries small when exporting large time ranges.
+//
+// Windows are queried ahead of the iteration, bounded by WithWindowLookahead
+// and the client's limit on concurrent requests. Results are yielded window by
+// window, newest first unless the first ordering criterion of the request is
+// ascending, so the order across pages is preserved when ordering by time.
+// Relative times are resolved once when the iterator is created.
+//
+// Call Close on the iterator when abandoning it early to stop prefetching.
+// Cursor() always returns nil, since a windowed iteration cannot be resumed
+// from a single cursor.
+//
+// Example:
+//
+//	iter := client.GetLogRecordsWindowedIter(ctx, &dash0.GetLogRecordsRequest{
+//	    TimeRange: dash0.TimeRangeLast(7 * 24 * time.Hour),
+//	}, dash0.WithWindowSize(6*time.Hour), dash0.WithAdaptiveWindows(15*time.Minute))
+//	defer iter.Close()
+//	for resourceLog, err := range iter.All() {
+//	    if err != nil {
+//	        // handle error
+//	    }
+//	    // process resourceLog
+//	}
+func (c *client) GetLogRecordsWindowedIter(ctx context.Context, request *GetLogRecordsRequest, opts ...WindowOption) *Iter[ResourceLogs] {
+	cfg := newWindowConfig(c.config.maxConcurrent, opts)
+	r, err := ResolveTimeRange(request.TimeRange, cfg.now())
+	if err != nil {
+		return newIterWithError[ResourceLogs](err)
+	}
+
+	return newWindowedIter(ctx, r, isDescending(request.Ordering), cfg, func(ctx context.Context, w timeWindow, cursor *string) ([]*ResourceLogs, *string, error) {
+		req := request.withCursor(cursor)
+		req.TimeRange = w.timeRange()
+		resp, err := c.GetLogRecords(ctx, req)
+		if err != nil {
+			return nil, nil, err
 		}
-		return items, nextCursor, nil
+		return toPointerSlice(resp.ResourceLogs), afterCursor(resp.Cursors), nil
 	})
 }
+
+// TailLogRecords follows the log records matching the request as they arrive,
+// like tail -f, and passes each of them to fn exactly once. It first emits the
+// records of the request's time range, and then polls for newer records every
+// WithTailInterval, until ctx is done or fn returns an error, which it then
+// returns.
+//
+// Each poll reaches back by WithTailOverlap to pick up records that arrive
+// late. Records are de-duplicated across polls by their Id or Dash0EventId,
+// or by their content if the API returns neither. The records of a poll are
+// emitted ordered by time, but late records are emitted in a later poll than
+// newer records that arrived earlier.
+//
+// Requests go through the client's concurrency limit and retries. If a poll
+// still fails with a rate limiting or server error, the same window is polled
+// again with exponential backoff; other errors end the tail.
+//
+// Example:
+//
+//	err := client.TailLogRecords(ctx, &dash0.GetLogRecordsRequest{
+//	    TimeRange: dash0.TimeRangeLast(5 * time.Minute),
+//	    Filter:    &filter,
+//	}, func(entry *dash0.LogRecordEntry) error {
+//	    fmt.Println(entry.LogRecord.Body.String())
+//	    return nil
+//	})
+//	if err != nil && !errors.Is(err, context.Canceled) {
+//	    // handle error
+//	}
+func (c *client) TailLogRecords(ctx context.Context, request *GetLogRecordsRequest, fn func(*LogRecordEntry) error, opts ...TailOption) error {
+	cfg := newTailConfig(c.config.retryWaitMax, opts)
+	r, err := ResolveTimeRange(request.TimeRange, cfg.now())
+	if err != nil {
+		return err
+	}
+
+	t := &tailer[*LogRecordEntry]{
+		config:    cfg,
+		key:       logRecordKey,
+		timestamp: logRecordTimestamp,
+		fetch: func(ctx context.Context, w timeWindow) ([]*LogRecordEntry, error) {
+			req := request.withCursor(nil)
+			req.TimeRange = w.timeRange()
+			var resourceLogs []*ResourceLogs
+			it := c.GetLogRecordsIter(ctx, req)
+			for it.Next() {
+				resourceLogs = append(resourceLogs, it.Current())
+			}
+			return logRecordEntries(resourceLogs), it.Err()
+		},
+	}
+	return t.run(ctx, r, fn)
+}
+
+// WithCursor returns a copy of the request that starts at the given cursor.
+// Use it to resume an iteration from a cursor obtained via Iter.Cursor().
+// The original request, including its Pagination, is not modified.
+func (r *GetLogRecordsRequest) WithCursor(cursor Cursor) *GetLogRecordsRequest {
+	return r.withCursor(&cursor)
+}
+
+// withCursor returns a copy of the request with its own Pagination that starts
+// at the given cursor, or at the first page if cursor is nil.
+func (r *GetLogRecordsRequest) withCursor(cursor *Cursor) *GetLogRecordsRequest {
+	req := *r
+	if req.Pagination == nil {
+		req.Pagination = &CursorPagination{}
+	} else {
+		pagination := *req.Pagination
+		req.Pagination = &pagination
+	}
+	req.Pagination.Cursor = cursor
+	return &req
+}
@@ -8,7 +8,11 @@ import (
 
 // GetSpans retrieves spans based on the provided request.
 // This is a POST endpoint but is idempotent (read-only query).
+// The time range of the request is checked with ValidateTimeRange before sending.
 func (c *client) GetSpans(ctx context.Context, request *GetSpansRequest) (*GetSpansResponse, error) {
+	if err := ValidateTimeRange(request.TimeRange); err != nil {
+		return nil, err
+	}
 	ctx = withIdempotent(ctx)
 	resp, err := c.inner.PostApiSpansWithResponse(ctx, *request)
 	if err != nil {
@@ -37,41 +41,210 @@ func (c *client) GetSpans(ctx context.Context, request *GetSpansRequest) (*GetSp
 //	    // handle error
 //	}
 func (c *client) GetSpansIter(ctx context.Context, request *GetSpansRequest) *Iter[ResourceSpans] {
+	return c.getSpansIter(ctx, request, afterCursor)
+}
+
+// GetSpansIterBackward is like GetSpansIter, but follows the Before cursors of
+// the responses instead of the After cursors. Combine it with WithCursor to
+// walk away from an anchor page in the opposite direction.
+//
+// Example:
+//
+//	// Load the pages preceding an anchor page
+//	iter := client.GetSpansIterBackward(ctx, request.WithCursor(*anchor.Cursors.Before))
+//	for resourceSpan, err := range iter.All() {
+//	    if err != nil {
+//	        // handle error
+//	    }
+//	    // process resourceSpan
+//	}
+func (c *client) GetSpansIterBackward(ctx context.Context, request *GetSpansRequest) *Iter[ResourceSpans] {
+	return c.getSpansIter(ctx, request, beforeCursor)
+}
+
+// getSpansIter returns an iterator over spans that follows the cursors
+// selected by next.
+func (c *client) getSpansIter(ctx context.Context, request *GetSpansRequest, next func(*NextCursors) *string) *Iter[ResourceSpans] {
 	// Make initial request
 	resp, err := c.GetSpans(ctx, request)
 	if err != nil {
 		return newIterWithError[ResourceSpans](err)
 	}
 
-	items := toPointerSlice(resp.ResourceSpans)
-	var cursor *string
-	hasMore := false
-	if resp.Cursors != nil && resp.Cursors.After != nil {
-		cursor = (*string)(resp.Cursors.After)
-		hasMore = true
-	}
-
-	return newIter(items, hasMore, cursor, func(cur *string) ([]*ResourceSpans, *string, error) {
-		// Create a copy of the request with the cursor
-		nextReq := *request
-		if nextReq.Pagination == nil {
-			nextReq.Pagination = &CursorPagination{}
-		} else {
-			paginationCopy := *nextReq.Pagination
-			nextReq.Pagination = &paginationCopy
+	cursor := next(resp.Cursors)
+	return newIter(toPointerSlice(resp.ResourceSpans), cursor != nil, cursor, func(cur *string) ([]*ResourceSpans, *string, error) {
+		resp, err := c.GetSpans(ctx, request.WithCursor(*cur))
+		if err != nil {
+			return nil, nil, err
 		}
-		nextReq.Pagination.Cursor = (*Cursor)(cur)
+		return toPointerSlice(resp.ResourceSpans), next(resp.Cursors), nil
+	})
+}
 
-		resp, err := c.GetSpans(ctx, &nextReq)
+// GetSpansPages returns an iterator over the pages of spans matching the request.
+// Each item is a complete response, including ExecutionTime and TimeRange.
+// After processing a page, Cursor() returns the cursor of the next page, which
+// can be persisted and later passed to WithCursor to resume from that position.
+//
+// Example:
+//
+//	pages := client.GetSpansPages(ctx, request)
+//	for pages.Next() {
+//	    page := pages.Current()
+//	    // process page.ResourceSpans
+//	    if cursor := pages.Cursor(); cursor != nil {
+//	        // checkpoint *cursor
+//	    }
+//	}
+//	if err := pages.Err(); err != nil {
+//	    // handle error, then resume later with request.WithCursor(checkpoint)
+//	}
+func (c *client) GetSpansPages(ctx context.Context, request *GetSpansRequest) *Iter[GetSpansResponse] {
+	return c.getSpansPages(ctx, request, afterCursor)
+}
+
+// GetSpansPagesBackward is like GetSpansPages, but follows the Before cursors
+// of the responses instead of the After cursors. Cursor() then returns the
+// Before cursor of the current page.
+func (c *client) GetSpansPagesBackward(ctx context.Context, request *GetSpansRequest) *Iter[GetSpansResponse] {
+	return c.getSpansPages(ctx, request, beforeCursor)
+}
+
+// getSpansPages returns an iterator over pages of spans that follows the
+// cursors selected by next.
+func (c *client) getSpansPages(ctx context.Context, request *GetSpansRequest, next func(*NextCursors) *string) *Iter[GetSpansResponse] {
+	resp, err := c.GetSpans(ctx, request)
+	if err != nil {
+		return newIterWithError[GetSpansResponse](err)
+	}
+
+	cursor := next(resp.Cursors)
+	return newIter([]*GetSpansResponse{resp}, cursor != nil, cursor, func(cur *string) ([]*GetSpansResponse, *string, error) {
+		resp, err := c.GetSpans(ctx, request.WithCursor(*cur))
 		if err != nil {
 			return nil, nil, err
 		}
+		return []*GetSpansResponse{resp}, next(resp.Cursors), nil
+	})
+}
 
-		items := toPointerSlice(resp.ResourceSpans)
-		var nextCursor *string
-		if resp.Cursors != nil && resp.Cursors.After != nil {
-			nextCursor = (*string)(resp.Cursors.After)
+// GetSpansWindowedIter returns an iterator over spans matching the request
+// that splits the time range into sub-windows and queries them in parallel.
+// This keeps individual queries small when exporting large time ranges.
+//
+// Windows are queried ahead of the iteration, bounded by WithWindowLookahead
+// and the client's limit on concurrent requests. Results are yielded window by
+// window, newest first unless the first ordering criterion of the request is
+// ascending, so the order across pages is preserved when ordering by time.
+// Relative times are resolved once when the iterator is created.
+//
+// Call Close on the iterator when abandoning it early to stop prefetching.
+// Cursor() always returns nil, since a windowed iteration cannot be resumed
+// from a single cursor.
+//
+// Example:
+//
+//	iter := client.GetSpansWindowedIter(ctx, &dash0.GetSpansRequest{
+//	    TimeRange: dash0.TimeRangeLast(7 * 24 * time.Hour),
+//	}, dash0.WithWindowSize(6*time.Hour), dash0.WithAdaptiveWindows(15*time.Minute))
+//	defer iter.Close()
+//	for resourceSpan, err := range iter.All() {
+//	    if err != nil {
+//	        // handle error
+//	    }
+//	    // process resourceSpan
+//	}
+func (c *client) GetSpansWindowedIter(ctx context.Context, request *GetSpansRequest, opts ...WindowOption) *Iter[ResourceSpans] {
+	cfg := newWindowConfig(c.config.maxConcurrent, opts)
+	r, err := ResolveTimeRange(request.TimeRange, cfg.now())
+	if err != nil {
+		return newIterWithError[ResourceSpans](err)
+	}
+
+	return newWindowedIter(ctx, r, isDescending(request.Ordering), cfg, func(ctx context.Context, w timeWindow, cursor *string) ([]*ResourceSpans, *string, error) {
+		req := request.withCursor(cursor)
+		req.TimeRange = w.timeRange()
+		resp, err := c.GetSpans(ctx, req)
+		if err != nil {
+			return nil, nil, err
 		}
-		return items, nextCursor, nil
+		return toPointerSlice(resp.ResourceSpans), afterCursor(resp.Cursors), nil
 	})
 }
+
+// TailSpans follows the spans matching the request as they arrive and passes
+// each of them to fn exactly once. It first emits the spans of the request's
+// time range, and then polls for newer spans every WithTailInterval, until ctx
+// is done or fn returns an error, which it then returns. Only spans matching
+// the request's Filter are emitted.
+//
+// Each poll reaches back by WithTailOverlap to pick up spans that arrive late.
+// Spans are selected by their start time, so spans that run for longer than
+// the overlap are missed unless the overlap is raised accordingly. Spans are
+// de-duplicated across polls by their trace ID and span ID. The spans of a
+// poll are emitted ordered by start time.
+//
+// fn is called synchronously, so a slow consumer delays the next poll instead
+// of piling up spans; cancel ctx to stop the tail. Requests go through the
+// client's concurrency limit and retries. If a poll still fails with a rate
+// limiting or server error, the same window is polled again with exponential
+// backoff; other errors end the tail.
+//
+// Example:
+//
+//	filter, _ := dash0.ParseFilter(`service.name = "checkout" AND otel.span.status.code = 2`)
+//	err := client.TailSpans(ctx, &dash0.GetSpansRequest{
+//	    TimeRange: dash0.TimeRangeLast(5 * time.Minute),
+//	    Filter:    &filter,
+//	}, func(entry *dash0.SpanEntry) error {
+//	    fmt.Println(entry.Span.Name, entry.Span.Status.Message)
+//	    return nil
+//	})
+//	if err != nil && !errors.Is(err, context.Canceled) {
+//	    // handle error
+//	}
+func (c *client) TailSpans(ctx context.Context, request *GetSpansRequest, fn func(*SpanEntry) error, opts ...TailOption) error {
+	cfg := newTailConfig(c.config.retryWaitMax, opts)
+	r, err := ResolveTimeRange(request.TimeRange, cfg.now())
+	if err != nil {
+		return err
+	}
+
+	t := &tailer[*SpanEntry]{
+		config:    cfg,
+		key:       spanKey,
+		timestamp: spanTimestamp,
+		fetch: func(ctx context.Context, w timeWindow) ([]*SpanEntry, error) {
+			req := request.withCursor(nil)
+			req.TimeRange = w.timeRange()
+			var resourceSpans []*ResourceSpans
+			it := c.GetSpansIter(ctx, req)
+			for it.Next() {
+				resourceSpans = append(resourceSpans, it.Current())
+			}
+			return spanEntries(resourceSpans), it.Err()
+		},
+	}
+	return t.run(ctx, r, fn)
+}
+
+// WithCursor returns a copy of the request that starts at the given cursor.
+// Use it to resume an iteration from a cursor obtained via Iter.Cursor().
+// The original request, including its Pagination, is not modified.
+func (r *GetSpansRequest) WithCursor(cursor Cursor) *GetSpansRequest {
+	return r.withCursor(&cursor)
+}
+
+// withCursor returns a copy of the request with its own Pagination that starts
+// at the given cursor, or at the first page if cursor is nil.
+func (r *GetSpansRequest) withCursor(cursor *Cursor) *GetSpansRequest {
+	req := *r
+	if req.Pagination == nil {
+		req.Pagination = &CursorPagination{}
+	} else {
+		pagination := *req.Pagination
+		req.Pagination = &pagination
+	}
+	req.Pagination.Cursor = cursor
+	return &req
+}
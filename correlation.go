@@ -0,0 +1,175 @@
+package dash0
+
+import (
+	"cmp"
+	"context"
+	"encoding/hex"
+	"errors"
+	"slices"
+	"time"
+)
+
+// DefaultCorrelationWindow is the default time by which correlation queries
+// extend the time of the log record or span they start from, in both
+// directions.
+const DefaultCorrelationWindow = 15 * time.Minute
+
+// ErrNoTraceContext is returned when correlating a log record without a
+// trace ID.
+var ErrNoTraceContext = errors.New("dash0: log record has no trace context")
+
+// CorrelationOption configures correlation queries such as GetLogRecordTrace.
+type CorrelationOption func(*correlationConfig)
+
+type correlationConfig struct {
+	window time.Duration
+}
+
+// WithCorrelationWindow sets the time by which the query extends the time of
+// the log record or span in both directions. Spans of a trace may start long
+// before or end long after a log record, and log records may be timestamped
+// outside of their span due to clock skew. Defaults to
+// DefaultCorrelationWindow.
+func WithCorrelationWindow(window time.Duration) CorrelationOption {
+	return func(c *correlationConfig) {
+		c.window = window
+	}
+}
+
+func newCorrelationConfig(opts []CorrelationOption) *correlationConfig {
+	cfg := &correlationConfig{window: DefaultCorrelationWindow}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	if cfg.window < 0 {
+		cfg.window = 0
+	}
+	return cfg
+}
+
+// timeRange returns the range from start minus the window to end plus the
+// window.
+func (c *correlationConfig) timeRange(start, end time.Time) TimeReferenceRange {
+	return TimeReferenceRange{From: NewFixedTime(start.Add(-c.window)), To: NewFixedTime(end.Add(c.window))}
+}
+
+// GetLogRecordTrace fetches the trace a log record belongs to. The spans are
+// queried within the correlation window around the time of the log record,
+// or its observed time if the time is not set.
+//
+// It returns ErrNoTraceContext if the log record has no trace ID, and an
+// error for which IsNotFound reports true if no span of the trace is found.
+//
+// Example:
+//
+//	trace, err := dash0.GetLogRecordTrace(ctx, client, entry.LogRecord, nil)
+//	if err != nil {
+//	    // handle error
+//	}
+//	if span := trace.Span(hex.EncodeToString(*entry.LogRecord.SpanId)); span != nil {
+//	    // span that emitted the log record
+//	}
+func GetLogRecordTrace(ctx context.Context, client Client, record *LogRecord, dataset *string, opts ...CorrelationOption) (*Trace, error) {
+	if record.TraceId == nil || len(*record.TraceId) == 0 {
+		return nil, ErrNoTraceContext
+	}
+	t := time.Unix(0, logRecordUnixNano(record)).UTC()
+	return client.GetTrace(ctx, hex.EncodeToString(*record.TraceId), newCorrelationConfig(opts).timeRange(t, t), dataset)
+}
+
+// GetSpanLogRecords fetches the log records emitted within a span, i.e. the
+// log records with the trace ID and span ID of the span, within the
+// correlation window around the span. The log records are ordered by time.
+//
+// Example:
+//
+//	for node := range trace.DepthFirst() {
+//	    logs, err := dash0.GetSpanLogRecords(ctx, client, node, nil)
+//	    // ...
+//	}
+func GetSpanLogRecords(ctx context.Context, client Client, node *SpanNode, dataset *string, opts ...CorrelationOption) ([]*LogRecordEntry, error) {
+	filter, err := NewFilter().
+		Is(FilterKeyTraceID, hex.EncodeToString(node.Span.TraceId)).
+		Is(FilterKeySpanID, node.SpanID()).
+		Build()
+	if err != nil {
+		return nil, err
+	}
+	return getCorrelatedLogRecords(ctx, client, filter, newCorrelationConfig(opts).timeRange(node.StartTime(), node.EndTime()), dataset)
+}
+
+// GetTraceLogRecords fetches the log records of all spans of a trace, i.e. the
+// log records with the trace ID, within the correlation window around the
+// spans of the trace. The log records are ordered by time. Use NewTimeline to
+// merge them with the spans.
+func GetTraceLogRecords(ctx context.Context, client Client, trace *Trace, dataset *string, opts ...CorrelationOption) ([]*LogRecordEntry, error) {
+	filter, err := NewFilter().Is(FilterKeyTraceID, trace.TraceID).Build()
+	if err != nil {
+		return nil, err
+	}
+	var start, end time.Time
+	for node := range trace.DepthFirst() {
+		if s := node.StartTime(); start.IsZero() || s.Before(start) {
+			start = s
+		}
+		if e := node.EndTime(); e.After(end) {
+			end = e
+		}
+	}
+	return getCorrelatedLogRecords(ctx, client, filter, newCorrelationConfig(opts).timeRange(start, end), dataset)
+}
+
+// getCorrelatedLogRecords fetches all log records matching the filter,
+// ordered by time.
+func getCorrelatedLogRecords(ctx context.Context, client Client, filter FilterCriteria, timeRange TimeReferenceRange, dataset *string) ([]*LogRecordEntry, error) {
+	request := &GetLogRecordsRequest{Dataset: dataset, Filter: &filter, TimeRange: timeRange}
+	var resourceLogs []*ResourceLogs
+	for rl, err := range client.GetLogRecordsIter(ctx, request).All() {
+		if err != nil {
+			return nil, err
+		}
+		resourceLogs = append(resourceLogs, rl)
+	}
+
+	entries := logRecordEntries(resourceLogs)
+	slices.SortStableFunc(entries, func(a, b *LogRecordEntry) int {
+		return cmp.Compare(logRecordTimestamp(a), logRecordTimestamp(b))
+	})
+	return entries, nil
+}
+
+// TimelineEvent is an event of a timeline, either the start of a span or a
+// log record.
+type TimelineEvent struct {
+	Time time.Time
+
+	// Span is the span that starts at Time, or the span that emitted
+	// LogRecord. It is nil for log records of spans that are not part of
+	// the trace.
+	Span *SpanNode
+
+	// LogRecord is the log record of the event, or nil for span starts.
+	LogRecord *LogRecordEntry
+}
+
+// NewTimeline merges the spans of a trace and log records into a single
+// timeline, ordered by time. Spans come before log records at the same time,
+// and spans starting at the same time keep their depth-first order. Log
+// records are attributed to the span with their span ID.
+func NewTimeline(trace *Trace, logRecords []*LogRecordEntry) []TimelineEvent {
+	var events []TimelineEvent
+	for node := range trace.DepthFirst() {
+		events = append(events, TimelineEvent{Time: node.StartTime(), Span: node})
+	}
+	for _, entry := range logRecords {
+		event := TimelineEvent{Time: time.Unix(0, logRecordTimestamp(entry)).UTC(), LogRecord: entry}
+		if id := entry.LogRecord.SpanId; id != nil && len(*id) > 0 {
+			event.Span = trace.Span(hex.EncodeToString(*id))
+		}
+		events = append(events, event)
+	}
+	slices.SortStableFunc(events, func(a, b TimelineEvent) int {
+		return a.Time.Compare(b.Time)
+	})
+	return events
+}
@@ -0,0 +1,128 @@
+package dash0
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// correlationClient serves a trace via GetTrace and log records via
+// GetLogRecordsIter, and records the requests.
+type correlationClient struct {
+	Client
+
+	trace        *Trace
+	resourceLogs []ResourceLogs
+
+	traceID   string
+	timeRange TimeReferenceRange
+	requests  []*GetLogRecordsRequest
+}
+
+func (c *correlationClient) GetTrace(ctx context.Context, traceID string, timeRange TimeReferenceRange, dataset *string) (*Trace, error) {
+	c.traceID, c.timeRange = traceID, timeRange
+	return c.trace, nil
+}
+
+func (c *correlationClient) GetLogRecordsIter(ctx context.Context, request *GetLogRecordsRequest) *Iter[ResourceLogs] {
+	c.requests = append(c.requests, request)
+	return newIter(toPointerSlice(c.resourceLogs), false, nil, nil)
+}
+
+func correlatedLogRecord(body string, spanID string, timeUnixNano string) LogRecord {
+	return LogRecord{
+		TimeUnixNano: timeUnixNano,
+		Body:         &AnyValue{StringValue: String(body)},
+		TraceId:      Ptr(mustDecodeHex(testTraceID)),
+		SpanId:       Ptr(testSpanID(spanID)),
+	}
+}
+
+func correlationTrace() *Trace {
+	return NewTrace([]ResourceSpans{testResourceSpans("checkout", testSpan("a", "", 1000), testSpan("b", "a", 2000))})
+}
+
+func TestGetLogRecordTrace(t *testing.T) {
+	client := &correlationClient{trace: correlationTrace()}
+	record := correlatedLogRecord("failed", "b", "")
+	record.ObservedTimeUnixNano = "1705329000000000000"
+
+	trace, err := GetLogRecordTrace(context.Background(), client, &record, nil, WithCorrelationWindow(time.Minute))
+	if err != nil || trace != client.trace {
+		t.Fatalf("expected trace, got %v (%v)", trace, err)
+	}
+	if client.traceID != testTraceID {
+		t.Errorf("expected trace ID %s, got %s", testTraceID, client.traceID)
+	}
+	from, to := client.timeRange.From.(time.Time), client.timeRange.To.(time.Time)
+	if want := time.Unix(0, 1705329000000000000).Add(-time.Minute); !from.Equal(want) || to.Sub(from) != 2*time.Minute {
+		t.Errorf("expected a minute around the observed time, got %v - %v", from, to)
+	}
+
+	if _, err := GetLogRecordTrace(context.Background(), client, &LogRecord{}, nil); !errors.Is(err, ErrNoTraceContext) {
+		t.Errorf("expected ErrNoTraceContext, got %v", err)
+	}
+}
+
+func TestGetSpanLogRecords(t *testing.T) {
+	client := &correlationClient{resourceLogs: []ResourceLogs{{ScopeLogs: []ScopeLogs{{LogRecords: []LogRecord{
+		correlatedLogRecord("second", "b", "2005"),
+		correlatedLogRecord("first", "b", "2001"),
+	}}}}}}
+	node := correlationTrace().Span(hex.EncodeToString(testSpanID("b")))
+
+	entries, err := GetSpanLogRecords(context.Background(), client, node, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(entries) != 2 || entries[0].LogRecord.Body.String() != "first" {
+		t.Errorf("expected log records ordered by time, got %v", entries)
+	}
+
+	request := client.requests[0]
+	if got := FormatFilter(*request.Filter); !strings.Contains(got, testTraceID) || !strings.Contains(got, "otel.span.id = "+hex.EncodeToString(testSpanID("b"))) {
+		t.Errorf("unexpected filter %s", got)
+	}
+	from, to := request.TimeRange.From.(time.Time), request.TimeRange.To.(time.Time)
+	if !from.Equal(time.Unix(0, 2000).Add(-DefaultCorrelationWindow)) || !to.Equal(time.Unix(0, 2010).Add(DefaultCorrelationWindow)) {
+		t.Errorf("unexpected time range %v - %v", from, to)
+	}
+}
+
+func TestTimeline(t *testing.T) {
+	client := &correlationClient{resourceLogs: []ResourceLogs{{ScopeLogs: []ScopeLogs{{LogRecords: []LogRecord{
+		correlatedLogRecord("in b", "b", "2000"),
+		correlatedLogRecord("in a", "a", "1500"),
+		correlatedLogRecord("unknown span", "x", "500"),
+	}}}}}}
+	trace := correlationTrace()
+
+	entries, err := GetTraceLogRecords(context.Background(), client, trace, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := FormatFilter(*client.requests[0].Filter); got != `otel.trace.id = "`+testTraceID+`"` {
+		t.Errorf("unexpected filter %s", got)
+	}
+
+	var events []string
+	for _, event := range NewTimeline(trace, entries) {
+		var s string
+		switch {
+		case event.LogRecord == nil:
+			s = "span " + event.Span.Span.Name
+		case event.Span == nil:
+			s = "log " + event.LogRecord.LogRecord.Body.String()
+		default:
+			s = "log " + event.LogRecord.LogRecord.Body.String() + " of " + event.Span.Span.Name
+		}
+		events = append(events, s)
+	}
+	want := "log unknown span, span a, log in a of a, span b, log in b of b"
+	if got := strings.Join(events, ", "); got != want {
+		t.Errorf("expected %s, got %s", want, got)
+	}
+}
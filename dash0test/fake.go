@@ -0,0 +1,91 @@
+package dash0test
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/dash0hq/dash0-api-client-go"
+)
+
+// SpansFunc returns a function for MockClient.GetSpansFunc that serves the
+// given resource spans. The filter of each request is evaluated in-process, so
+// only matching spans are returned. The pagination limit applies to resource
+// spans, and the After cursor of a response continues with the next ones.
+// Time ranges are ignored.
+//
+// Example:
+//
+//	mock := &dash0test.MockClient{
+//	    GetSpansFunc: dash0test.SpansFunc(resourceSpans),
+//	}
+func SpansFunc(resourceSpans []dash0.ResourceSpans) func(ctx context.Context, request *dash0.GetSpansRequest) (*dash0.GetSpansResponse, error) {
+	return func(ctx context.Context, request *dash0.GetSpansRequest) (*dash0.GetSpansResponse, error) {
+		matcher, err := compileRequestFilter(request.Filter)
+		if err != nil {
+			return nil, err
+		}
+		items, cursors, err := paginate(matcher.FilterResourceSpans(resourceSpans), request.Pagination)
+		if err != nil {
+			return nil, err
+		}
+		return &dash0.GetSpansResponse{ResourceSpans: items, Cursors: cursors}, nil
+	}
+}
+
+// LogRecordsFunc returns a function for MockClient.GetLogRecordsFunc that
+// serves the given resource logs. The filter of each request is evaluated
+// in-process, so only matching log records are returned. The pagination limit
+// applies to resource logs, and the After cursor of a response continues with
+// the next ones. Time ranges are ignored.
+//
+// Example:
+//
+//	mock := &dash0test.MockClient{
+//	    GetLogRecordsFunc: dash0test.LogRecordsFunc(resourceLogs),
+//	}
+func LogRecordsFunc(resourceLogs []dash0.ResourceLogs) func(ctx context.Context, request *dash0.GetLogRecordsRequest) (*dash0.GetLogRecordsResponse, error) {
+	return func(ctx context.Context, request *dash0.GetLogRecordsRequest) (*dash0.GetLogRecordsResponse, error) {
+		matcher, err := compileRequestFilter(request.Filter)
+		if err != nil {
+			return nil, err
+		}
+		items, cursors, err := paginate(matcher.FilterResourceLogs(resourceLogs), request.Pagination)
+		if err != nil {
+			return nil, err
+		}
+		return &dash0.GetLogRecordsResponse{ResourceLogs: items, Cursors: cursors}, nil
+	}
+}
+
+func compileRequestFilter(filter *dash0.FilterCriteria) (*dash0.FilterMatcher, error) {
+	if filter == nil {
+		return dash0.CompileFilter(nil)
+	}
+	return dash0.CompileFilter(*filter)
+}
+
+// paginate returns the page of items selected by pagination. Cursors are the
+// offsets of the first item of a page.
+func paginate[T any](items []T, pagination *dash0.CursorPagination) ([]T, *dash0.NextCursors, error) {
+	if pagination == nil {
+		return items, nil, nil
+	}
+	offset := 0
+	if pagination.Cursor != nil {
+		n, err := strconv.Atoi(*pagination.Cursor)
+		if err != nil || n < 0 || n > len(items) {
+			return nil, nil, fmt.Errorf("dash0: dash0test: invalid cursor %q", *pagination.Cursor)
+		}
+		offset = n
+	}
+	end := len(items)
+	if pagination.Limit != nil && *pagination.Limit > 0 {
+		end = min(end, offset+int(*pagination.Limit))
+	}
+	if end == len(items) {
+		return items[offset:end], nil, nil
+	}
+	after := strconv.Itoa(end)
+	return items[offset:end], &dash0.NextCursors{After: &after}, nil
+}
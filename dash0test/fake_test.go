@@ -0,0 +1,209 @@
+package dash0test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"github.com/dash0hq/dash0-api-client-go"
+)
+
+// fakeServer serves the fakes over HTTP, so that the iterators of a real
+// client page through them. Errors of the fakes become 400 responses.
+func fakeServer(t *testing.T, spans []dash0.ResourceSpans, logs []dash0.ResourceLogs) (dash0.Client, *int) {
+	t.Helper()
+	getSpans := SpansFunc(spans)
+	getLogRecords := LogRecordsFunc(logs)
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		var (
+			response any
+			err      error
+		)
+		switch r.URL.Path {
+		case "/api/spans":
+			var request dash0.GetSpansRequest
+			if err = json.NewDecoder(r.Body).Decode(&request); err == nil {
+				response, err = getSpans(r.Context(), &request)
+			}
+		case "/api/logs":
+			var request dash0.GetLogRecordsRequest
+			if err = json.NewDecoder(r.Body).Decode(&request); err == nil {
+				response, err = getLogRecords(r.Context(), &request)
+			}
+		default:
+			t.Errorf("unexpected path %s", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+			return
+		}
+		_ = json.NewEncoder(w).Encode(response)
+	}))
+	t.Cleanup(server.Close)
+
+	client, err := dash0.NewClient(dash0.WithApiUrl(server.URL), dash0.WithAuthToken(TestAuthToken))
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	return client, &requests
+}
+
+func resourceSpans(service string, names ...string) dash0.ResourceSpans {
+	rs := dash0.ResourceSpans{
+		Resource:   dash0.Resource{Attributes: []dash0.KeyValue{{Key: "service.name", Value: dash0.AnyValue{StringValue: dash0.String(service)}}}},
+		ScopeSpans: []dash0.ScopeSpans{{}},
+	}
+	for _, name := range names {
+		rs.ScopeSpans[0].Spans = append(rs.ScopeSpans[0].Spans, dash0.Span{Name: name})
+	}
+	return rs
+}
+
+func resourceLogs(service string, bodies ...string) dash0.ResourceLogs {
+	rl := dash0.ResourceLogs{
+		Resource:  dash0.Resource{Attributes: []dash0.KeyValue{{Key: "service.name", Value: dash0.AnyValue{StringValue: dash0.String(service)}}}},
+		ScopeLogs: []dash0.ScopeLogs{{}},
+	}
+	for _, body := range bodies {
+		rl.ScopeLogs[0].LogRecords = append(rl.ScopeLogs[0].LogRecords, dash0.LogRecord{Body: &dash0.AnyValue{StringValue: dash0.String(body)}})
+	}
+	return rl
+}
+
+func TestSpansFunc(t *testing.T) {
+	spans := []dash0.ResourceSpans{
+		resourceSpans("checkout", "GET /cart", "POST /order"),
+		resourceSpans("payment", "charge"),
+		resourceSpans("checkout", "GET /health"),
+		resourceSpans("checkout", "GET /order"),
+	}
+	timeRange := dash0.TimeReferenceRange{From: "now-1h", To: "now"}
+
+	t.Run("pages through matching spans", func(t *testing.T) {
+		client, requests := fakeServer(t, spans, nil)
+		filter, err := dash0.NewFilter().Is("service.name", "checkout").Build()
+		if err != nil {
+			t.Fatalf("failed to build filter: %v", err)
+		}
+
+		var names []string
+		for rs, err := range client.GetSpansIter(context.Background(), &dash0.GetSpansRequest{
+			TimeRange:  timeRange,
+			Filter:     &filter,
+			Pagination: &dash0.CursorPagination{Limit: dash0.Int64(2)},
+		}).All() {
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			for _, span := range rs.ScopeSpans[0].Spans {
+				names = append(names, span.Name)
+			}
+		}
+		if want := []string{"GET /cart", "POST /order", "GET /health", "GET /order"}; !slices.Equal(names, want) {
+			t.Errorf("expected %v, got %v", want, names)
+		}
+		if *requests != 2 {
+			t.Errorf("expected 2 requests, got %d", *requests)
+		}
+	})
+
+	t.Run("returns all spans without pagination", func(t *testing.T) {
+		resp, err := SpansFunc(spans)(context.Background(), &dash0.GetSpansRequest{TimeRange: timeRange})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(resp.ResourceSpans) != len(spans) || resp.Cursors != nil {
+			t.Errorf("unexpected response: %+v", resp)
+		}
+	})
+
+	t.Run("surfaces invalid filters", func(t *testing.T) {
+		client, _ := fakeServer(t, spans, nil)
+		filter := dash0.FilterCriteria{{Key: "service.name", Operator: dash0.AttributeFilterOperatorIsOneOf}}
+
+		var iterErr error
+		for _, err := range client.GetSpansIter(context.Background(), &dash0.GetSpansRequest{TimeRange: timeRange, Filter: &filter}).All() {
+			iterErr = err
+		}
+		if iterErr == nil {
+			t.Error("expected iteration error")
+		}
+	})
+
+	t.Run("rejects invalid cursors", func(t *testing.T) {
+		request := (&dash0.GetSpansRequest{TimeRange: timeRange}).WithCursor("10")
+		if _, err := SpansFunc(spans)(context.Background(), request); err == nil {
+			t.Error("expected error")
+		}
+	})
+}
+
+func TestLogRecordsFunc(t *testing.T) {
+	logs := []dash0.ResourceLogs{
+		resourceLogs("checkout", "order placed"),
+		resourceLogs("payment", "charge failed"),
+		resourceLogs("checkout", "cart updated", "cart emptied"),
+	}
+	timeRange := dash0.TimeReferenceRange{From: "now-1h", To: "now"}
+
+	t.Run("pages through matching log records", func(t *testing.T) {
+		client, requests := fakeServer(t, nil, logs)
+		filter, err := dash0.NewFilter().Is("service.name", "checkout").Build()
+		if err != nil {
+			t.Fatalf("failed to build filter: %v", err)
+		}
+
+		var bodies []string
+		for rl, err := range client.GetLogRecordsIter(context.Background(), &dash0.GetLogRecordsRequest{
+			TimeRange:  timeRange,
+			Filter:     &filter,
+			Pagination: &dash0.CursorPagination{Limit: dash0.Int64(1)},
+		}).All() {
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			for _, record := range rl.ScopeLogs[0].LogRecords {
+				bodies = append(bodies, *record.Body.StringValue)
+			}
+		}
+		if want := []string{"order placed", "cart updated", "cart emptied"}; !slices.Equal(bodies, want) {
+			t.Errorf("expected %v, got %v", want, bodies)
+		}
+		if *requests != 2 {
+			t.Errorf("expected 2 requests, got %d", *requests)
+		}
+	})
+
+	t.Run("surfaces invalid filters", func(t *testing.T) {
+		client, _ := fakeServer(t, nil, logs)
+		filter := dash0.FilterCriteria{{Key: "service.name", Operator: dash0.AttributeFilterOperatorIsOneOf}}
+
+		var iterErr error
+		for _, err := range client.GetLogRecordsIter(context.Background(), &dash0.GetLogRecordsRequest{TimeRange: timeRange, Filter: &filter}).All() {
+			iterErr = err
+		}
+		if iterErr == nil {
+			t.Error("expected iteration error")
+		}
+	})
+
+	t.Run("stops at the last page", func(t *testing.T) {
+		request := &dash0.GetLogRecordsRequest{TimeRange: timeRange, Pagination: &dash0.CursorPagination{Limit: dash0.Int64(2)}}
+		resp, err := LogRecordsFunc(logs)(context.Background(), request.WithCursor("2"))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(resp.ResourceLogs) != 1 || resp.Cursors != nil {
+			t.Errorf("unexpected response: %+v", resp)
+		}
+	})
+}
@@ -59,12 +59,23 @@ type MockClient struct {
 	ListSamplingRulesIterFunc func(ctx context.Context, dataset *string) *dash0.Iter[dash0.SamplingDefinition]
 
 	// Spans
-	GetSpansFunc     func(ctx context.Context, request *dash0.GetSpansRequest) (*dash0.GetSpansResponse, error)
-	GetSpansIterFunc func(ctx context.Context, request *dash0.GetSpansRequest) *dash0.Iter[dash0.ResourceSpans]
+	GetSpansFunc              func(ctx context.Context, request *dash0.GetSpansRequest) (*dash0.GetSpansResponse, error)
+	GetSpansIterFunc          func(ctx context.Context, request *dash0.GetSpansRequest) *dash0.Iter[dash0.ResourceSpans]
+	GetSpansIterBackwardFunc  func(ctx context.Context, request *dash0.GetSpansRequest) *dash0.Iter[dash0.ResourceSpans]
+	GetSpansPagesFunc         func(ctx context.Context, request *dash0.GetSpansRequest) *dash0.Iter[dash0.GetSpansResponse]
+	GetSpansPagesBackwardFunc func(ctx context.Context, request *dash0.GetSpansRequest) *dash0.Iter[dash0.GetSpansResponse]
+	GetSpansWindowedIterFunc  func(ctx context.Context, request *dash0.GetSpansRequest, opts ...dash0.WindowOption) *dash0.Iter[dash0.ResourceSpans]
+	GetTraceFunc              func(ctx context.Context, traceID string, timeRange dash0.TimeReferenceRange, dataset *string) (*dash0.Trace, error)
+	TailSpansFunc             func(ctx context.Context, request *dash0.GetSpansRequest, fn func(*dash0.SpanEntry) error, opts ...dash0.TailOption) error
 
 	// Logs
-	GetLogRecordsFunc     func(ctx context.Context, request *dash0.GetLogRecordsRequest) (*dash0.GetLogRecordsResponse, error)
-	GetLogRecordsIterFunc func(ctx context.Context, request *dash0.GetLogRecordsRequest) *dash0.Iter[dash0.ResourceLogs]
+	GetLogRecordsFunc              func(ctx context.Context, request *dash0.GetLogRecordsRequest) (*dash0.GetLogRecordsResponse, error)
+	GetLogRecordsIterFunc          func(ctx context.Context, request *dash0.GetLogRecordsRequest) *dash0.Iter[dash0.ResourceLogs]
+	GetLogRecordsIterBackwardFunc  func(ctx context.Context, request *dash0.GetLogRecordsRequest) *dash0.Iter[dash0.ResourceLogs]
+	GetLogRecordsPagesFunc         func(ctx context.Context, request *dash0.GetLogRecordsRequest) *dash0.Iter[dash0.GetLogRecordsResponse]
+	GetLogRecordsPagesBackwardFunc func(ctx context.Context, request *dash0.GetLogRecordsRequest) *dash0.Iter[dash0.GetLogRecordsResponse]
+	GetLogRecordsWindowedIterFunc  func(ctx context.Context, request *dash0.GetLogRecordsRequest, opts ...dash0.WindowOption) *dash0.Iter[dash0.ResourceLogs]
+	TailLogRecordsFunc             func(ctx context.Context, request *dash0.GetLogRecordsRequest, fn func(*dash0.LogRecordEntry) error, opts ...dash0.TailOption) error
 
 	// Import
 	ImportCheckRuleFunc      func(ctx context.Context, rule *dash0.PostApiImportCheckRuleJSONRequestBody, dataset *string) (*dash0.PrometheusAlertRule, error)
@@ -312,6 +323,48 @@ func (m *MockClient) GetSpansIter(ctx context.Context, request *dash0.GetSpansRe
 	return nil
 }
 
+func (m *MockClient) GetSpansIterBackward(ctx context.Context, request *dash0.GetSpansRequest) *dash0.Iter[dash0.ResourceSpans] {
+	if m.GetSpansIterBackwardFunc != nil {
+		return m.GetSpansIterBackwardFunc(ctx, request)
+	}
+	return nil
+}
+
+func (m *MockClient) GetSpansPages(ctx context.Context, request *dash0.GetSpansRequest) *dash0.Iter[dash0.GetSpansResponse] {
+	if m.GetSpansPagesFunc != nil {
+		return m.GetSpansPagesFunc(ctx, request)
+	}
+	return nil
+}
+
+func (m *MockClient) GetSpansPagesBackward(ctx context.Context, request *dash0.GetSpansRequest) *dash0.Iter[dash0.GetSpansResponse] {
+	if m.GetSpansPagesBackwardFunc != nil {
+		return m.GetSpansPagesBackwardFunc(ctx, request)
+	}
+	return nil
+}
+
+func (m *MockClient) GetSpansWindowedIter(ctx context.Context, request *dash0.GetSpansRequest, opts ...dash0.WindowOption) *dash0.Iter[dash0.ResourceSpans] {
+	if m.GetSpansWindowedIterFunc != nil {
+		return m.GetSpansWindowedIterFunc(ctx, request, opts...)
+	}
+	return nil
+}
+
+func (m *MockClient) GetTrace(ctx context.Context, traceID string, timeRange dash0.TimeReferenceRange, dataset *string) (*dash0.Trace, error) {
+	if m.GetTraceFunc != nil {
+		return m.GetTraceFunc(ctx, traceID, timeRange, dataset)
+	}
+	return nil, nil
+}
+
+func (m *MockClient) TailSpans(ctx context.Context, request *dash0.GetSpansRequest, fn func(*dash0.SpanEntry) error, opts ...dash0.TailOption) error {
+	if m.TailSpansFunc != nil {
+		return m.TailSpansFunc(ctx, request, fn, opts...)
+	}
+	return nil
+}
+
 // Logs
 
 func (m *MockClient) GetLogRecords(ctx context.Context, request *dash0.GetLogRecordsRequest) (*dash0.GetLogRecordsResponse, error) {
@@ -328,6 +381,41 @@ func (m *MockClient) GetLogRecordsIter(ctx context.Context, request *dash0.GetLo
 	return nil
 }
 
+func (m *MockClient) GetLogRecordsIterBackward(ctx context.Context, request *dash0.GetLogRecordsRequest) *dash0.Iter[dash0.ResourceLogs] {
+	if m.GetLogRecordsIterBackwardFunc != nil {
+		return m.GetLogRecordsIterBackwardFunc(ctx, request)
+	}
+	return nil
+}
+
+func (m *MockClient) GetLogRecordsPages(ctx context.Context, request *dash0.GetLogRecordsRequest) *dash0.Iter[dash0.GetLogRecordsResponse] {
+	if m.GetLogRecordsPagesFunc != nil {
+		return m.GetLogRecordsPagesFunc(ctx, request)
+	}
+	return nil
+}
+
+func (m *MockClient) GetLogRecordsPagesBackward(ctx context.Context, request *dash0.GetLogRecordsRequest) *dash0.Iter[dash0.GetLogRecordsResponse] {
+	if m.GetLogRecordsPagesBackwardFunc != nil {
+		return m.GetLogRecordsPagesBackwardFunc(ctx, request)
+	}
+	return nil
+}
+
+func (m *MockClient) GetLogRecordsWindowedIter(ctx context.Context, request *dash0.GetLogRecordsRequest, opts ...dash0.WindowOption) *dash0.Iter[dash0.ResourceLogs] {
+	if m.GetLogRecordsWindowedIterFunc != nil {
+		return m.GetLogRecordsWindowedIterFunc(ctx, request, opts...)
+	}
+	return nil
+}
+
+func (m *MockClient) TailLogRecords(ctx context.Context, request *dash0.GetLogRecordsRequest, fn func(*dash0.LogRecordEntry) error, opts ...dash0.TailOption) error {
+	if m.TailLogRecordsFunc != nil {
+		return m.TailLogRecordsFunc(ctx, request, fn, opts...)
+	}
+	return nil
+}
+
 // Import
 
 func (m *MockClient) ImportCheckRule(ctx context.Context, rule *dash0.PostApiImportCheckRuleJSONRequestBody, dataset *string) (*dash0.PrometheusAlertRule, error) {
@@ -0,0 +1,290 @@
+// Package diff computes semantic differences between Dash0 asset definitions.
+//
+// Definitions are compared in their JSON form, so the free-form
+// DashboardDefinition.Spec map is handled the same way as the typed specs of
+// check rules, synthetic checks, views and sampling rules. Fields that are
+// managed by the Dash0 API, such as DashboardMetadata.CreatedAt, are ignored.
+//
+// Example:
+//
+//	changes, err := diff.Compare(current, desired)
+//	if err != nil {
+//	    // handle error
+//	}
+//	for _, c := range changes {
+//	    fmt.Println(c) // e.g. replace /spec/duration: "1h" -> "30m"
+//	}
+package diff
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/dash0hq/dash0-api-client-go"
+)
+
+// Definition is the set of asset definitions that can be compared.
+type Definition interface {
+	dash0.DashboardDefinition |
+		dash0.PrometheusAlertRule |
+		dash0.SyntheticCheckDefinition |
+		dash0.ViewDefinition |
+		dash0.SamplingDefinition
+}
+
+// Operation is the kind of a field-level change.
+// The values follow the JSON Patch (RFC 6902) vocabulary.
+type Operation string
+
+const (
+	OpAdd     Operation = "add"
+	OpRemove  Operation = "remove"
+	OpReplace Operation = "replace"
+)
+
+// Change is a single field-level difference between two definitions.
+type Change struct {
+	// Op is the kind of change.
+	Op Operation
+
+	// Path is the JSON pointer (RFC 6901) of the changed field.
+	Path string
+
+	// From is the old value in its generic JSON form. It is nil for additions.
+	From any
+
+	// To is the new value in its generic JSON form. It is nil for removals.
+	To any
+}
+
+// String returns a one-line description of the change.
+func (c Change) String() string {
+	switch c.Op {
+	case OpAdd:
+		return fmt.Sprintf("add %s: %s", c.Path, formatValue(c.To))
+	case OpRemove:
+		return fmt.Sprintf("remove %s: %s", c.Path, formatValue(c.From))
+	default:
+		return fmt.Sprintf("replace %s: %s -> %s", c.Path, formatValue(c.From), formatValue(c.To))
+	}
+}
+
+// serverManagedFields lists, per definition type, the JSON pointers of fields
+// that are set by the Dash0 API and are ignored when comparing.
+var serverManagedFields = map[reflect.Type][]string{
+	reflect.TypeFor[dash0.DashboardDefinition](): {
+		"/metadata/createdAt",
+		"/metadata/updatedAt",
+		"/metadata/version",
+		"/metadata/dash0Extensions/id",
+		"/metadata/dash0Extensions/origin",
+		"/metadata/dash0Extensions/createdBy",
+	},
+	reflect.TypeFor[dash0.PrometheusAlertRule](): {
+		"/id",
+	},
+	reflect.TypeFor[dash0.SyntheticCheckDefinition](): {
+		"/metadata/labels/dash0.com~1id",
+		"/metadata/labels/dash0.com~1origin",
+		"/metadata/labels/dash0.com~1version",
+	},
+	reflect.TypeFor[dash0.ViewDefinition](): {
+		"/metadata/labels/dash0.com~1id",
+		"/metadata/labels/dash0.com~1origin",
+		"/metadata/labels/dash0.com~1version",
+	},
+	reflect.TypeFor[dash0.SamplingDefinition](): {
+		"/metadata/labels/dash0.com~1id",
+		"/metadata/labels/dash0.com~1origin",
+		"/metadata/labels/dash0.com~1version",
+	},
+}
+
+// ServerManagedFields returns the JSON pointers of the fields that are ignored
+// for the given definition type.
+func ServerManagedFields[T Definition]() []string {
+	return slices.Clone(serverManagedFields[reflect.TypeFor[T]()])
+}
+
+// Compare returns the field-level changes that turn from into to.
+// Changes are ordered depth-first, with object members sorted by key.
+// A nil definition is treated as empty.
+func Compare[T Definition](from, to *T, opts ...Option) ([]Change, error) {
+	cfg := newConfig(opts)
+	a, err := normalize[T](from, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("dash0: diff: %w", err)
+	}
+	b, err := normalize[T](to, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("dash0: diff: %w", err)
+	}
+
+	var changes []Change
+	compareValues("", a, b, &changes)
+	return changes, nil
+}
+
+// Equal reports whether two definitions are equal once server-managed fields are ignored.
+func Equal[T Definition](a, b *T, opts ...Option) (bool, error) {
+	changes, err := Compare(a, b, opts...)
+	if err != nil {
+		return false, err
+	}
+	return len(changes) == 0, nil
+}
+
+// normalize converts a definition into its generic JSON form, without
+// server-managed and ignored fields and without null object members.
+func normalize[T Definition](definition *T, cfg *config) (any, error) {
+	if definition == nil {
+		return map[string]any{}, nil
+	}
+	data, err := json.Marshal(definition)
+	if err != nil {
+		return nil, err
+	}
+	var v any
+	if err := json.Unmarshal(data, &v); err != nil {
+		return nil, err
+	}
+	v = dropNulls(v)
+
+	ignored := slices.Concat(serverManagedFields[reflect.TypeFor[T]()], cfg.ignoredPaths)
+	for _, pointer := range ignored {
+		removePath(v, parsePointer(pointer))
+	}
+	return v, nil
+}
+
+// dropNulls removes null members from all objects, so that absent and null
+// fields compare equal.
+func dropNulls(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		for k, child := range v {
+			if child == nil {
+				delete(v, k)
+				continue
+			}
+			v[k] = dropNulls(child)
+		}
+	case []any:
+		for i, child := range v {
+			v[i] = dropNulls(child)
+		}
+	}
+	return v
+}
+
+// removePath deletes the member at path and drops parent objects left empty.
+func removePath(v any, path []string) {
+	obj, ok := v.(map[string]any)
+	if !ok || len(path) == 0 {
+		return
+	}
+	if len(path) == 1 {
+		delete(obj, path[0])
+		return
+	}
+	child, ok := obj[path[0]]
+	if !ok {
+		return
+	}
+	removePath(child, path[1:])
+	if m, ok := child.(map[string]any); ok && len(m) == 0 {
+		delete(obj, path[0])
+	}
+}
+
+// compareValues appends the changes between a and b at path to changes.
+func compareValues(path string, a, b any, changes *[]Change) {
+	switch a := a.(type) {
+	case map[string]any:
+		if b, ok := b.(map[string]any); ok {
+			compareObjects(path, a, b, changes)
+			return
+		}
+	case []any:
+		if b, ok := b.([]any); ok {
+			compareArrays(path, a, b, changes)
+			return
+		}
+	}
+	if !reflect.DeepEqual(a, b) {
+		*changes = append(*changes, Change{Op: OpReplace, Path: path, From: a, To: b})
+	}
+}
+
+func compareObjects(path string, a, b map[string]any, changes *[]Change) {
+	keys := make([]string, 0, len(a)+len(b))
+	for k := range a {
+		keys = append(keys, k)
+	}
+	for k := range b {
+		if _, ok := a[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		childPath := path + "/" + escapePointerToken(k)
+		av, inA := a[k]
+		bv, inB := b[k]
+		switch {
+		case !inA:
+			*changes = append(*changes, Change{Op: OpAdd, Path: childPath, To: bv})
+		case !inB:
+			*changes = append(*changes, Change{Op: OpRemove, Path: childPath, From: av})
+		default:
+			compareValues(childPath, av, bv, changes)
+		}
+	}
+}
+
+func compareArrays(path string, a, b []any, changes *[]Change) {
+	n := min(len(a), len(b))
+	for i := 0; i < n; i++ {
+		compareValues(path+"/"+strconv.Itoa(i), a[i], b[i], changes)
+	}
+	for i := n; i < len(b); i++ {
+		*changes = append(*changes, Change{Op: OpAdd, Path: path + "/" + strconv.Itoa(i), To: b[i]})
+	}
+	for i := n; i < len(a); i++ {
+		*changes = append(*changes, Change{Op: OpRemove, Path: path + "/" + strconv.Itoa(i), From: a[i]})
+	}
+}
+
+// escapePointerToken escapes a JSON pointer reference token as per RFC 6901.
+func escapePointerToken(s string) string {
+	s = strings.ReplaceAll(s, "~", "~0")
+	return strings.ReplaceAll(s, "/", "~1")
+}
+
+// parsePointer splits a JSON pointer into its unescaped reference tokens.
+func parsePointer(pointer string) []string {
+	if pointer == "" {
+		return nil
+	}
+	tokens := strings.Split(strings.TrimPrefix(pointer, "/"), "/")
+	for i, t := range tokens {
+		t = strings.ReplaceAll(t, "~1", "/")
+		tokens[i] = strings.ReplaceAll(t, "~0", "~")
+	}
+	return tokens
+}
+
+// formatValue renders a generic JSON value compactly.
+func formatValue(v any) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(data)
+}
@@ -0,0 +1,259 @@
+package diff
+
+import (
+	"math/rand/v2"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dash0hq/dash0-api-client-go"
+)
+
+func TestCompare(t *testing.T) {
+	t.Run("ignores server-managed fields", func(t *testing.T) {
+		now := time.Now()
+		a := &dash0.DashboardDefinition{
+			Metadata: dash0.DashboardMetadata{Name: "checkout"},
+			Spec:     map[string]interface{}{"duration": "1h"},
+		}
+		b := &dash0.DashboardDefinition{
+			Metadata: dash0.DashboardMetadata{
+				Name:            "checkout",
+				CreatedAt:       &now,
+				UpdatedAt:       &now,
+				Version:         dash0.Int64(3),
+				Dash0Extensions: &dash0.DashboardMetadataExtensions{Id: dash0.Ptr("server-id")},
+			},
+			Spec: map[string]interface{}{"duration": "1h"},
+		}
+
+		changes, err := Compare(a, b)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(changes) != 0 {
+			t.Errorf("expected no changes, got %v", changes)
+		}
+	})
+
+	t.Run("reports changes in free-form spec with JSON pointers", func(t *testing.T) {
+		a := &dash0.DashboardDefinition{
+			Spec: map[string]interface{}{
+				"duration": "1h",
+				"panels": map[string]interface{}{
+					"a/b": map[string]interface{}{"kind": "Panel"},
+				},
+				"variables": []interface{}{"x", "y"},
+			},
+		}
+		b := &dash0.DashboardDefinition{
+			Spec: map[string]interface{}{
+				"duration": "30m",
+				"panels": map[string]interface{}{
+					"a/b": map[string]interface{}{"kind": "Panel"},
+					"c~d": map[string]interface{}{"kind": "Panel"},
+				},
+				"variables": []interface{}{"x"},
+			},
+		}
+
+		changes, err := Compare(a, b)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		want := []struct {
+			op   Operation
+			path string
+		}{
+			{OpReplace, "/spec/duration"},
+			{OpAdd, "/spec/panels/c~0d"},
+			{OpRemove, "/spec/variables/1"},
+		}
+		if len(changes) != len(want) {
+			t.Fatalf("expected %d changes, got %v", len(want), changes)
+		}
+		for i, w := range want {
+			if changes[i].Op != w.op || changes[i].Path != w.path {
+				t.Errorf("change %d: expected %s %s, got %s", i, w.op, w.path, changes[i])
+			}
+		}
+		if got := changes[0].String(); got != `replace /spec/duration: "1h" -> "30m"` {
+			t.Errorf("unexpected string: %s", got)
+		}
+	})
+
+	t.Run("compares typed specs", func(t *testing.T) {
+		a := &dash0.PrometheusAlertRule{Id: dash0.Ptr("a"), Name: "high error rate", Expression: "up == 0"}
+		b := &dash0.PrometheusAlertRule{Id: dash0.Ptr("b"), Name: "high error rate", Expression: "up == 1", For: dash0.Ptr("5m")}
+
+		changes, err := Compare(a, b)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(changes) != 2 || changes[0].Path != "/expression" || changes[1].Path != "/for" {
+			t.Errorf("unexpected changes: %v", changes)
+		}
+	})
+
+	t.Run("ignores labels managed by the server", func(t *testing.T) {
+		a := &dash0.ViewDefinition{Metadata: dash0.ViewMetadata{Name: "errors"}}
+		b := &dash0.ViewDefinition{Metadata: dash0.ViewMetadata{
+			Name:   "errors",
+			Labels: &dash0.ViewLabels{Dash0Comid: dash0.Ptr("id"), Dash0Comversion: dash0.Ptr("2")},
+		}}
+
+		equal, err := Equal(a, b)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !equal {
+			t.Error("expected views to be equal")
+		}
+	})
+
+	t.Run("supports additional ignored paths", func(t *testing.T) {
+		a := &dash0.PrometheusAlertRule{Name: "a", Description: dash0.Ptr("old")}
+		b := &dash0.PrometheusAlertRule{Name: "a", Description: dash0.Ptr("new")}
+
+		equal, err := Equal(a, b, WithIgnoredPaths("/description"))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !equal {
+			t.Error("expected rules to be equal")
+		}
+	})
+}
+
+func TestUnified(t *testing.T) {
+	t.Run("renders a unified diff", func(t *testing.T) {
+		a := &dash0.PrometheusAlertRule{Name: "a", Expression: "up == 0"}
+		b := &dash0.PrometheusAlertRule{Name: "a", Expression: "up == 1"}
+
+		text, err := Unified(a, b)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		want := strings.Join([]string{
+			"--- current",
+			"+++ desired",
+			"@@ -1,4 +1,4 @@",
+			" {",
+			`-  "expression": "up == 0",`,
+			`+  "expression": "up == 1",`,
+			`   "name": "a"`,
+			" }",
+			"",
+		}, "\n")
+		if text != want {
+			t.Errorf("unexpected diff:\n%s\nwant:\n%s", text, want)
+		}
+	})
+
+	t.Run("returns empty string without differences", func(t *testing.T) {
+		a := &dash0.PrometheusAlertRule{Name: "a"}
+
+		text, err := Unified(a, a)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if text != "" {
+			t.Errorf("expected empty diff, got:\n%s", text)
+		}
+	})
+
+	t.Run("splits distant changes into hunks", func(t *testing.T) {
+		a := make([]string, 20)
+		b := make([]string, 20)
+		for i := range a {
+			a[i] = string(rune('a' + i))
+			b[i] = a[i]
+		}
+		b[1] = "X"
+		b[18] = "Y"
+
+		text := unified(a, b, newConfig([]Option{WithContextLines(1)}))
+		if strings.Count(text, "@@ -") != 2 {
+			t.Errorf("expected 2 hunks, got:\n%s", text)
+		}
+		if !strings.Contains(text, "@@ -1,3 +1,3 @@") || !strings.Contains(text, "@@ -18,3 +18,3 @@") {
+			t.Errorf("unexpected hunk headers:\n%s", text)
+		}
+	})
+}
+
+func TestEditScript(t *testing.T) {
+	// apply reconstructs both sides of an edit script and counts its edits.
+	apply := func(edits []edit) (a, b []string, changes int) {
+		for _, e := range edits {
+			if e.op != '+' {
+				a = append(a, e.line)
+			}
+			if e.op != '-' {
+				b = append(b, e.line)
+			}
+			if e.op != ' ' {
+				changes++
+			}
+		}
+		return a, b, changes
+	}
+
+	t.Run("computes minimal edit scripts", func(t *testing.T) {
+		rng := rand.New(rand.NewPCG(1, 2))
+		for range 500 {
+			a := make([]string, rng.IntN(12))
+			for i := range a {
+				a[i] = string(rune('a' + rng.IntN(4)))
+			}
+			b := make([]string, rng.IntN(12))
+			for i := range b {
+				b[i] = string(rune('a' + rng.IntN(4)))
+			}
+
+			gotA, gotB, changes := apply(editScript(a, b))
+			if !slices.Equal(gotA, a) || !slices.Equal(gotB, b) {
+				t.Fatalf("edit script of %q and %q does not reproduce the inputs: %q, %q", a, b, gotA, gotB)
+			}
+			if want := len(a) + len(b) - 2*lcsLength(a, b); changes != want {
+				t.Fatalf("edit script of %q and %q has %d changes, want %d", a, b, changes, want)
+			}
+		}
+	})
+
+	t.Run("handles large inputs with few changes", func(t *testing.T) {
+		a := make([]string, 100_000)
+		for i := range a {
+			a[i] = strconv.Itoa(i)
+		}
+		b := slices.Clone(a)
+		b[10] = "changed"
+		b = slices.Delete(b, 50_000, 50_010)
+
+		_, _, changes := apply(editScript(a, b))
+		if changes != 12 {
+			t.Errorf("expected 12 changes, got %d", changes)
+		}
+	})
+}
+
+// lcsLength returns the length of the longest common subsequence of a and b.
+func lcsLength(a, b []string) int {
+	prev := make([]int, len(b)+1)
+	for i := range a {
+		cur := make([]int, len(b)+1)
+		for j := range b {
+			if a[i] == b[j] {
+				cur[j+1] = prev[j] + 1
+			} else {
+				cur[j+1] = max(prev[j+1], cur[j])
+			}
+		}
+		prev = cur
+	}
+	return prev[len(b)]
+}
@@ -0,0 +1,55 @@
+package diff
+
+// Option configures a comparison.
+type Option func(*config)
+
+type config struct {
+	ignoredPaths []string
+	fromLabel    string
+	toLabel      string
+	contextLines int
+}
+
+func newConfig(opts []Option) *config {
+	cfg := &config{
+		fromLabel:    "current",
+		toLabel:      "desired",
+		contextLines: 3,
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	if cfg.contextLines < 0 {
+		cfg.contextLines = 0
+	}
+	return cfg
+}
+
+// WithIgnoredPaths ignores the fields at the given JSON pointers (RFC 6901)
+// in addition to the server-managed fields.
+//
+// Example:
+//
+//	diff.Compare(current, desired, diff.WithIgnoredPaths("/metadata/annotations"))
+func WithIgnoredPaths(pointers ...string) Option {
+	return func(c *config) {
+		c.ignoredPaths = append(c.ignoredPaths, pointers...)
+	}
+}
+
+// WithLabels sets the file labels used in the header of a unified diff.
+// Default is "current" and "desired".
+func WithLabels(from, to string) Option {
+	return func(c *config) {
+		c.fromLabel = from
+		c.toLabel = to
+	}
+}
+
+// WithContextLines sets the number of unchanged lines shown around each change
+// in a unified diff. Default is 3.
+func WithContextLines(n int) Option {
+	return func(c *config) {
+		c.contextLines = n
+	}
+}
@@ -0,0 +1,236 @@
+package diff
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Unified renders the differences between two definitions as a unified diff of
+// their indented JSON form, suitable for code review. Server-managed fields are
+// omitted. An empty string is returned when there are no differences.
+//
+// Example:
+//
+//	text, err := diff.Unified(current, desired, diff.WithLabels("dash0", "dashboards/checkout.json"))
+func Unified[T Definition](from, to *T, opts ...Option) (string, error) {
+	cfg := newConfig(opts)
+	a, err := normalize[T](from, cfg)
+	if err != nil {
+		return "", fmt.Errorf("dash0: diff: %w", err)
+	}
+	b, err := normalize[T](to, cfg)
+	if err != nil {
+		return "", fmt.Errorf("dash0: diff: %w", err)
+	}
+
+	aLines, err := jsonLines(a)
+	if err != nil {
+		return "", fmt.Errorf("dash0: diff: %w", err)
+	}
+	bLines, err := jsonLines(b)
+	if err != nil {
+		return "", fmt.Errorf("dash0: diff: %w", err)
+	}
+	return unified(aLines, bLines, cfg), nil
+}
+
+// jsonLines renders a generic JSON value as indented lines.
+// Object members are sorted by key, which keeps the output stable.
+func jsonLines(v any) ([]string, error) {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return strings.Split(string(data), "\n"), nil
+}
+
+// edit is a single line of an edit script.
+type edit struct {
+	op   byte // ' ', '-' or '+'
+	line string
+}
+
+// editScript computes a minimal line-based edit script from a to b with
+// the linear-space variant of Myers' O(ND) difference algorithm, so that
+// large definitions with few changes are compared quickly.
+func editScript(a, b []string) []edit {
+	return appendEdits(make([]edit, 0, len(a)+len(b)), a, b)
+}
+
+// appendEdits appends the edit script from a to b. It splits the problem at
+// the middle snake of the shortest edit path and recurses on both halves.
+func appendEdits(edits []edit, a, b []string) []edit {
+	// Common prefix and suffix do not need to take part in the search.
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+	am := a[prefix : len(a)-suffix]
+	bm := b[prefix : len(b)-suffix]
+
+	for _, line := range a[:prefix] {
+		edits = append(edits, edit{' ', line})
+	}
+	switch {
+	case len(am) == 0:
+		for _, line := range bm {
+			edits = append(edits, edit{'+', line})
+		}
+	case len(bm) == 0:
+		for _, line := range am {
+			edits = append(edits, edit{'-', line})
+		}
+	default:
+		// Both sides differ in their first and last line, so the edit
+		// distance is at least 2 and both halves are smaller problems.
+		x, y, u, v := middleSnake(am, bm)
+		edits = appendEdits(edits, am[:x], bm[:y])
+		for _, line := range am[x:u] {
+			edits = append(edits, edit{' ', line})
+		}
+		edits = appendEdits(edits, am[u:], bm[v:])
+	}
+	for _, line := range a[len(a)-suffix:] {
+		edits = append(edits, edit{' ', line})
+	}
+	return edits
+}
+
+// middleSnake returns the start (x, y) and end (u, v) of the middle snake of
+// a shortest edit path from a to b, found by searching forward from the start
+// and backward from the end at the same time until the paths overlap.
+func middleSnake(a, b []string) (x, y, u, v int) {
+	n, m := len(a), len(b)
+	delta := n - m
+	odd := delta%2 != 0
+	maxD := (n + m + 1) / 2
+	offset := maxD + 1
+
+	// forward[offset+k] is the furthest x reached on diagonal k = x-y from
+	// the start, backward[offset+k] the same from the end on the reversed
+	// sequences.
+	forward := make([]int, 2*offset+1)
+	backward := make([]int, 2*offset+1)
+	for d := 0; d <= maxD; d++ {
+		for k := -d; k <= d; k += 2 {
+			x := forward[offset+k-1] + 1
+			if k == -d || k != d && forward[offset+k-1] < forward[offset+k+1] {
+				x = forward[offset+k+1]
+			}
+			y := x - k
+			x0, y0 := x, y
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			forward[offset+k] = x
+			if kr := delta - k; odd && kr >= -(d-1) && kr <= d-1 && x+backward[offset+kr] >= n {
+				return x0, y0, x, y
+			}
+		}
+		for k := -d; k <= d; k += 2 {
+			x := backward[offset+k-1] + 1
+			if k == -d || k != d && backward[offset+k-1] < backward[offset+k+1] {
+				x = backward[offset+k+1]
+			}
+			y := x - k
+			x0, y0 := x, y
+			for x < n && y < m && a[n-1-x] == b[m-1-y] {
+				x++
+				y++
+			}
+			backward[offset+k] = x
+			if kf := delta - k; !odd && kf >= -d && kf <= d && x+forward[offset+kf] >= n {
+				return n - x, m - y, n - x0, m - y0
+			}
+		}
+	}
+	// Not reached: the paths overlap after at most maxD steps.
+	return 0, 0, 0, 0
+}
+
+// unified renders an edit script in the unified diff format.
+func unified(a, b []string, cfg *config) string {
+	edits := editScript(a, b)
+
+	// Group changed lines into hunks, merging hunks whose context overlaps.
+	type hunk struct{ start, end int } // edit indices, end exclusive
+	var hunks []hunk
+	for i, e := range edits {
+		if e.op == ' ' {
+			continue
+		}
+		start := max(i-cfg.contextLines, 0)
+		end := min(i+1+cfg.contextLines, len(edits))
+		if len(hunks) > 0 && start <= hunks[len(hunks)-1].end {
+			hunks[len(hunks)-1].end = end
+			continue
+		}
+		hunks = append(hunks, hunk{start, end})
+	}
+	if len(hunks) == 0 {
+		return ""
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "--- %s\n+++ %s\n", cfg.fromLabel, cfg.toLabel)
+
+	// Track the 1-based line numbers in a and b while walking the edits.
+	aLine, bLine := 1, 1
+	pos := 0
+	for _, h := range hunks {
+		for ; pos < h.start; pos++ {
+			aLine, bLine = advance(edits[pos].op, aLine, bLine)
+		}
+		aStart, bStart := aLine, bLine
+		aCount, bCount := 0, 0
+		for _, e := range edits[h.start:h.end] {
+			if e.op != '+' {
+				aCount++
+			}
+			if e.op != '-' {
+				bCount++
+			}
+		}
+		// An empty range refers to the line before it.
+		if aCount == 0 {
+			aStart--
+		}
+		if bCount == 0 {
+			bStart--
+		}
+		fmt.Fprintf(&sb, "@@ -%s +%s @@\n", hunkRange(aStart, aCount), hunkRange(bStart, bCount))
+		for ; pos < h.end; pos++ {
+			e := edits[pos]
+			sb.WriteByte(e.op)
+			sb.WriteString(e.line)
+			sb.WriteByte('\n')
+			aLine, bLine = advance(e.op, aLine, bLine)
+		}
+	}
+	return sb.String()
+}
+
+// advance moves the line counters past an edit.
+func advance(op byte, aLine, bLine int) (int, int) {
+	switch op {
+	case '-':
+		return aLine + 1, bLine
+	case '+':
+		return aLine, bLine + 1
+	default:
+		return aLine + 1, bLine + 1
+	}
+}
+
+func hunkRange(start, count int) string {
+	if count == 1 {
+		return fmt.Sprintf("%d", start)
+	}
+	return fmt.Sprintf("%d,%d", start, count)
+}
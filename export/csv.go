@@ -0,0 +1,76 @@
+package export
+
+import (
+	"encoding/csv"
+	"io"
+	"iter"
+
+	"github.com/dash0hq/dash0-api-client-go"
+)
+
+// WriteSpansCSV writes spans as CSV with a header row and one row per span,
+// with the columns described in the package documentation. Attributes,
+// events and links are JSON documents, timestamps are RFC 3339 strings, and
+// null cells are empty. Use WithAttributeColumns to add columns for
+// individual attributes.
+//
+// Spans are written as they are yielded, so the memory usage does not depend
+// on the number of spans. The first error yielded by resourceSpans is
+// returned after the rows before it have been written.
+//
+// Example:
+//
+//	iter := client.GetSpansIter(ctx, request)
+//	err := export.WriteSpansCSV(f, iter.All(), export.WithAttributeColumns("http.route", "http.response.status_code"))
+func WriteSpansCSV(w io.Writer, resourceSpans iter.Seq2[*dash0.ResourceSpans, error], opts ...TableOption) error {
+	return writeSpanRows(resourceSpans, newCSVWriter(w, spanColumns(newTableConfig(opts))))
+}
+
+// WriteLogRecordsCSV is like WriteSpansCSV for log records.
+func WriteLogRecordsCSV(w io.Writer, resourceLogs iter.Seq2[*dash0.ResourceLogs, error], opts ...TableOption) error {
+	return writeLogRecordRows(resourceLogs, newCSVWriter(w, logRecordColumns(newTableConfig(opts))))
+}
+
+type csvWriter[R any] struct {
+	w       *csv.Writer
+	columns []column[R]
+	record  []string
+	header  bool
+}
+
+func newCSVWriter[R any](w io.Writer, columns []column[R]) *csvWriter[R] {
+	return &csvWriter[R]{w: csv.NewWriter(w), columns: columns, record: make([]string, len(columns))}
+}
+
+func (w *csvWriter[R]) write(row R) error {
+	if err := w.writeHeader(); err != nil {
+		return err
+	}
+	for i, c := range w.columns {
+		v := c.value(row)
+		w.record[i] = ""
+		if v.valid {
+			w.record[i] = v.text(c.kind)
+		}
+	}
+	return w.w.Write(w.record)
+}
+
+func (w *csvWriter[R]) writeHeader() error {
+	if w.header {
+		return nil
+	}
+	w.header = true
+	for i, c := range w.columns {
+		w.record[i] = c.name
+	}
+	return w.w.Write(w.record)
+}
+
+func (w *csvWriter[R]) close() error {
+	if err := w.writeHeader(); err != nil {
+		return err
+	}
+	w.w.Flush()
+	return w.w.Error()
+}
@@ -0,0 +1,120 @@
+// Package export converts spans returned by the Dash0 API into the file
+// formats of other tracing tools, so that traces can be inspected locally.
+//
+// WriteJaeger writes the JSON format that the Jaeger UI accepts as a trace
+// upload, and WriteZipkin writes Zipkin v2 JSON. Both follow the mapping of
+// the OpenTelemetry specification for non-OTLP exporters as far as the
+// formats allow.
+//
+// For offline analysis, WriteSpansNDJSON and WriteSpansCSV and their log
+// record counterparts stream the items of an iterator such as
+// Iter.All into flat tables with one row per span or log record, which tools
+// like DuckDB can query directly. Span tables have the columns start_time,
+// end_time, duration_ns, trace_id, span_id, parent_span_id, trace_state,
+// name, kind, status_code, status_message, service_name, scope_name and
+// scope_version, followed by the columns of WithAttributeColumns and the JSON
+// columns attributes, resource_attributes, events and links. Log record
+// tables have the columns time, observed_time, trace_id, span_id,
+// severity_number, severity_text, event_name, body, service_name, scope_name
+// and scope_version, followed by the columns of WithAttributeColumns and the
+// JSON columns attributes and resource_attributes. Apache Parquet is not
+// supported yet; DuckDB can convert the NDJSON files to Parquet.
+//
+// Example:
+//
+//	resp, err := client.GetSpans(ctx, request)
+//	if err != nil {
+//	    // handle error
+//	}
+//	f, err := os.Create("trace.json")
+//	if err != nil {
+//	    // handle error
+//	}
+//	defer f.Close()
+//	err = export.WriteJaeger(f, resp.ResourceSpans)
+package export
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"strconv"
+
+	"github.com/dash0hq/dash0-api-client-go"
+)
+
+// Attribute keys of the OpenTelemetry specification for non-OTLP exporters.
+const (
+	keyStatusCode        = "otel.status_code"
+	keyStatusDescription = "otel.status_description"
+	keyScopeName         = "otel.scope.name"
+	keyScopeVersion      = "otel.scope.version"
+	keyServiceName       = "service.name"
+	keyPeerService       = "peer.service"
+)
+
+// statusCodeName returns the name of a span status code as used by
+// otel.status_code, or an empty string for the unset status.
+func statusCodeName(code dash0.SpanStatusCode) string {
+	switch code {
+	case dash0.SpanStatusCodeOK:
+		return "OK"
+	case dash0.SpanStatusCodeError:
+		return "ERROR"
+	default:
+		return ""
+	}
+}
+
+// micros converts a decimal timestamp in nanoseconds to microseconds.
+// Invalid timestamps are 0.
+func micros(unixNano string) int64 {
+	return dash0.UnixNano(unixNano) / 1000
+}
+
+// spanDuration returns the duration of a span in microseconds.
+func spanDuration(span *dash0.Span) int64 {
+	return max(micros(span.EndTimeUnixNano)-micros(span.StartTimeUnixNano), 0)
+}
+
+func hexID(id []byte) string {
+	return hex.EncodeToString(id)
+}
+
+func parentID(span *dash0.Span) string {
+	if span.ParentSpanId == nil {
+		return ""
+	}
+	return hexID(*span.ParentSpanId)
+}
+
+// forEachSpan calls fn for every span with its resource and scope.
+func forEachSpan(resourceSpans []dash0.ResourceSpans, fn func(resource *dash0.Resource, scope *dash0.InstrumentationScope, span *dash0.Span)) {
+	for i := range resourceSpans {
+		rs := &resourceSpans[i]
+		for j := range rs.ScopeSpans {
+			ss := &rs.ScopeSpans[j]
+			for k := range ss.Spans {
+				fn(&rs.Resource, ss.Scope, &ss.Spans[k])
+			}
+		}
+	}
+}
+
+// typedValue returns an attribute value as a JSON value together with its
+// Jaeger type, i.e. string, bool, int64, float64 or binary. Bytes are
+// encoded in base64.
+func typedValue(v dash0.AnyValue) (string, any) {
+	switch {
+	case v.IntValue != nil:
+		if n, err := strconv.ParseInt(*v.IntValue, 10, 64); err == nil {
+			return "int64", n
+		}
+	case v.DoubleValue != nil:
+		return "float64", *v.DoubleValue
+	case v.BoolValue != nil:
+		return "bool", *v.BoolValue
+	case v.BytesValue != nil:
+		return "binary", base64.StdEncoding.EncodeToString(*v.BytesValue)
+	}
+	return "string", v.String()
+}
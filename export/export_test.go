@@ -0,0 +1,61 @@
+package export
+
+import (
+	"github.com/dash0hq/dash0-api-client-go"
+)
+
+var (
+	testTraceID = []byte{0x4b, 0xf9, 0x2f, 0x35, 0x77, 0xb3, 0x4d, 0xa6, 0xa3, 0xce, 0x92, 0x9d, 0x0e, 0x0e, 0x47, 0x36}
+	rootSpanID  = []byte{1, 1, 1, 1, 1, 1, 1, 1}
+	childSpanID = []byte{2, 2, 2, 2, 2, 2, 2, 2}
+	linkTraceID = []byte{9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9}
+)
+
+func stringValue(s string) dash0.AnyValue {
+	return dash0.AnyValue{StringValue: dash0.String(s)}
+}
+
+// testResourceSpans returns a server span of the frontend service with a
+// failing client call to the backend as child.
+func testResourceSpans() []dash0.ResourceSpans {
+	return []dash0.ResourceSpans{{
+		Resource: dash0.Resource{Attributes: []dash0.KeyValue{
+			{Key: "service.name", Value: stringValue("frontend")},
+			{Key: "host.name", Value: stringValue("web-1")},
+		}},
+		ScopeSpans: []dash0.ScopeSpans{{
+			Scope: &dash0.InstrumentationScope{Name: dash0.String("net/http"), Version: dash0.String("1.2.3")},
+			Spans: []dash0.Span{
+				{
+					TraceId:           testTraceID,
+					SpanId:            rootSpanID,
+					Name:              "GET /checkout",
+					Kind:              2,
+					StartTimeUnixNano: "1705329000000000000",
+					EndTimeUnixNano:   "1705329000250000000",
+					Attributes:        []dash0.KeyValue{{Key: "http.status_code", Value: dash0.AnyValue{IntValue: dash0.String("500")}}},
+					Status:            dash0.SpanStatus{Code: 1},
+					Links:             []dash0.SpanLink{{TraceId: linkTraceID, SpanId: rootSpanID}},
+				},
+				{
+					TraceId:           testTraceID,
+					SpanId:            childSpanID,
+					ParentSpanId:      dash0.Ptr(rootSpanID),
+					Name:              "POST /pay",
+					Kind:              3,
+					StartTimeUnixNano: "1705329000010000000",
+					EndTimeUnixNano:   "1705329000200000000",
+					Attributes: []dash0.KeyValue{
+						{Key: "peer.service", Value: stringValue("backend")},
+						{Key: "retry", Value: dash0.AnyValue{BoolValue: dash0.Bool(true)}},
+					},
+					Events: []dash0.SpanEvent{
+						{Name: "retry", TimeUnixNano: "1705329000100000000"},
+						{Name: "exception", TimeUnixNano: "1705329000190000000", Attributes: []dash0.KeyValue{{Key: "exception.type", Value: stringValue("Timeout")}}},
+					},
+					Status: dash0.SpanStatus{Code: 2, Message: dash0.String("deadline exceeded")},
+				},
+			},
+		}},
+	}}
+}
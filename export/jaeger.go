@@ -0,0 +1,211 @@
+package export
+
+import (
+	"encoding/json"
+	"io"
+	"strconv"
+
+	"github.com/dash0hq/dash0-api-client-go"
+)
+
+// JaegerTrace is a trace in the JSON format of the Jaeger query API.
+type JaegerTrace struct {
+	TraceID   string                   `json:"traceID"`
+	Spans     []JaegerSpan             `json:"spans"`
+	Processes map[string]JaegerProcess `json:"processes"`
+	Warnings  []string                 `json:"warnings"`
+}
+
+// JaegerSpan is a span in the JSON format of the Jaeger query API.
+type JaegerSpan struct {
+	TraceID       string            `json:"traceID"`
+	SpanID        string            `json:"spanID"`
+	Flags         uint32            `json:"flags"`
+	OperationName string            `json:"operationName"`
+	References    []JaegerReference `json:"references"`
+	StartTime     int64             `json:"startTime"`
+	Duration      int64             `json:"duration"`
+	Tags          []JaegerKeyValue  `json:"tags"`
+	Logs          []JaegerLog       `json:"logs"`
+	ProcessID     string            `json:"processID"`
+	Warnings      []string          `json:"warnings"`
+}
+
+// JaegerReference is a reference from a span to its parent (CHILD_OF) or to
+// a linked span (FOLLOWS_FROM).
+type JaegerReference struct {
+	RefType string `json:"refType"`
+	TraceID string `json:"traceID"`
+	SpanID  string `json:"spanID"`
+}
+
+// JaegerKeyValue is a tag or log field. Type is one of string, bool, int64,
+// float64 and binary.
+type JaegerKeyValue struct {
+	Key   string `json:"key"`
+	Type  string `json:"type"`
+	Value any    `json:"value"`
+}
+
+// JaegerLog is a timestamped span log, converted from a span event.
+type JaegerLog struct {
+	Timestamp int64            `json:"timestamp"`
+	Fields    []JaegerKeyValue `json:"fields"`
+}
+
+// JaegerProcess describes the service that emitted a span.
+type JaegerProcess struct {
+	ServiceName string           `json:"serviceName"`
+	Tags        []JaegerKeyValue `json:"tags"`
+}
+
+// Jaeger reference types.
+const (
+	JaegerChildOf     = "CHILD_OF"
+	JaegerFollowsFrom = "FOLLOWS_FROM"
+)
+
+// ToJaeger converts resource spans to Jaeger traces, one per trace ID in the
+// order of their first span. Timestamps are truncated to microseconds.
+//
+// The mapping follows the OpenTelemetry specification: the span kind becomes
+// the span.kind tag, the status becomes the otel.status_code and
+// otel.status_description tags plus error=true for errors, events become
+// logs with an event field, and links become FOLLOWS_FROM references. The
+// resource becomes the process, with service.name as the service name, and
+// the instrumentation scope becomes the otel.scope.name and
+// otel.scope.version tags.
+func ToJaeger(resourceSpans []dash0.ResourceSpans) []JaegerTrace {
+	var traces []*JaegerTrace
+	byID := make(map[string]*JaegerTrace)
+	// Processes are shared by the spans of a resource within a trace
+	processIDs := make(map[string]map[*dash0.Resource]string)
+
+	forEachSpan(resourceSpans, func(resource *dash0.Resource, scope *dash0.InstrumentationScope, span *dash0.Span) {
+		traceID := hexID(span.TraceId)
+		trace := byID[traceID]
+		if trace == nil {
+			trace = &JaegerTrace{TraceID: traceID, Processes: make(map[string]JaegerProcess)}
+			byID[traceID] = trace
+			traces = append(traces, trace)
+			processIDs[traceID] = make(map[*dash0.Resource]string)
+		}
+		processID, ok := processIDs[traceID][resource]
+		if !ok {
+			processID = "p" + strconv.Itoa(len(trace.Processes)+1)
+			processIDs[traceID][resource] = processID
+			trace.Processes[processID] = jaegerProcess(resource)
+		}
+		trace.Spans = append(trace.Spans, jaegerSpan(scope, span, processID))
+	})
+
+	result := make([]JaegerTrace, len(traces))
+	for i, trace := range traces {
+		result[i] = *trace
+	}
+	return result
+}
+
+// WriteJaeger writes resource spans as Jaeger JSON, i.e. the traces returned
+// by ToJaeger wrapped in a data field. This is the format of the Jaeger query
+// API, which the Jaeger UI can open as a JSON file.
+func WriteJaeger(w io.Writer, resourceSpans []dash0.ResourceSpans) error {
+	return json.NewEncoder(w).Encode(struct {
+		Data []JaegerTrace `json:"data"`
+	}{ToJaeger(resourceSpans)})
+}
+
+func jaegerProcess(resource *dash0.Resource) JaegerProcess {
+	process := JaegerProcess{ServiceName: resource.ServiceName(), Tags: []JaegerKeyValue{}}
+	for _, kv := range resource.Attributes {
+		if kv.Key != keyServiceName {
+			process.Tags = append(process.Tags, jaegerKeyValue(kv.Key, kv.Value))
+		}
+	}
+	return process
+}
+
+func jaegerSpan(scope *dash0.InstrumentationScope, span *dash0.Span, processID string) JaegerSpan {
+	traceID := hexID(span.TraceId)
+	s := JaegerSpan{
+		TraceID:       traceID,
+		SpanID:        hexID(span.SpanId),
+		Flags:         uint32(dash0.Int64Value(span.Flags) & 0xff),
+		OperationName: span.Name,
+		References:    []JaegerReference{},
+		StartTime:     micros(span.StartTimeUnixNano),
+		Duration:      spanDuration(span),
+		Tags:          []JaegerKeyValue{},
+		Logs:          []JaegerLog{},
+		ProcessID:     processID,
+	}
+
+	if parent := parentID(span); parent != "" {
+		s.References = append(s.References, JaegerReference{RefType: JaegerChildOf, TraceID: traceID, SpanID: parent})
+	}
+	for _, link := range span.Links {
+		s.References = append(s.References, JaegerReference{RefType: JaegerFollowsFrom, TraceID: hexID(link.TraceId), SpanID: hexID(link.SpanId)})
+	}
+
+	for _, kv := range span.Attributes {
+		s.Tags = append(s.Tags, jaegerKeyValue(kv.Key, kv.Value))
+	}
+	if kind := jaegerSpanKind(span.Kind); kind != "" {
+		s.Tags = append(s.Tags, jaegerString("span.kind", kind))
+	}
+	if code := statusCodeName(span.Status.Code); code != "" {
+		s.Tags = append(s.Tags, jaegerString(keyStatusCode, code))
+	}
+	if span.Status.Code == dash0.SpanStatusCodeError {
+		s.Tags = append(s.Tags, JaegerKeyValue{Key: "error", Type: "bool", Value: true})
+		if message := dash0.StringValue(span.Status.Message); message != "" {
+			s.Tags = append(s.Tags, jaegerString(keyStatusDescription, message))
+		}
+	}
+	if scope != nil {
+		if name := dash0.StringValue(scope.Name); name != "" {
+			s.Tags = append(s.Tags, jaegerString(keyScopeName, name))
+		}
+		if version := dash0.StringValue(scope.Version); version != "" {
+			s.Tags = append(s.Tags, jaegerString(keyScopeVersion, version))
+		}
+	}
+	if state := dash0.StringValue(span.TraceState); state != "" {
+		s.Tags = append(s.Tags, jaegerString("w3c.tracestate", state))
+	}
+
+	for _, event := range span.Events {
+		log := JaegerLog{Timestamp: micros(event.TimeUnixNano), Fields: []JaegerKeyValue{jaegerString("event", event.Name)}}
+		for _, kv := range event.Attributes {
+			log.Fields = append(log.Fields, jaegerKeyValue(kv.Key, kv.Value))
+		}
+		s.Logs = append(s.Logs, log)
+	}
+	return s
+}
+
+func jaegerSpanKind(kind dash0.SpanKind) string {
+	switch kind {
+	case dash0.SpanKindInternal:
+		return "internal"
+	case dash0.SpanKindServer:
+		return "server"
+	case dash0.SpanKindClient:
+		return "client"
+	case dash0.SpanKindProducer:
+		return "producer"
+	case dash0.SpanKindConsumer:
+		return "consumer"
+	default:
+		return ""
+	}
+}
+
+func jaegerString(key, value string) JaegerKeyValue {
+	return JaegerKeyValue{Key: key, Type: "string", Value: value}
+}
+
+func jaegerKeyValue(key string, v dash0.AnyValue) JaegerKeyValue {
+	typ, value := typedValue(v)
+	return JaegerKeyValue{Key: key, Type: typ, Value: value}
+}
@@ -0,0 +1,108 @@
+package export
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+)
+
+func jaegerTag(tags []JaegerKeyValue, key string) *JaegerKeyValue {
+	for i := range tags {
+		if tags[i].Key == key {
+			return &tags[i]
+		}
+	}
+	return nil
+}
+
+func TestToJaeger(t *testing.T) {
+	traces := ToJaeger(testResourceSpans())
+	if len(traces) != 1 || len(traces[0].Spans) != 2 {
+		t.Fatalf("expected 1 trace with 2 spans, got %+v", traces)
+	}
+	trace := traces[0]
+	if trace.TraceID != "4bf92f3577b34da6a3ce929d0e0e4736" {
+		t.Errorf("unexpected trace ID %s", trace.TraceID)
+	}
+
+	t.Run("maps the resource to a process", func(t *testing.T) {
+		process, ok := trace.Processes["p1"]
+		if !ok || len(trace.Processes) != 1 || process.ServiceName != "frontend" {
+			t.Fatalf("unexpected processes %+v", trace.Processes)
+		}
+		if len(process.Tags) != 1 || process.Tags[0].Key != "host.name" {
+			t.Errorf("unexpected process tags %+v", process.Tags)
+		}
+	})
+
+	t.Run("maps span fields", func(t *testing.T) {
+		root, child := trace.Spans[0], trace.Spans[1]
+		if root.StartTime != 1705329000000000 || root.Duration != 250000 || root.ProcessID != "p1" {
+			t.Errorf("unexpected root span %+v", root)
+		}
+		if tag := jaegerTag(root.Tags, "http.status_code"); tag == nil || tag.Type != "int64" || tag.Value != int64(500) {
+			t.Errorf("unexpected int tag %+v", tag)
+		}
+		if tag := jaegerTag(root.Tags, "span.kind"); tag == nil || tag.Value != "server" {
+			t.Errorf("unexpected span.kind %+v", tag)
+		}
+		if tag := jaegerTag(root.Tags, "otel.status_code"); tag == nil || tag.Value != "OK" {
+			t.Errorf("unexpected status %+v", tag)
+		}
+		if tag := jaegerTag(root.Tags, "otel.scope.name"); tag == nil || tag.Value != "net/http" {
+			t.Errorf("unexpected scope %+v", tag)
+		}
+		if jaegerTag(root.Tags, "error") != nil {
+			t.Error("expected no error tag for OK spans")
+		}
+
+		if tag := jaegerTag(child.Tags, "error"); tag == nil || tag.Value != true {
+			t.Errorf("expected error tag, got %+v", tag)
+		}
+		if tag := jaegerTag(child.Tags, "otel.status_description"); tag == nil || tag.Value != "deadline exceeded" {
+			t.Errorf("unexpected status description %+v", tag)
+		}
+	})
+
+	t.Run("maps parents and links to references", func(t *testing.T) {
+		root, child := trace.Spans[0], trace.Spans[1]
+		if len(root.References) != 1 || root.References[0].RefType != JaegerFollowsFrom || root.References[0].TraceID != "09090909090909090909090909090909" {
+			t.Errorf("unexpected root references %+v", root.References)
+		}
+		if len(child.References) != 1 || child.References[0] != (JaegerReference{RefType: JaegerChildOf, TraceID: trace.TraceID, SpanID: "0101010101010101"}) {
+			t.Errorf("unexpected child references %+v", child.References)
+		}
+	})
+
+	t.Run("maps events to logs", func(t *testing.T) {
+		logs := trace.Spans[1].Logs
+		if len(logs) != 2 || logs[1].Timestamp != 1705329000190000 {
+			t.Fatalf("unexpected logs %+v", logs)
+		}
+		if tag := jaegerTag(logs[1].Fields, "event"); tag == nil || tag.Value != "exception" {
+			t.Errorf("unexpected event field %+v", tag)
+		}
+		if tag := jaegerTag(logs[1].Fields, "exception.type"); tag == nil || tag.Value != "Timeout" {
+			t.Errorf("unexpected event attribute %+v", tag)
+		}
+	})
+}
+
+func TestWriteJaeger(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteJaeger(&buf, testResourceSpans()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var doc struct {
+		Data []struct {
+			TraceID string           `json:"traceID"`
+			Spans   []map[string]any `json:"spans"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if len(doc.Data) != 1 || len(doc.Data[0].Spans) != 2 || doc.Data[0].Spans[0]["operationName"] != "GET /checkout" {
+		t.Errorf("unexpected document %s", buf.String())
+	}
+}
@@ -0,0 +1,75 @@
+package export
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"iter"
+
+	"github.com/dash0hq/dash0-api-client-go"
+)
+
+// WriteSpansNDJSON writes spans as newline-delimited JSON, one object per span
+// with the columns described in the package documentation as fields. The
+// attributes, events and links are nested JSON values, timestamps are RFC
+// 3339 strings, and null cells are null.
+//
+// Spans are written as they are yielded, so the memory usage does not depend
+// on the number of spans. The first error yielded by resourceSpans is
+// returned after the rows before it have been written.
+//
+// Example:
+//
+//	iter := client.GetSpansIter(ctx, request)
+//	err := export.WriteSpansNDJSON(f, iter.All())
+func WriteSpansNDJSON(w io.Writer, resourceSpans iter.Seq2[*dash0.ResourceSpans, error], opts ...TableOption) error {
+	return writeSpanRows(resourceSpans, newNDJSONWriter(w, spanColumns(newTableConfig(opts))))
+}
+
+// WriteLogRecordsNDJSON is like WriteSpansNDJSON for log records.
+func WriteLogRecordsNDJSON(w io.Writer, resourceLogs iter.Seq2[*dash0.ResourceLogs, error], opts ...TableOption) error {
+	return writeLogRecordRows(resourceLogs, newNDJSONWriter(w, logRecordColumns(newTableConfig(opts))))
+}
+
+type ndjsonWriter[R any] struct {
+	w       *bufio.Writer
+	columns []column[R]
+	line    []byte
+}
+
+func newNDJSONWriter[R any](w io.Writer, columns []column[R]) *ndjsonWriter[R] {
+	return &ndjsonWriter[R]{w: bufio.NewWriter(w), columns: columns}
+}
+
+func (w *ndjsonWriter[R]) write(row R) error {
+	line := append(w.line[:0], '{')
+	for i, c := range w.columns {
+		if i > 0 {
+			line = append(line, ',')
+		}
+		line = appendJSONString(line, c.name)
+		line = append(line, ':')
+
+		v := c.value(row)
+		switch {
+		case !v.valid:
+			line = append(line, "null"...)
+		case c.kind == int64Column, c.kind == jsonColumn:
+			line = append(line, v.text(c.kind)...)
+		default:
+			line = appendJSONString(line, v.text(c.kind))
+		}
+	}
+	w.line = append(line, '}', '\n')
+	_, err := w.w.Write(w.line)
+	return err
+}
+
+func (w *ndjsonWriter[R]) close() error {
+	return w.w.Flush()
+}
+
+func appendJSONString(b []byte, s string) []byte {
+	encoded, _ := json.Marshal(s)
+	return append(b, encoded...)
+}
@@ -0,0 +1,312 @@
+package export
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"iter"
+	"strconv"
+	"time"
+
+	"github.com/dash0hq/dash0-api-client-go"
+)
+
+// TableOption configures the tabular exporters such as WriteSpansCSV.
+type TableOption func(*tableConfig)
+
+type tableConfig struct {
+	attributeColumns []string
+}
+
+// WithAttributeColumns adds a column per attribute key, named after the key,
+// after the fixed columns. Values are looked up like dash0.LookupSpanKey and
+// dash0.LookupLogRecordKey do, in the span or log record attributes, the
+// scope attributes and the resource attributes, in that order, and are
+// written as strings.
+func WithAttributeColumns(keys ...string) TableOption {
+	return func(c *tableConfig) {
+		c.attributeColumns = append(c.attributeColumns, keys...)
+	}
+}
+
+func newTableConfig(opts []TableOption) *tableConfig {
+	cfg := &tableConfig{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	return cfg
+}
+
+// columnKind is the type of a column.
+type columnKind int
+
+const (
+	stringColumn columnKind = iota
+	int64Column
+	// timestampColumn holds nanoseconds since the Unix epoch.
+	timestampColumn
+	// jsonColumn holds a JSON document as string.
+	jsonColumn
+)
+
+// column is a column of a table whose rows are of type R.
+type column[R any] struct {
+	name  string
+	kind  columnKind
+	value func(R) cell
+}
+
+// cell is a value of a column. Invalid cells are null.
+type cell struct {
+	str   string
+	num   int64
+	valid bool
+}
+
+func stringCell(s string) cell {
+	return cell{str: s, valid: true}
+}
+
+// optionalStringCell returns a null cell for empty strings.
+func optionalStringCell(s string) cell {
+	return cell{str: s, valid: s != ""}
+}
+
+func int64Cell(n int64) cell {
+	return cell{num: n, valid: true}
+}
+
+// timestampCell returns the cell of a decimal timestamp in nanoseconds, or a
+// null cell if the timestamp is unset.
+func timestampCell(unixNano string) cell {
+	n := dash0.UnixNano(unixNano)
+	return cell{num: n, valid: n != 0}
+}
+
+func jsonCell(v any) cell {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		return cell{}
+	}
+	return cell{str: string(bytes.TrimSuffix(buf.Bytes(), []byte("\n"))), valid: true}
+}
+
+// text returns the cell as text for the CSV and NDJSON formats. Timestamps
+// are formatted in RFC 3339 with nanoseconds.
+func (c cell) text(kind columnKind) string {
+	switch kind {
+	case int64Column:
+		return strconv.FormatInt(c.num, 10)
+	case timestampColumn:
+		return time.Unix(0, c.num).UTC().Format(time.RFC3339Nano)
+	default:
+		return c.str
+	}
+}
+
+// tableWriter writes rows of type R to a file format.
+type tableWriter[R any] interface {
+	write(row R) error
+	close() error
+}
+
+// writeSpanRows writes the spans of all resource spans as rows and closes the
+// writer. The writer is also closed on iteration errors, so that the rows
+// before the error are flushed.
+func writeSpanRows(resourceSpans iter.Seq2[*dash0.ResourceSpans, error], w tableWriter[*dash0.SpanEntry]) error {
+	for rs, err := range resourceSpans {
+		if err != nil {
+			return errors.Join(err, w.close())
+		}
+		for i := range rs.ScopeSpans {
+			ss := &rs.ScopeSpans[i]
+			for j := range ss.Spans {
+				if err := w.write(&dash0.SpanEntry{Resource: &rs.Resource, Scope: ss.Scope, Span: &ss.Spans[j]}); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return w.close()
+}
+
+// writeLogRecordRows writes the log records of all resource logs as rows and
+// closes the writer, also on iteration errors.
+func writeLogRecordRows(resourceLogs iter.Seq2[*dash0.ResourceLogs, error], w tableWriter[*dash0.LogRecordEntry]) error {
+	for rl, err := range resourceLogs {
+		if err != nil {
+			return errors.Join(err, w.close())
+		}
+		for i := range rl.ScopeLogs {
+			sl := &rl.ScopeLogs[i]
+			for j := range sl.LogRecords {
+				if err := w.write(&dash0.LogRecordEntry{Resource: &rl.Resource, Scope: sl.Scope, LogRecord: &sl.LogRecords[j]}); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return w.close()
+}
+
+// spanColumns returns the columns of the span table.
+func spanColumns(cfg *tableConfig) []column[*dash0.SpanEntry] {
+	columns := []column[*dash0.SpanEntry]{
+		{"start_time", timestampColumn, func(e *dash0.SpanEntry) cell { return timestampCell(e.Span.StartTimeUnixNano) }},
+		{"end_time", timestampColumn, func(e *dash0.SpanEntry) cell { return timestampCell(e.Span.EndTimeUnixNano) }},
+		{"duration_ns", int64Column, func(e *dash0.SpanEntry) cell {
+			return int64Cell(max(dash0.UnixNano(e.Span.EndTimeUnixNano)-dash0.UnixNano(e.Span.StartTimeUnixNano), 0))
+		}},
+		{"trace_id", stringColumn, func(e *dash0.SpanEntry) cell { return stringCell(hexID(e.Span.TraceId)) }},
+		{"span_id", stringColumn, func(e *dash0.SpanEntry) cell { return stringCell(hexID(e.Span.SpanId)) }},
+		{"parent_span_id", stringColumn, func(e *dash0.SpanEntry) cell { return optionalStringCell(parentID(e.Span)) }},
+		{"trace_state", stringColumn, func(e *dash0.SpanEntry) cell { return optionalStringCell(dash0.StringValue(e.Span.TraceState)) }},
+		{"name", stringColumn, func(e *dash0.SpanEntry) cell { return stringCell(e.Span.Name) }},
+		{"kind", stringColumn, func(e *dash0.SpanEntry) cell { return stringCell(spanKindName(e.Span.Kind)) }},
+		{"status_code", stringColumn, func(e *dash0.SpanEntry) cell { return stringCell(statusCodeNameOrUnset(e.Span.Status.Code)) }},
+		{"status_message", stringColumn, func(e *dash0.SpanEntry) cell { return optionalStringCell(dash0.StringValue(e.Span.Status.Message)) }},
+		{"service_name", stringColumn, func(e *dash0.SpanEntry) cell { return stringCell(e.Resource.ServiceName()) }},
+		{"scope_name", stringColumn, func(e *dash0.SpanEntry) cell { return optionalStringCell(scopeName(e.Scope)) }},
+		{"scope_version", stringColumn, func(e *dash0.SpanEntry) cell { return optionalStringCell(scopeVersion(e.Scope)) }},
+	}
+	for _, key := range cfg.attributeColumns {
+		columns = append(columns, column[*dash0.SpanEntry]{key, stringColumn, func(e *dash0.SpanEntry) cell {
+			return lookupCell(dash0.LookupSpanKey(e.Resource, e.Scope, e.Span, key))
+		}})
+	}
+	return append(columns,
+		column[*dash0.SpanEntry]{"attributes", jsonColumn, func(e *dash0.SpanEntry) cell { return jsonCell(attributeMap(e.Span.Attributes)) }},
+		column[*dash0.SpanEntry]{"resource_attributes", jsonColumn, func(e *dash0.SpanEntry) cell { return jsonCell(attributeMap(e.Resource.Attributes)) }},
+		column[*dash0.SpanEntry]{"events", jsonColumn, func(e *dash0.SpanEntry) cell { return eventsCell(e.Span.Events) }},
+		column[*dash0.SpanEntry]{"links", jsonColumn, func(e *dash0.SpanEntry) cell { return linksCell(e.Span.Links) }},
+	)
+}
+
+// logRecordColumns returns the columns of the log record table.
+func logRecordColumns(cfg *tableConfig) []column[*dash0.LogRecordEntry] {
+	columns := []column[*dash0.LogRecordEntry]{
+		{"time", timestampColumn, func(e *dash0.LogRecordEntry) cell { return timestampCell(e.LogRecord.TimeUnixNano) }},
+		{"observed_time", timestampColumn, func(e *dash0.LogRecordEntry) cell { return timestampCell(e.LogRecord.ObservedTimeUnixNano) }},
+		{"trace_id", stringColumn, func(e *dash0.LogRecordEntry) cell { return optionalIDCell(e.LogRecord.TraceId) }},
+		{"span_id", stringColumn, func(e *dash0.LogRecordEntry) cell { return optionalIDCell(e.LogRecord.SpanId) }},
+		{"severity_number", int64Column, func(e *dash0.LogRecordEntry) cell {
+			if e.LogRecord.SeverityNumber == nil {
+				return cell{}
+			}
+			return int64Cell(int64(*e.LogRecord.SeverityNumber))
+		}},
+		{"severity_text", stringColumn, func(e *dash0.LogRecordEntry) cell {
+			return optionalStringCell(dash0.StringValue(e.LogRecord.SeverityText))
+		}},
+		{"event_name", stringColumn, func(e *dash0.LogRecordEntry) cell {
+			return optionalStringCell(dash0.StringValue(e.LogRecord.EventName))
+		}},
+		{"body", stringColumn, func(e *dash0.LogRecordEntry) cell {
+			if e.LogRecord.Body == nil {
+				return cell{}
+			}
+			return stringCell(e.LogRecord.Body.String())
+		}},
+		{"service_name", stringColumn, func(e *dash0.LogRecordEntry) cell { return stringCell(e.Resource.ServiceName()) }},
+		{"scope_name", stringColumn, func(e *dash0.LogRecordEntry) cell { return optionalStringCell(scopeName(e.Scope)) }},
+		{"scope_version", stringColumn, func(e *dash0.LogRecordEntry) cell { return optionalStringCell(scopeVersion(e.Scope)) }},
+	}
+	for _, key := range cfg.attributeColumns {
+		columns = append(columns, column[*dash0.LogRecordEntry]{key, stringColumn, func(e *dash0.LogRecordEntry) cell {
+			return lookupCell(dash0.LookupLogRecordKey(e.Resource, e.Scope, e.LogRecord, key))
+		}})
+	}
+	return append(columns,
+		column[*dash0.LogRecordEntry]{"attributes", jsonColumn, func(e *dash0.LogRecordEntry) cell { return jsonCell(attributeMap(e.LogRecord.Attributes)) }},
+		column[*dash0.LogRecordEntry]{"resource_attributes", jsonColumn, func(e *dash0.LogRecordEntry) cell { return jsonCell(attributeMap(e.Resource.Attributes)) }},
+	)
+}
+
+func optionalIDCell(id *[]byte) cell {
+	if id == nil {
+		return cell{}
+	}
+	return optionalStringCell(hexID(*id))
+}
+
+func scopeName(scope *dash0.InstrumentationScope) string {
+	if scope == nil {
+		return ""
+	}
+	return dash0.StringValue(scope.Name)
+}
+
+func scopeVersion(scope *dash0.InstrumentationScope) string {
+	if scope == nil {
+		return ""
+	}
+	return dash0.StringValue(scope.Version)
+}
+
+// lookupCell returns the cell of a looked up value, or a null cell if the
+// key was not found.
+func lookupCell(value string, ok bool) cell {
+	return cell{str: value, valid: ok}
+}
+
+// attributeMap returns attributes as a map of typed JSON values.
+func attributeMap(attributes []dash0.KeyValue) map[string]any {
+	m := make(map[string]any, len(attributes))
+	for _, kv := range attributes {
+		_, m[kv.Key] = typedValue(kv.Value)
+	}
+	return m
+}
+
+func eventsCell(events []dash0.SpanEvent) cell {
+	type event struct {
+		Time       string         `json:"time"`
+		Name       string         `json:"name"`
+		Attributes map[string]any `json:"attributes"`
+	}
+	values := make([]event, len(events))
+	for i, e := range events {
+		values[i] = event{Time: timestampCell(e.TimeUnixNano).text(timestampColumn), Name: e.Name, Attributes: attributeMap(e.Attributes)}
+	}
+	return jsonCell(values)
+}
+
+func linksCell(links []dash0.SpanLink) cell {
+	type link struct {
+		TraceID    string         `json:"trace_id"`
+		SpanID     string         `json:"span_id"`
+		Attributes map[string]any `json:"attributes"`
+	}
+	values := make([]link, len(links))
+	for i, l := range links {
+		values[i] = link{TraceID: hexID(l.TraceId), SpanID: hexID(l.SpanId), Attributes: attributeMap(l.Attributes)}
+	}
+	return jsonCell(values)
+}
+
+// spanKindName returns the name of a span kind, e.g. SERVER.
+func spanKindName(kind dash0.SpanKind) string {
+	switch kind {
+	case dash0.SpanKindInternal:
+		return "INTERNAL"
+	case dash0.SpanKindServer:
+		return "SERVER"
+	case dash0.SpanKindClient:
+		return "CLIENT"
+	case dash0.SpanKindProducer:
+		return "PRODUCER"
+	case dash0.SpanKindConsumer:
+		return "CONSUMER"
+	default:
+		return "UNSPECIFIED"
+	}
+}
+
+func statusCodeNameOrUnset(code dash0.SpanStatusCode) string {
+	if name := statusCodeName(code); name != "" {
+		return name
+	}
+	return "UNSET"
+}
@@ -0,0 +1,167 @@
+package export
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"iter"
+	"strings"
+	"testing"
+
+	"github.com/dash0hq/dash0-api-client-go"
+)
+
+// seq yields pointers to the items, followed by err if it is not nil.
+func seq[T any](items []T, err error) iter.Seq2[*T, error] {
+	return func(yield func(*T, error) bool) {
+		for i := range items {
+			if !yield(&items[i], nil) {
+				return
+			}
+		}
+		if err != nil {
+			yield(nil, err)
+		}
+	}
+}
+
+func testResourceLogs() []dash0.ResourceLogs {
+	return []dash0.ResourceLogs{{
+		Resource: dash0.Resource{Attributes: []dash0.KeyValue{{Key: "service.name", Value: stringValue("frontend")}}},
+		ScopeLogs: []dash0.ScopeLogs{{
+			LogRecords: []dash0.LogRecord{
+				{
+					TimeUnixNano:   "1705329000000000000",
+					SeverityNumber: dash0.Ptr(dash0.SeverityNumber(17)),
+					SeverityText:   dash0.String("ERROR"),
+					Body:           dash0.Ptr(stringValue("payment failed")),
+					TraceId:        dash0.Ptr(testTraceID),
+					Attributes:     []dash0.KeyValue{{Key: "user.id", Value: stringValue("42")}},
+				},
+				{ObservedTimeUnixNano: "1705329000500000000"},
+			},
+		}},
+	}}
+}
+
+func TestWriteNDJSON(t *testing.T) {
+	t.Run("writes spans", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := WriteSpansNDJSON(&buf, seq(testResourceSpans(), nil), WithAttributeColumns("peer.service")); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+		if len(lines) != 2 {
+			t.Fatalf("expected 2 lines, got %d", len(lines))
+		}
+		if !strings.HasPrefix(lines[0], `{"start_time":"2024-01-15T14:30:00Z","end_time":"2024-01-15T14:30:00.25Z","duration_ns":250000000,`) {
+			t.Errorf("unexpected column order in %s", lines[0])
+		}
+
+		var child map[string]any
+		if err := json.Unmarshal([]byte(lines[1]), &child); err != nil {
+			t.Fatalf("invalid JSON: %v", err)
+		}
+		for key, want := range map[string]any{
+			"parent_span_id": "0101010101010101",
+			"kind":           "CLIENT",
+			"status_code":    "ERROR",
+			"service_name":   "frontend",
+			"peer.service":   "backend",
+			"trace_state":    nil,
+		} {
+			if child[key] != want {
+				t.Errorf("expected %s=%v, got %v", key, want, child[key])
+			}
+		}
+		if attributes, ok := child["attributes"].(map[string]any); !ok || attributes["retry"] != true {
+			t.Errorf("expected nested attributes, got %v", child["attributes"])
+		}
+		if events, ok := child["events"].([]any); !ok || len(events) != 2 {
+			t.Errorf("expected nested events, got %v", child["events"])
+		}
+	})
+
+	t.Run("writes log records", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := WriteLogRecordsNDJSON(&buf, seq(testResourceLogs(), nil)); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		var first, second map[string]any
+		lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+		if err := errors.Join(json.Unmarshal([]byte(lines[0]), &first), json.Unmarshal([]byte(lines[1]), &second)); err != nil {
+			t.Fatalf("invalid JSON: %v", err)
+		}
+		if first["severity_number"] != float64(17) || first["body"] != "payment failed" || first["trace_id"] != "4bf92f3577b34da6a3ce929d0e0e4736" {
+			t.Errorf("unexpected log record %v", first)
+		}
+		if second["time"] != nil || second["observed_time"] != "2024-01-15T14:30:00.5Z" || second["body"] != nil {
+			t.Errorf("unexpected log record %v", second)
+		}
+	})
+
+	t.Run("returns iteration errors", func(t *testing.T) {
+		errFetch := errors.New("fetch failed")
+		var buf bytes.Buffer
+		if err := WriteSpansNDJSON(&buf, seq(testResourceSpans(), errFetch)); !errors.Is(err, errFetch) {
+			t.Errorf("expected iteration error, got %v", err)
+		}
+		if lines := strings.Count(buf.String(), "\n"); lines != 2 {
+			t.Errorf("expected the 2 rows before the error, got %d lines", lines)
+		}
+	})
+}
+
+func TestWriteCSV(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteSpansCSV(&buf, seq(testResourceSpans(), nil), WithAttributeColumns("host.name", "missing")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("invalid CSV: %v", err)
+	}
+	if len(records) != 3 {
+		t.Fatalf("expected header and 2 rows, got %d", len(records))
+	}
+
+	row := make(map[string]string)
+	for i, name := range records[0] {
+		row[name] = records[2][i]
+	}
+	for key, want := range map[string]string{
+		"start_time":     "2024-01-15T14:30:00.01Z",
+		"name":           "POST /pay",
+		"status_message": "deadline exceeded",
+		"host.name":      "web-1",
+		"missing":        "",
+		"links":          "[]",
+	} {
+		if row[key] != want {
+			t.Errorf("expected %s=%q, got %q", key, want, row[key])
+		}
+	}
+
+	t.Run("writes the header without rows", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := WriteLogRecordsCSV(&buf, seq[dash0.ResourceLogs](nil, nil)); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !strings.HasPrefix(buf.String(), "time,observed_time,trace_id,") || strings.Count(buf.String(), "\n") != 1 {
+			t.Errorf("unexpected output %q", buf.String())
+		}
+	})
+
+	t.Run("flushes the rows before iteration errors", func(t *testing.T) {
+		errFetch := errors.New("fetch failed")
+		var buf bytes.Buffer
+		if err := WriteSpansCSV(&buf, seq(testResourceSpans(), errFetch)); !errors.Is(err, errFetch) {
+			t.Errorf("expected iteration error, got %v", err)
+		}
+		records, err := csv.NewReader(&buf).ReadAll()
+		if err != nil || len(records) != 3 {
+			t.Errorf("expected header and 2 rows, got %d records (%v)", len(records), err)
+		}
+	})
+}
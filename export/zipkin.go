@@ -0,0 +1,140 @@
+package export
+
+import (
+	"encoding/json"
+	"io"
+
+	"github.com/dash0hq/dash0-api-client-go"
+)
+
+// ZipkinSpan is a span in the Zipkin v2 JSON format.
+type ZipkinSpan struct {
+	TraceID        string             `json:"traceId"`
+	ID             string             `json:"id"`
+	ParentID       string             `json:"parentId,omitempty"`
+	Name           string             `json:"name,omitempty"`
+	Kind           string             `json:"kind,omitempty"`
+	Timestamp      int64              `json:"timestamp,omitempty"`
+	Duration       int64              `json:"duration,omitempty"`
+	LocalEndpoint  *ZipkinEndpoint    `json:"localEndpoint,omitempty"`
+	RemoteEndpoint *ZipkinEndpoint    `json:"remoteEndpoint,omitempty"`
+	Annotations    []ZipkinAnnotation `json:"annotations,omitempty"`
+	Tags           map[string]string  `json:"tags,omitempty"`
+}
+
+// ZipkinEndpoint identifies the service on either side of a span.
+type ZipkinEndpoint struct {
+	ServiceName string `json:"serviceName,omitempty"`
+}
+
+// ZipkinAnnotation is a timestamped event of a span.
+type ZipkinAnnotation struct {
+	Timestamp int64  `json:"timestamp"`
+	Value     string `json:"value"`
+}
+
+// ToZipkin converts resource spans to Zipkin v2 spans. Timestamps are
+// truncated to microseconds.
+//
+// The mapping follows the OpenTelemetry specification: server, client,
+// producer and consumer spans get the corresponding kind, the status becomes
+// the otel.status_code tag plus an error tag with the status message for
+// errors, and events become annotations, with their attributes encoded as
+// JSON. Resource and span attributes become tags, service.name becomes the
+// local endpoint and peer.service the remote endpoint. Zipkin has no concept
+// of span links, so links are dropped.
+func ToZipkin(resourceSpans []dash0.ResourceSpans) []ZipkinSpan {
+	var result []ZipkinSpan
+	forEachSpan(resourceSpans, func(resource *dash0.Resource, scope *dash0.InstrumentationScope, span *dash0.Span) {
+		result = append(result, zipkinSpan(resource, scope, span))
+	})
+	return result
+}
+
+// WriteZipkin writes resource spans as a Zipkin v2 JSON array, as accepted by
+// the /api/v2/spans endpoint and the JSON upload of the Zipkin UI.
+func WriteZipkin(w io.Writer, resourceSpans []dash0.ResourceSpans) error {
+	spans := ToZipkin(resourceSpans)
+	if spans == nil {
+		spans = []ZipkinSpan{}
+	}
+	return json.NewEncoder(w).Encode(spans)
+}
+
+func zipkinSpan(resource *dash0.Resource, scope *dash0.InstrumentationScope, span *dash0.Span) ZipkinSpan {
+	s := ZipkinSpan{
+		TraceID:       hexID(span.TraceId),
+		ID:            hexID(span.SpanId),
+		ParentID:      parentID(span),
+		Name:          span.Name,
+		Kind:          zipkinSpanKind(span.Kind),
+		Timestamp:     micros(span.StartTimeUnixNano),
+		Duration:      spanDuration(span),
+		LocalEndpoint: &ZipkinEndpoint{ServiceName: resource.ServiceName()},
+		Tags:          make(map[string]string),
+	}
+
+	for _, kv := range resource.Attributes {
+		if kv.Key != keyServiceName {
+			s.Tags[kv.Key] = kv.Value.String()
+		}
+	}
+	for _, kv := range span.Attributes {
+		s.Tags[kv.Key] = kv.Value.String()
+		if kv.Key == keyPeerService && (span.Kind == dash0.SpanKindClient || span.Kind == dash0.SpanKindProducer) {
+			s.RemoteEndpoint = &ZipkinEndpoint{ServiceName: kv.Value.String()}
+		}
+	}
+	if code := statusCodeName(span.Status.Code); code != "" {
+		s.Tags[keyStatusCode] = code
+	}
+	if span.Status.Code == dash0.SpanStatusCodeError {
+		s.Tags["error"] = dash0.StringValue(span.Status.Message)
+	}
+	if scope != nil {
+		if name := dash0.StringValue(scope.Name); name != "" {
+			s.Tags[keyScopeName] = name
+		}
+		if version := dash0.StringValue(scope.Version); version != "" {
+			s.Tags[keyScopeVersion] = version
+		}
+	}
+
+	for _, event := range span.Events {
+		s.Annotations = append(s.Annotations, ZipkinAnnotation{
+			Timestamp: micros(event.TimeUnixNano),
+			Value:     zipkinAnnotationValue(&event),
+		})
+	}
+	return s
+}
+
+// zipkinAnnotationValue returns the event name, followed by its attributes as
+// a JSON object if it has any, e.g. `"exception":{"exception.type":"E"}`.
+func zipkinAnnotationValue(event *dash0.SpanEvent) string {
+	if len(event.Attributes) == 0 {
+		return event.Name
+	}
+	attributes := make(map[string]any, len(event.Attributes))
+	for _, kv := range event.Attributes {
+		_, attributes[kv.Key] = typedValue(kv.Value)
+	}
+	name, _ := json.Marshal(event.Name)
+	value, _ := json.Marshal(attributes)
+	return string(name) + ":" + string(value)
+}
+
+func zipkinSpanKind(kind dash0.SpanKind) string {
+	switch kind {
+	case dash0.SpanKindServer:
+		return "SERVER"
+	case dash0.SpanKindClient:
+		return "CLIENT"
+	case dash0.SpanKindProducer:
+		return "PRODUCER"
+	case dash0.SpanKindConsumer:
+		return "CONSUMER"
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,76 @@
+package export
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+)
+
+func TestToZipkin(t *testing.T) {
+	spans := ToZipkin(testResourceSpans())
+	if len(spans) != 2 {
+		t.Fatalf("expected 2 spans, got %d", len(spans))
+	}
+	root, child := spans[0], spans[1]
+
+	t.Run("maps span fields", func(t *testing.T) {
+		if root.TraceID != "4bf92f3577b34da6a3ce929d0e0e4736" || root.ID != "0101010101010101" || root.ParentID != "" {
+			t.Errorf("unexpected IDs %+v", root)
+		}
+		if root.Kind != "SERVER" || root.Timestamp != 1705329000000000 || root.Duration != 250000 {
+			t.Errorf("unexpected root span %+v", root)
+		}
+		if root.LocalEndpoint.ServiceName != "frontend" || root.RemoteEndpoint != nil {
+			t.Errorf("unexpected endpoints %+v %+v", root.LocalEndpoint, root.RemoteEndpoint)
+		}
+		if root.Tags["host.name"] != "web-1" || root.Tags["http.status_code"] != "500" || root.Tags["otel.scope.version"] != "1.2.3" {
+			t.Errorf("unexpected tags %v", root.Tags)
+		}
+		if _, ok := root.Tags["error"]; ok {
+			t.Error("expected no error tag for OK spans")
+		}
+	})
+
+	t.Run("maps client spans", func(t *testing.T) {
+		if child.Kind != "CLIENT" || child.ParentID != "0101010101010101" {
+			t.Errorf("unexpected child span %+v", child)
+		}
+		if child.RemoteEndpoint == nil || child.RemoteEndpoint.ServiceName != "backend" {
+			t.Errorf("expected remote endpoint backend, got %+v", child.RemoteEndpoint)
+		}
+		if child.Tags["error"] != "deadline exceeded" || child.Tags["otel.status_code"] != "ERROR" {
+			t.Errorf("unexpected status tags %v", child.Tags)
+		}
+	})
+
+	t.Run("maps events to annotations", func(t *testing.T) {
+		if len(child.Annotations) != 2 {
+			t.Fatalf("expected 2 annotations, got %+v", child.Annotations)
+		}
+		if child.Annotations[0].Value != "retry" {
+			t.Errorf("unexpected annotation %+v", child.Annotations[0])
+		}
+		if got := child.Annotations[1].Value; got != `"exception":{"exception.type":"Timeout"}` {
+			t.Errorf("unexpected annotation %s", got)
+		}
+	})
+}
+
+func TestWriteZipkin(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteZipkin(&buf, nil); err != nil || buf.String() != "[]\n" {
+		t.Errorf("expected empty array, got %q (%v)", buf.String(), err)
+	}
+
+	buf.Reset()
+	if err := WriteZipkin(&buf, testResourceSpans()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var spans []map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &spans); err != nil || len(spans) != 2 {
+		t.Fatalf("unexpected output %s (%v)", buf.String(), err)
+	}
+	if _, ok := spans[0]["parentId"]; ok {
+		t.Error("expected parentId to be omitted for root spans")
+	}
+}
@@ -0,0 +1,295 @@
+package dash0
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+)
+
+// FilterBuilder builds FilterCriteria for GetSpansRequest, GetLogRecordsRequest
+// and ViewSpec.Filter. All conditions are combined with a logical AND.
+// Use NewFilter to create one.
+//
+// Invalid conditions do not stop the chain; they are collected and reported
+// by Build.
+//
+// Example:
+//
+//	filter, err := dash0.NewFilter().
+//	    Is("service.name", "checkout").
+//	    IsOneOf("http.request.method", "POST", "PUT").
+//	    Gte("http.response.status_code", "500").
+//	    Build()
+//	if err != nil {
+//	    // handle error
+//	}
+//	request := &dash0.GetSpansRequest{Filter: &filter, ...}
+type FilterBuilder struct {
+	filters FilterCriteria
+}
+
+// NewFilter creates a new, empty FilterBuilder.
+func NewFilter() *FilterBuilder {
+	return &FilterBuilder{}
+}
+
+// Is adds a condition that the attribute exists and equals value.
+func (b *FilterBuilder) Is(key, value string) *FilterBuilder {
+	return b.Where(key, AttributeFilterOperatorIs, value)
+}
+
+// IsNot adds a condition that the attribute exists and does not equal value.
+func (b *FilterBuilder) IsNot(key, value string) *FilterBuilder {
+	return b.Where(key, AttributeFilterOperatorIsNot, value)
+}
+
+// IsSet adds a condition that the attribute exists.
+func (b *FilterBuilder) IsSet(key string) *FilterBuilder {
+	return b.Where(key, AttributeFilterOperatorIsSet)
+}
+
+// IsNotSet adds a condition that the attribute does not exist.
+func (b *FilterBuilder) IsNotSet(key string) *FilterBuilder {
+	return b.Where(key, AttributeFilterOperatorIsNotSet)
+}
+
+// IsOneOf adds a condition that the attribute exists and equals one of values.
+func (b *FilterBuilder) IsOneOf(key string, values ...string) *FilterBuilder {
+	return b.Where(key, AttributeFilterOperatorIsOneOf, values...)
+}
+
+// IsNotOneOf adds a condition that the attribute exists and equals none of values.
+func (b *FilterBuilder) IsNotOneOf(key string, values ...string) *FilterBuilder {
+	return b.Where(key, AttributeFilterOperatorIsNotOneOf, values...)
+}
+
+// Gt adds a condition that the attribute is greater than value.
+func (b *FilterBuilder) Gt(key, value string) *FilterBuilder {
+	return b.Where(key, AttributeFilterOperatorGt, value)
+}
+
+// Gte adds a condition that the attribute is greater than or equal to value.
+func (b *FilterBuilder) Gte(key, value string) *FilterBuilder {
+	return b.Where(key, AttributeFilterOperatorGte, value)
+}
+
+// Lt adds a condition that the attribute is less than value.
+func (b *FilterBuilder) Lt(key, value string) *FilterBuilder {
+	return b.Where(key, AttributeFilterOperatorLt, value)
+}
+
+// Lte adds a condition that the attribute is less than or equal to value.
+func (b *FilterBuilder) Lte(key, value string) *FilterBuilder {
+	return b.Where(key, AttributeFilterOperatorLte, value)
+}
+
+// Matches adds a condition that the attribute exists and matches the regular expression pattern.
+func (b *FilterBuilder) Matches(key, pattern string) *FilterBuilder {
+	return b.Where(key, AttributeFilterOperatorMatches, pattern)
+}
+
+// DoesNotMatch adds a condition that the attribute exists and does not match the regular expression pattern.
+func (b *FilterBuilder) DoesNotMatch(key, pattern string) *FilterBuilder {
+	return b.Where(key, AttributeFilterOperatorDoesNotMatch, pattern)
+}
+
+// Contains adds a condition that the attribute exists and contains value.
+func (b *FilterBuilder) Contains(key, value string) *FilterBuilder {
+	return b.Where(key, AttributeFilterOperatorContains, value)
+}
+
+// DoesNotContain adds a condition that the attribute exists and does not contain value.
+func (b *FilterBuilder) DoesNotContain(key, value string) *FilterBuilder {
+	return b.Where(key, AttributeFilterOperatorDoesNotContain, value)
+}
+
+// StartsWith adds a condition that the attribute exists and starts with value.
+func (b *FilterBuilder) StartsWith(key, value string) *FilterBuilder {
+	return b.Where(key, AttributeFilterOperatorStartsWith, value)
+}
+
+// DoesNotStartWith adds a condition that the attribute exists and does not start with value.
+func (b *FilterBuilder) DoesNotStartWith(key, value string) *FilterBuilder {
+	return b.Where(key, AttributeFilterOperatorDoesNotStartWith, value)
+}
+
+// EndsWith adds a condition that the attribute exists and ends with value.
+func (b *FilterBuilder) EndsWith(key, value string) *FilterBuilder {
+	return b.Where(key, AttributeFilterOperatorEndsWith, value)
+}
+
+// DoesNotEndWith adds a condition that the attribute exists and does not end with value.
+func (b *FilterBuilder) DoesNotEndWith(key, value string) *FilterBuilder {
+	return b.Where(key, AttributeFilterOperatorDoesNotEndWith, value)
+}
+
+// IsAny adds a condition that matches any value, whether the attribute exists or not.
+func (b *FilterBuilder) IsAny(key string) *FilterBuilder {
+	return b.Where(key, AttributeFilterOperatorIsAny)
+}
+
+// Where adds a condition with an arbitrary operator and string values.
+// Operators that take a single value expect exactly one value, the
+// is_one_of and is_not_one_of operators expect at least one, and is_set,
+// is_not_set and is_any expect none.
+func (b *FilterBuilder) Where(key string, operator AttributeFilterOperator, values ...string) *FilterBuilder {
+	items := make([]AttributeFilter_Values_Item, len(values))
+	for i, v := range values {
+		_ = items[i].FromAttributeFilterStringValue(v)
+	}
+	b.filters = append(b.filters, newAttributeFilter(key, operator, items))
+	return b
+}
+
+// WhereValue is like Where, but takes typed AnyValue values, e.g. to compare
+// against an integer or boolean attribute.
+func (b *FilterBuilder) WhereValue(key string, operator AttributeFilterOperator, values ...AnyValue) *FilterBuilder {
+	items := make([]AttributeFilter_Values_Item, len(values))
+	for i, v := range values {
+		_ = items[i].FromAttributeFilterAnyValue(v)
+	}
+	b.filters = append(b.filters, newAttributeFilter(key, operator, items))
+	return b
+}
+
+// Build validates all conditions and returns them as FilterCriteria.
+// All invalid conditions are reported together, joined with errors.Join.
+func (b *FilterBuilder) Build() (FilterCriteria, error) {
+	if err := ValidateFilter(b.filters); err != nil {
+		return nil, err
+	}
+	return append(FilterCriteria{}, b.filters...), nil
+}
+
+// newAttributeFilter places values in Value or Values depending on the operator.
+func newAttributeFilter(key string, operator AttributeFilterOperator, values []AttributeFilter_Values_Item) AttributeFilter {
+	filter := AttributeFilter{Key: key, Operator: operator}
+	if len(values) == 1 && operatorArity(operator) == arityOne {
+		// Both unions share the same JSON representation
+		filter.Value = &AttributeFilter_Value{union: values[0].union}
+		return filter
+	}
+	if len(values) > 0 {
+		filter.Values = &values
+	}
+	return filter
+}
+
+// FilterError describes an invalid condition in a FilterCriteria.
+type FilterError struct {
+	// Index is the position of the condition in the FilterCriteria.
+	Index int
+
+	// Key is the attribute key of the condition.
+	Key string
+
+	// Operator is the operator of the condition.
+	Operator AttributeFilterOperator
+
+	// Reason describes why the condition is invalid.
+	Reason string
+}
+
+// Error implements the error interface.
+func (e *FilterError) Error() string {
+	return fmt.Sprintf("dash0: invalid filter %d (%q %s): %s", e.Index, e.Key, e.Operator, e.Reason)
+}
+
+// ValidateFilter checks that every condition of a FilterCriteria is
+// well-formed: the key is not empty, the operator is known and the number of
+// values fits the operator. Regular expressions of matches and does_not_match
+// conditions must compile.
+// All invalid conditions are returned as *FilterError joined with errors.Join.
+func ValidateFilter(filter FilterCriteria) error {
+	var errs []error
+	for i, f := range filter {
+		if reason := validateAttributeFilter(f); reason != "" {
+			errs = append(errs, &FilterError{Index: i, Key: f.Key, Operator: f.Operator, Reason: reason})
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// validateAttributeFilter returns the reason why f is invalid, or an empty string.
+func validateAttributeFilter(f AttributeFilter) string {
+	if f.Key == "" {
+		return "key must not be empty"
+	}
+
+	hasValue := f.Value != nil
+	numValues := 0
+	if f.Values != nil {
+		numValues = len(*f.Values)
+	}
+
+	switch operatorArity(f.Operator) {
+	case arityNone:
+		if hasValue || numValues > 0 {
+			return "operator does not take a value"
+		}
+	case arityMany:
+		if hasValue {
+			return "operator takes values, not a single value"
+		}
+		if numValues == 0 {
+			return "operator requires at least one value"
+		}
+	case arityOne:
+		if numValues > 0 {
+			return "operator takes a single value, not values"
+		}
+		if !hasValue {
+			return "operator requires a value"
+		}
+		if f.Operator == AttributeFilterOperatorMatches || f.Operator == AttributeFilterOperatorDoesNotMatch {
+			if pattern, err := f.Value.AsAttributeFilterStringValue(); err == nil {
+				if _, err := regexp.Compile(pattern); err != nil {
+					return fmt.Sprintf("invalid regular expression: %v", err)
+				}
+			}
+		}
+	default:
+		return "unknown operator"
+	}
+	return ""
+}
+
+// arity is the number of values an operator takes.
+type arity int
+
+const (
+	arityUnknown arity = iota
+	arityNone
+	arityOne
+	arityMany
+)
+
+// operatorArity returns the number of values the operator takes.
+func operatorArity(operator AttributeFilterOperator) arity {
+	switch operator {
+	case AttributeFilterOperatorIsSet,
+		AttributeFilterOperatorIsNotSet,
+		AttributeFilterOperatorIsAny:
+		return arityNone
+	case AttributeFilterOperatorIsOneOf,
+		AttributeFilterOperatorIsNotOneOf:
+		return arityMany
+	case AttributeFilterOperatorIs,
+		AttributeFilterOperatorIsNot,
+		AttributeFilterOperatorGt,
+		AttributeFilterOperatorGte,
+		AttributeFilterOperatorLt,
+		AttributeFilterOperatorLte,
+		AttributeFilterOperatorMatches,
+		AttributeFilterOperatorDoesNotMatch,
+		AttributeFilterOperatorContains,
+		AttributeFilterOperatorDoesNotContain,
+		AttributeFilterOperatorStartsWith,
+		AttributeFilterOperatorDoesNotStartWith,
+		AttributeFilterOperatorEndsWith,
+		AttributeFilterOperatorDoesNotEndWith:
+		return arityOne
+	default:
+		return arityUnknown
+	}
+}
@@ -0,0 +1,397 @@
+package dash0
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Keys under which span and log record fields are exposed to filters
+// evaluated with FilterMatcher, in addition to their attributes.
+const (
+	FilterKeyTraceID           = "otel.trace.id"
+	FilterKeySpanID            = "otel.span.id"
+	FilterKeyParentSpanID      = "otel.parent.id"
+	FilterKeySpanName          = "otel.span.name"
+	FilterKeySpanKind          = "otel.span.kind"
+	FilterKeySpanStatusCode    = "otel.span.status.code"
+	FilterKeyLogBody           = "otel.log.body"
+	FilterKeyLogSeverityNumber = "otel.log.severity.number"
+	FilterKeyLogSeverityText   = "otel.log.severity.text"
+)
+
+// String returns the value as text. Integers and doubles are rendered in
+// decimal notation, booleans as "true" or "false" and bytes in base64.
+// An empty AnyValue renders as an empty string.
+func (v AnyValue) String() string {
+	switch {
+	case v.StringValue != nil:
+		return *v.StringValue
+	case v.IntValue != nil:
+		return *v.IntValue
+	case v.DoubleValue != nil:
+		return strconv.FormatFloat(*v.DoubleValue, 'f', -1, 64)
+	case v.BoolValue != nil:
+		return strconv.FormatBool(*v.BoolValue)
+	case v.BytesValue != nil:
+		return base64.StdEncoding.EncodeToString(*v.BytesValue)
+	default:
+		return ""
+	}
+}
+
+// FilterMatcher evaluates FilterCriteria in-process, e.g. to post-filter
+// fetched pages or to make test fakes honour request filters.
+// Use CompileFilter to create one.
+//
+// Conditions are combined with a logical AND. Values are compared as numbers
+// when both sides are numeric and as case-sensitive strings otherwise.
+// Regular expressions use Go syntax and match anywhere in the value unless
+// anchored.
+type FilterMatcher struct {
+	conditions []filterCondition
+}
+
+type filterCondition struct {
+	key      string
+	operator AttributeFilterOperator
+	values   []string
+	pattern  *regexp.Regexp
+}
+
+// CompileFilter validates the filter and compiles it into a FilterMatcher.
+// An empty filter matches everything.
+func CompileFilter(filter FilterCriteria) (*FilterMatcher, error) {
+	if err := ValidateFilter(filter); err != nil {
+		return nil, err
+	}
+
+	m := &FilterMatcher{conditions: make([]filterCondition, len(filter))}
+	for i, f := range filter {
+		c := filterCondition{key: f.Key, operator: f.Operator}
+		if f.Value != nil {
+			c.values = append(c.values, filterValueString(f.Value.union))
+		}
+		if f.Values != nil {
+			for _, v := range *f.Values {
+				c.values = append(c.values, filterValueString(v.union))
+			}
+		}
+		if f.Operator == AttributeFilterOperatorMatches || f.Operator == AttributeFilterOperatorDoesNotMatch {
+			// ValidateFilter only checks string patterns, typed values are checked here
+			pattern, err := regexp.Compile(c.values[0])
+			if err != nil {
+				return nil, &FilterError{Index: i, Key: f.Key, Operator: f.Operator, Reason: "invalid regular expression: " + err.Error()}
+			}
+			c.pattern = pattern
+		}
+		m.conditions[i] = c
+	}
+	return m, nil
+}
+
+// Match reports whether the attributes satisfy all conditions.
+// When several attribute lists are given, a key is looked up in order and the
+// first list containing it wins, so pass the most specific list first, e.g.
+// span, scope and then resource attributes.
+func (m *FilterMatcher) Match(attributes ...[]KeyValue) bool {
+	return m.match(func(key string) (string, bool) {
+		return lookupAttribute(key, attributes)
+	})
+}
+
+// MatchSpan reports whether a span satisfies all conditions. Keys are looked
+// up in the span fields (see FilterKeySpanName and friends), the span
+// attributes, the scope attributes and the resource attributes, in that order.
+// The resource and scope may be nil.
+func (m *FilterMatcher) MatchSpan(resource *Resource, scope *InstrumentationScope, span *Span) bool {
+	attributes := scopedAttributes(span.Attributes, resource, scope)
+	return m.match(func(key string) (string, bool) {
+		return lookupSpanKey(key, span, attributes)
+	})
+}
+
+// LookupSpanKey returns the value of a key for a span, looked up like
+// MatchSpan does: in the span fields, the span attributes, the scope
+// attributes and the resource attributes, in that order. The resource and
+// scope may be nil.
+func LookupSpanKey(resource *Resource, scope *InstrumentationScope, span *Span, key string) (string, bool) {
+	return lookupSpanKey(key, span, scopedAttributes(span.Attributes, resource, scope))
+}
+
+func lookupSpanKey(key string, span *Span, attributes [][]KeyValue) (string, bool) {
+	switch key {
+	case FilterKeyTraceID:
+		return hex.EncodeToString(span.TraceId), true
+	case FilterKeySpanID:
+		return hex.EncodeToString(span.SpanId), true
+	case FilterKeyParentSpanID:
+		if span.ParentSpanId == nil || len(*span.ParentSpanId) == 0 {
+			return "", false
+		}
+		return hex.EncodeToString(*span.ParentSpanId), true
+	case FilterKeySpanName:
+		return span.Name, true
+	case FilterKeySpanKind:
+		return strconv.Itoa(int(span.Kind)), true
+	case FilterKeySpanStatusCode:
+		return strconv.Itoa(int(span.Status.Code)), true
+	}
+	return lookupAttribute(key, attributes)
+}
+
+// MatchLogRecord reports whether a log record satisfies all conditions. Keys
+// are looked up in the log record fields (see FilterKeyLogBody and friends),
+// the log record attributes, the scope attributes and the resource attributes,
+// in that order. The resource and scope may be nil.
+func (m *FilterMatcher) MatchLogRecord(resource *Resource, scope *InstrumentationScope, record *LogRecord) bool {
+	attributes := scopedAttributes(record.Attributes, resource, scope)
+	return m.match(func(key string) (string, bool) {
+		return lookupLogRecordKey(key, record, attributes)
+	})
+}
+
+// LookupLogRecordKey returns the value of a key for a log record, looked up
+// like MatchLogRecord does: in the log record fields, the log record
+// attributes, the scope attributes and the resource attributes, in that order.
+// The resource and scope may be nil.
+func LookupLogRecordKey(resource *Resource, scope *InstrumentationScope, record *LogRecord, key string) (string, bool) {
+	return lookupLogRecordKey(key, record, scopedAttributes(record.Attributes, resource, scope))
+}
+
+func lookupLogRecordKey(key string, record *LogRecord, attributes [][]KeyValue) (string, bool) {
+	switch key {
+	case FilterKeyTraceID:
+		if record.TraceId == nil || len(*record.TraceId) == 0 {
+			return "", false
+		}
+		return hex.EncodeToString(*record.TraceId), true
+	case FilterKeySpanID:
+		if record.SpanId == nil || len(*record.SpanId) == 0 {
+			return "", false
+		}
+		return hex.EncodeToString(*record.SpanId), true
+	case FilterKeyLogBody:
+		if record.Body == nil {
+			return "", false
+		}
+		return record.Body.String(), true
+	case FilterKeyLogSeverityNumber:
+		if record.SeverityNumber == nil {
+			return "", false
+		}
+		return strconv.Itoa(int(*record.SeverityNumber)), true
+	case FilterKeyLogSeverityText:
+		if record.SeverityText == nil {
+			return "", false
+		}
+		return *record.SeverityText, true
+	}
+	return lookupAttribute(key, attributes)
+}
+
+// FilterResourceSpans returns copies of the resource spans that only contain
+// matching spans. Scopes and resources without matching spans are dropped.
+func (m *FilterMatcher) FilterResourceSpans(resourceSpans []ResourceSpans) []ResourceSpans {
+	var result []ResourceSpans
+	for _, rs := range resourceSpans {
+		var scopeSpans []ScopeSpans
+		for _, ss := range rs.ScopeSpans {
+			var spans []Span
+			for i := range ss.Spans {
+				if m.MatchSpan(&rs.Resource, ss.Scope, &ss.Spans[i]) {
+					spans = append(spans, ss.Spans[i])
+				}
+			}
+			if len(spans) > 0 {
+				ss.Spans = spans
+				scopeSpans = append(scopeSpans, ss)
+			}
+		}
+		if len(scopeSpans) > 0 {
+			rs.ScopeSpans = scopeSpans
+			result = append(result, rs)
+		}
+	}
+	return result
+}
+
+// FilterResourceLogs returns copies of the resource logs that only contain
+// matching log records. Scopes and resources without matching log records are
+// dropped.
+func (m *FilterMatcher) FilterResourceLogs(resourceLogs []ResourceLogs) []ResourceLogs {
+	var result []ResourceLogs
+	for _, rl := range resourceLogs {
+		var scopeLogs []ScopeLogs
+		for _, sl := range rl.ScopeLogs {
+			var records []LogRecord
+			for i := range sl.LogRecords {
+				if m.MatchLogRecord(&rl.Resource, sl.Scope, &sl.LogRecords[i]) {
+					records = append(records, sl.LogRecords[i])
+				}
+			}
+			if len(records) > 0 {
+				sl.LogRecords = records
+				scopeLogs = append(scopeLogs, sl)
+			}
+		}
+		if len(scopeLogs) > 0 {
+			rl.ScopeLogs = scopeLogs
+			result = append(result, rl)
+		}
+	}
+	return result
+}
+
+// match evaluates all conditions using lookup to resolve keys.
+func (m *FilterMatcher) match(lookup func(key string) (string, bool)) bool {
+	for _, c := range m.conditions {
+		value, ok := lookup(c.key)
+		if !c.match(value, ok) {
+			return false
+		}
+	}
+	return true
+}
+
+func (c *filterCondition) match(value string, exists bool) bool {
+	switch c.operator {
+	case AttributeFilterOperatorIsAny:
+		return true
+	case AttributeFilterOperatorIsSet:
+		return exists
+	case AttributeFilterOperatorIsNotSet:
+		return !exists
+	}
+	if !exists {
+		return false
+	}
+
+	switch c.operator {
+	case AttributeFilterOperatorIs:
+		return filterValuesEqual(value, c.values[0])
+	case AttributeFilterOperatorIsNot:
+		return !filterValuesEqual(value, c.values[0])
+	case AttributeFilterOperatorIsOneOf:
+		return c.isOneOf(value)
+	case AttributeFilterOperatorIsNotOneOf:
+		return !c.isOneOf(value)
+	case AttributeFilterOperatorGt:
+		return compareFilterValues(value, c.values[0]) > 0
+	case AttributeFilterOperatorGte:
+		return compareFilterValues(value, c.values[0]) >= 0
+	case AttributeFilterOperatorLt:
+		return compareFilterValues(value, c.values[0]) < 0
+	case AttributeFilterOperatorLte:
+		return compareFilterValues(value, c.values[0]) <= 0
+	case AttributeFilterOperatorMatches:
+		return c.pattern.MatchString(value)
+	case AttributeFilterOperatorDoesNotMatch:
+		return !c.pattern.MatchString(value)
+	case AttributeFilterOperatorContains:
+		return strings.Contains(value, c.values[0])
+	case AttributeFilterOperatorDoesNotContain:
+		return !strings.Contains(value, c.values[0])
+	case AttributeFilterOperatorStartsWith:
+		return strings.HasPrefix(value, c.values[0])
+	case AttributeFilterOperatorDoesNotStartWith:
+		return !strings.HasPrefix(value, c.values[0])
+	case AttributeFilterOperatorEndsWith:
+		return strings.HasSuffix(value, c.values[0])
+	case AttributeFilterOperatorDoesNotEndWith:
+		return !strings.HasSuffix(value, c.values[0])
+	default:
+		return false
+	}
+}
+
+func (c *filterCondition) isOneOf(value string) bool {
+	for _, v := range c.values {
+		if filterValuesEqual(value, v) {
+			return true
+		}
+	}
+	return false
+}
+
+// filterValuesEqual compares two values numerically if both are numbers and
+// as strings otherwise.
+func filterValuesEqual(a, b string) bool {
+	if x, y, ok := parseFilterNumbers(a, b); ok {
+		return x == y
+	}
+	return a == b
+}
+
+// compareFilterValues compares two values numerically if both are numbers and
+// lexically otherwise.
+func compareFilterValues(a, b string) int {
+	if x, y, ok := parseFilterNumbers(a, b); ok {
+		switch {
+		case x < y:
+			return -1
+		case x > y:
+			return 1
+		default:
+			return 0
+		}
+	}
+	return strings.Compare(a, b)
+}
+
+// parseFilterNumbers parses both values as finite numbers. NaN and infinities
+// are not valid attribute values, so they are compared as strings.
+func parseFilterNumbers(a, b string) (float64, float64, bool) {
+	x, err := strconv.ParseFloat(a, 64)
+	if err != nil || math.IsNaN(x) || math.IsInf(x, 0) {
+		return 0, 0, false
+	}
+	y, err := strconv.ParseFloat(b, 64)
+	if err != nil || math.IsNaN(y) || math.IsInf(y, 0) {
+		return 0, 0, false
+	}
+	return x, y, true
+}
+
+// filterValueString returns the textual form of a filter value union, which
+// holds either a plain string or an AnyValue.
+func filterValueString(raw json.RawMessage) string {
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		return s
+	}
+	var v AnyValue
+	if err := json.Unmarshal(raw, &v); err == nil {
+		return v.String()
+	}
+	return string(raw)
+}
+
+// scopedAttributes returns the attribute lists of an item, its scope and its
+// resource, most specific first.
+func scopedAttributes(attributes []KeyValue, resource *Resource, scope *InstrumentationScope) [][]KeyValue {
+	lists := [][]KeyValue{attributes}
+	if scope != nil {
+		lists = append(lists, scope.Attributes)
+	}
+	if resource != nil {
+		lists = append(lists, resource.Attributes)
+	}
+	return lists
+}
+
+// lookupAttribute returns the value of the first attribute with the given key.
+func lookupAttribute(key string, lists [][]KeyValue) (string, bool) {
+	for _, attributes := range lists {
+		for _, kv := range attributes {
+			if kv.Key == key {
+				return kv.Value.String(), true
+			}
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,217 @@
+package dash0
+
+import (
+	"testing"
+)
+
+func stringAttribute(key, value string) KeyValue {
+	return KeyValue{Key: key, Value: AnyValue{StringValue: Ptr(value)}}
+}
+
+func intAttribute(key, value string) KeyValue {
+	return KeyValue{Key: key, Value: AnyValue{IntValue: Ptr(value)}}
+}
+
+func TestFilterMatcher(t *testing.T) {
+	attributes := []KeyValue{
+		stringAttribute("service.name", "checkout"),
+		stringAttribute("url.path", "/api/orders"),
+		intAttribute("http.status_code", "503"),
+		{Key: "duration", Value: AnyValue{DoubleValue: Float64(1.5)}},
+		{Key: "retry", Value: AnyValue{BoolValue: Bool(true)}},
+		stringAttribute("ratio", "NaN"),
+		stringAttribute("limit", "Inf"),
+	}
+
+	tests := []struct {
+		expr string
+		want bool
+	}{
+		{`service.name = checkout`, true},
+		{`service.name = "Checkout"`, false},
+		{`service.name != checkout`, false},
+		{`missing != checkout`, false},
+		{`http.status_code = 503`, true},
+		{`http.status_code = 503.0`, true},
+		{`http.status_code >= 500`, true},
+		{`http.status_code > 503`, false},
+		{`http.status_code < 1000`, true},
+		{`duration <= 1.5`, true},
+		{`duration > 2`, false},
+		{`retry = true`, true},
+		{`ratio = NaN`, true},
+		{`ratio != NaN`, false},
+		{`limit = Inf`, true},
+		{`limit = "+Inf"`, false},
+		{`duration < "+Inf"`, false},
+		{`url.path ~ "^/api"`, true},
+		{`url.path !~ "orders$"`, false},
+		{`url.path contains order`, true},
+		{`url.path not contains order`, false},
+		{`url.path starts_with "/api/"`, true},
+		{`url.path not starts_with "/api/"`, false},
+		{`url.path ends_with orders`, true},
+		{`url.path not ends_with orders`, false},
+		{`service.name in (cart, checkout)`, true},
+		{`service.name not in (cart, checkout)`, false},
+		{`missing not in (cart)`, false},
+		{`service.name exists`, true},
+		{`missing exists`, false},
+		{`missing not exists`, true},
+		{`missing = *`, true},
+		{`service.name = checkout AND http.status_code < 500`, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			filter, err := ParseFilter(tt.expr)
+			if err != nil {
+				t.Fatalf("failed to parse filter: %v", err)
+			}
+			m, err := CompileFilter(filter)
+			if err != nil {
+				t.Fatalf("failed to compile filter: %v", err)
+			}
+			if got := m.Match(attributes); got != tt.want {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+
+	t.Run("empty filter matches everything", func(t *testing.T) {
+		m, err := CompileFilter(nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !m.Match(nil) {
+			t.Error("expected match")
+		}
+	})
+
+	t.Run("rejects invalid filters", func(t *testing.T) {
+		_, err := CompileFilter(FilterCriteria{{Key: "a", Operator: AttributeFilterOperatorIsOneOf}})
+		if err == nil {
+			t.Error("expected error")
+		}
+	})
+
+	t.Run("looks up attribute lists in order", func(t *testing.T) {
+		filter, _ := NewFilter().Is("service.name", "cart").Build()
+		m, _ := CompileFilter(filter)
+
+		own := []KeyValue{stringAttribute("service.name", "cart")}
+		resource := []KeyValue{stringAttribute("service.name", "checkout")}
+		if !m.Match(own, resource) {
+			t.Error("expected the first list to win")
+		}
+		if m.Match(resource, own) {
+			t.Error("expected the first list to win")
+		}
+	})
+}
+
+func TestFilterMatcher_Spans(t *testing.T) {
+	resourceSpans := []ResourceSpans{{
+		Resource: Resource{Attributes: []KeyValue{stringAttribute("service.name", "checkout")}},
+		ScopeSpans: []ScopeSpans{{
+			Spans: []Span{
+				{Name: "GET /cart", TraceId: []byte{0xab, 0xcd}, Status: SpanStatus{Code: 2}},
+				{Name: "SELECT", TraceId: []byte{0xab, 0xcd}, Attributes: []KeyValue{stringAttribute("db.system", "postgresql")}},
+			},
+		}},
+	}, {
+		Resource: Resource{Attributes: []KeyValue{stringAttribute("service.name", "cart")}},
+		ScopeSpans: []ScopeSpans{{
+			Spans: []Span{{Name: "GET /cart"}},
+		}},
+	}}
+
+	t.Run("matches span fields and resource attributes", func(t *testing.T) {
+		filter, _ := ParseFilter(`service.name = checkout AND otel.span.status.code = 2 AND otel.trace.id = abcd`)
+		m, _ := CompileFilter(filter)
+
+		result := m.FilterResourceSpans(resourceSpans)
+		if len(result) != 1 || len(result[0].ScopeSpans[0].Spans) != 1 || result[0].ScopeSpans[0].Spans[0].Name != "GET /cart" {
+			t.Errorf("unexpected result: %+v", result)
+		}
+		if len(resourceSpans[0].ScopeSpans[0].Spans) != 2 {
+			t.Error("input must not be modified")
+		}
+	})
+
+	t.Run("drops empty resources", func(t *testing.T) {
+		filter, _ := ParseFilter(`db.system exists`)
+		m, _ := CompileFilter(filter)
+
+		result := m.FilterResourceSpans(resourceSpans)
+		if len(result) != 1 || result[0].ScopeSpans[0].Spans[0].Name != "SELECT" {
+			t.Errorf("unexpected result: %+v", result)
+		}
+	})
+
+	t.Run("looks up keys of single spans", func(t *testing.T) {
+		rs := &resourceSpans[0]
+		span := &rs.ScopeSpans[0].Spans[1]
+		for key, want := range map[string]string{"otel.span.name": "SELECT", "db.system": "postgresql", "service.name": "checkout"} {
+			if got, ok := LookupSpanKey(&rs.Resource, nil, span, key); !ok || got != want {
+				t.Errorf("expected %s to be %q, got %q", key, want, got)
+			}
+		}
+		if _, ok := LookupSpanKey(nil, nil, span, "otel.parent.id"); ok {
+			t.Error("expected no parent ID")
+		}
+	})
+}
+
+func TestFilterMatcher_LogRecords(t *testing.T) {
+	resourceLogs := []ResourceLogs{{
+		ScopeLogs: []ScopeLogs{{
+			Scope: &InstrumentationScope{Attributes: []KeyValue{stringAttribute("logger", "app")}},
+			LogRecords: []LogRecord{
+				{Body: &AnyValue{StringValue: Ptr("payment failed")}, SeverityNumber: Ptr(SeverityNumber(17)), SeverityText: Ptr("ERROR")},
+				{Body: &AnyValue{StringValue: Ptr("payment ok")}, SeverityNumber: Ptr(SeverityNumber(9))},
+				{},
+			},
+		}},
+	}}
+
+	filter, _ := ParseFilter(`otel.log.body contains failed AND otel.log.severity.number >= 17 AND logger = app`)
+	m, _ := CompileFilter(filter)
+
+	result := m.FilterResourceLogs(resourceLogs)
+	if len(result) != 1 || len(result[0].ScopeLogs[0].LogRecords) != 1 {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+	if StringValue(result[0].ScopeLogs[0].LogRecords[0].SeverityText) != "ERROR" {
+		t.Errorf("unexpected log record: %+v", result[0].ScopeLogs[0].LogRecords[0])
+	}
+
+	scope := resourceLogs[0].ScopeLogs[0].Scope
+	record := &resourceLogs[0].ScopeLogs[0].LogRecords[0]
+	for key, want := range map[string]string{"otel.log.body": "payment failed", "otel.log.severity.number": "17", "logger": "app"} {
+		if got, ok := LookupLogRecordKey(nil, scope, record, key); !ok || got != want {
+			t.Errorf("expected %s to be %q, got %q", key, want, got)
+		}
+	}
+	if _, ok := LookupLogRecordKey(nil, nil, &resourceLogs[0].ScopeLogs[0].LogRecords[2], "otel.log.body"); ok {
+		t.Error("expected no body")
+	}
+}
+
+func TestAnyValue_String(t *testing.T) {
+	tests := []struct {
+		value AnyValue
+		want  string
+	}{
+		{AnyValue{StringValue: Ptr("a")}, "a"},
+		{AnyValue{IntValue: Ptr("42")}, "42"},
+		{AnyValue{DoubleValue: Float64(0.25)}, "0.25"},
+		{AnyValue{BoolValue: Bool(false)}, "false"},
+		{AnyValue{BytesValue: Ptr([]byte("hi"))}, "aGk="},
+		{AnyValue{}, ""},
+	}
+	for _, tt := range tests {
+		if got := tt.value.String(); got != tt.want {
+			t.Errorf("expected %q, got %q", tt.want, got)
+		}
+	}
+}
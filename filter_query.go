@@ -0,0 +1,436 @@
+package dash0
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// FilterSyntaxError is returned by ParseFilter for malformed filter expressions.
+type FilterSyntaxError struct {
+	// Column is the 1-based position, in characters, at which the error was detected.
+	Column int
+
+	// Message describes the error.
+	Message string
+}
+
+// Error implements the error interface.
+func (e *FilterSyntaxError) Error() string {
+	return fmt.Sprintf("dash0: filter syntax error at column %d: %s", e.Column, e.Message)
+}
+
+// ParseFilter parses a filter expression into FilterCriteria.
+//
+// An expression is a list of conditions joined with AND (case-insensitive).
+// Each condition starts with an attribute key, either bare (e.g. service.name)
+// or double-quoted, followed by one of these operators:
+//
+//	key = value             is
+//	key != value            is_not
+//	key > value             gt (also >=, <, <=)
+//	key ~ pattern           matches
+//	key !~ pattern          does_not_match
+//	key in (v1, v2)         is_one_of
+//	key not in (v1, v2)     is_not_one_of
+//	key exists              is_set
+//	key not exists          is_not_set
+//	key contains value      contains (also starts_with, ends_with)
+//	key not contains value  does_not_contain (also not starts_with, not ends_with)
+//	key = *                 is_any
+//
+// Values are double-quoted strings with Go escape sequences, or bare words
+// such as 500 or GET. All values are sent as strings.
+//
+// Example:
+//
+//	filter, err := dash0.ParseFilter(`service.name = "checkout" AND http.status_code >= 500 AND url.path ~ "^/api"`)
+//
+// Errors are reported as *FilterSyntaxError.
+func ParseFilter(s string) (FilterCriteria, error) {
+	tokens, err := lexFilter(s)
+	if err != nil {
+		return nil, err
+	}
+	p := &filterParser{tokens: tokens}
+	return p.parse()
+}
+
+// FormatFilter renders FilterCriteria as a filter expression that can be read
+// back with ParseFilter. Typed AnyValue values are rendered as plain values, so
+// they are read back as strings.
+func FormatFilter(filter FilterCriteria) string {
+	conditions := make([]string, len(filter))
+	for i, f := range filter {
+		conditions[i] = formatCondition(f)
+	}
+	return strings.Join(conditions, " AND ")
+}
+
+type filterTokenKind int
+
+const (
+	filterTokenEOF filterTokenKind = iota
+	filterTokenWord
+	filterTokenString
+	filterTokenOperator
+	filterTokenLParen
+	filterTokenRParen
+	filterTokenComma
+	filterTokenStar
+)
+
+type filterToken struct {
+	kind filterTokenKind
+	text string
+	col  int
+}
+
+// isFilterWordChar reports whether r may appear in a bare key or value.
+func isFilterWordChar(r rune) bool {
+	switch {
+	case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		return true
+	}
+	return strings.ContainsRune("_.-+/:@", r)
+}
+
+// lexFilter splits a filter expression into tokens.
+func lexFilter(s string) ([]filterToken, error) {
+	var tokens []filterToken
+	col := 1
+	for i := 0; i < len(s); {
+		r, size := utf8.DecodeRuneInString(s[i:])
+		start := col
+		switch {
+		case r == ' ' || r == '\t' || r == '\n' || r == '\r':
+			i += size
+			col++
+		case r == '"':
+			end, n, err := scanFilterString(s[i:])
+			if err != nil {
+				return nil, &FilterSyntaxError{Column: start, Message: err.Error()}
+			}
+			text, err := strconv.Unquote(s[i : i+end])
+			if err != nil {
+				return nil, &FilterSyntaxError{Column: start, Message: "invalid quoted string"}
+			}
+			tokens = append(tokens, filterToken{kind: filterTokenString, text: text, col: start})
+			i += end
+			col += n
+		case r == '(':
+			tokens = append(tokens, filterToken{kind: filterTokenLParen, text: "(", col: start})
+			i++
+			col++
+		case r == ')':
+			tokens = append(tokens, filterToken{kind: filterTokenRParen, text: ")", col: start})
+			i++
+			col++
+		case r == ',':
+			tokens = append(tokens, filterToken{kind: filterTokenComma, text: ",", col: start})
+			i++
+			col++
+		case r == '*':
+			tokens = append(tokens, filterToken{kind: filterTokenStar, text: "*", col: start})
+			i++
+			col++
+		case strings.ContainsRune("=!<>~", r):
+			op := s[i : i+1]
+			if i+1 < len(s) && s[i+1] == '=' && op != "=" && op != "~" {
+				op = s[i : i+2]
+			} else if op == "!" && i+1 < len(s) && s[i+1] == '~' {
+				op = "!~"
+			}
+			if op == "!" {
+				return nil, &FilterSyntaxError{Column: start, Message: `unexpected "!"`}
+			}
+			tokens = append(tokens, filterToken{kind: filterTokenOperator, text: op, col: start})
+			i += len(op)
+			col += len(op)
+		case isFilterWordChar(r):
+			j := i
+			for j < len(s) {
+				r, size := utf8.DecodeRuneInString(s[j:])
+				if !isFilterWordChar(r) {
+					break
+				}
+				j += size
+			}
+			tokens = append(tokens, filterToken{kind: filterTokenWord, text: s[i:j], col: start})
+			col += j - i
+			i = j
+		default:
+			return nil, &FilterSyntaxError{Column: start, Message: fmt.Sprintf("unexpected character %q", r)}
+		}
+	}
+	return append(tokens, filterToken{kind: filterTokenEOF, col: col}), nil
+}
+
+// scanFilterString returns the byte length and the character length of the
+// double-quoted string at the start of s, including the quotes.
+func scanFilterString(s string) (int, int, error) {
+	n := 1
+	for i := 1; i < len(s); {
+		r, size := utf8.DecodeRuneInString(s[i:])
+		i += size
+		n++
+		switch r {
+		case '\\':
+			if i < len(s) {
+				_, size := utf8.DecodeRuneInString(s[i:])
+				i += size
+				n++
+			}
+		case '"':
+			return i, n, nil
+		}
+	}
+	return 0, 0, fmt.Errorf("unterminated string")
+}
+
+type filterParser struct {
+	tokens []filterToken
+	pos    int
+}
+
+func (p *filterParser) peek() filterToken {
+	return p.tokens[p.pos]
+}
+
+func (p *filterParser) next() filterToken {
+	t := p.tokens[p.pos]
+	if t.kind != filterTokenEOF {
+		p.pos++
+	}
+	return t
+}
+
+func (p *filterParser) parse() (FilterCriteria, error) {
+	if p.peek().kind == filterTokenEOF {
+		return nil, nil
+	}
+
+	var filter FilterCriteria
+	for {
+		f, col, err := p.parseCondition()
+		if err != nil {
+			return nil, err
+		}
+		if reason := validateAttributeFilter(f); reason != "" {
+			return nil, &FilterSyntaxError{Column: col, Message: reason}
+		}
+		filter = append(filter, f)
+
+		t := p.next()
+		switch {
+		case t.kind == filterTokenEOF:
+			return filter, nil
+		case t.kind == filterTokenWord && strings.EqualFold(t.text, "and"):
+			continue
+		case t.kind == filterTokenWord && strings.EqualFold(t.text, "or"):
+			return nil, &FilterSyntaxError{Column: t.col, Message: "OR is not supported, conditions can only be combined with AND"}
+		default:
+			return nil, &FilterSyntaxError{Column: t.col, Message: fmt.Sprintf("expected AND, got %s", describeFilterToken(t))}
+		}
+	}
+}
+
+// parseCondition parses a single condition and returns it together with the
+// column at which it starts.
+func (p *filterParser) parseCondition() (AttributeFilter, int, error) {
+	key := p.next()
+	if key.kind != filterTokenWord && key.kind != filterTokenString {
+		return AttributeFilter{}, 0, &FilterSyntaxError{Column: key.col, Message: fmt.Sprintf("expected attribute key, got %s", describeFilterToken(key))}
+	}
+
+	operator, multi, err := p.parseOperator()
+	if err != nil {
+		return AttributeFilter{}, 0, err
+	}
+
+	var values []string
+	switch {
+	case operatorArity(operator) == arityNone:
+	case multi:
+		values, err = p.parseValueList()
+	default:
+		var value string
+		value, err = p.parseValue()
+		values = []string{value}
+	}
+	if err != nil {
+		return AttributeFilter{}, 0, err
+	}
+
+	items := make([]AttributeFilter_Values_Item, len(values))
+	for i, v := range values {
+		_ = items[i].FromAttributeFilterStringValue(v)
+	}
+	return newAttributeFilter(key.text, operator, items), key.col, nil
+}
+
+// symbolOperators maps operator symbols to their AttributeFilterOperator.
+var symbolOperators = map[string]AttributeFilterOperator{
+	"=":  AttributeFilterOperatorIs,
+	"!=": AttributeFilterOperatorIsNot,
+	">":  AttributeFilterOperatorGt,
+	">=": AttributeFilterOperatorGte,
+	"<":  AttributeFilterOperatorLt,
+	"<=": AttributeFilterOperatorLte,
+	"~":  AttributeFilterOperatorMatches,
+	"!~": AttributeFilterOperatorDoesNotMatch,
+}
+
+// keywordOperators maps operator keywords to their AttributeFilterOperator,
+// without and with a preceding "not".
+var keywordOperators = map[string][2]AttributeFilterOperator{
+	"in":          {AttributeFilterOperatorIsOneOf, AttributeFilterOperatorIsNotOneOf},
+	"exists":      {AttributeFilterOperatorIsSet, AttributeFilterOperatorIsNotSet},
+	"contains":    {AttributeFilterOperatorContains, AttributeFilterOperatorDoesNotContain},
+	"starts_with": {AttributeFilterOperatorStartsWith, AttributeFilterOperatorDoesNotStartWith},
+	"ends_with":   {AttributeFilterOperatorEndsWith, AttributeFilterOperatorDoesNotEndWith},
+}
+
+// parseOperator parses an operator and reports whether it takes a value list.
+func (p *filterParser) parseOperator() (AttributeFilterOperator, bool, error) {
+	t := p.next()
+	if t.kind == filterTokenOperator {
+		operator := symbolOperators[t.text]
+		if operator == AttributeFilterOperatorIs && p.peek().kind == filterTokenStar {
+			p.next()
+			return AttributeFilterOperatorIsAny, false, nil
+		}
+		return operator, false, nil
+	}
+
+	if t.kind == filterTokenWord {
+		negated := strings.EqualFold(t.text, "not")
+		if negated {
+			t = p.next()
+		}
+		if t.kind == filterTokenWord {
+			if ops, ok := keywordOperators[strings.ToLower(t.text)]; ok {
+				operator := ops[0]
+				if negated {
+					operator = ops[1]
+				}
+				return operator, strings.EqualFold(t.text, "in"), nil
+			}
+		}
+	}
+	return "", false, &FilterSyntaxError{Column: t.col, Message: fmt.Sprintf("expected operator, got %s", describeFilterToken(t))}
+}
+
+func (p *filterParser) parseValue() (string, error) {
+	t := p.next()
+	if t.kind != filterTokenWord && t.kind != filterTokenString {
+		return "", &FilterSyntaxError{Column: t.col, Message: fmt.Sprintf("expected value, got %s", describeFilterToken(t))}
+	}
+	return t.text, nil
+}
+
+func (p *filterParser) parseValueList() ([]string, error) {
+	if t := p.next(); t.kind != filterTokenLParen {
+		return nil, &FilterSyntaxError{Column: t.col, Message: fmt.Sprintf(`expected "(", got %s`, describeFilterToken(t))}
+	}
+	var values []string
+	for {
+		value, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+
+		t := p.next()
+		switch t.kind {
+		case filterTokenComma:
+			continue
+		case filterTokenRParen:
+			return values, nil
+		default:
+			return nil, &FilterSyntaxError{Column: t.col, Message: fmt.Sprintf(`expected "," or ")", got %s`, describeFilterToken(t))}
+		}
+	}
+}
+
+func describeFilterToken(t filterToken) string {
+	switch t.kind {
+	case filterTokenEOF:
+		return "end of input"
+	case filterTokenString:
+		return strconv.Quote(t.text)
+	default:
+		return fmt.Sprintf("%q", t.text)
+	}
+}
+
+var (
+	bareFilterKeyPattern   = regexp.MustCompile(`^[A-Za-z0-9_.\-+/:@]+$`)
+	bareFilterValuePattern = regexp.MustCompile(`^[+-]?[0-9]+(\.[0-9]+)?$`)
+)
+
+func formatCondition(f AttributeFilter) string {
+	key := f.Key
+	if !bareFilterKeyPattern.MatchString(key) {
+		key = strconv.Quote(key)
+	}
+
+	var values []string
+	if f.Value != nil {
+		values = append(values, formatFilterValue(f.Value.union))
+	}
+	if f.Values != nil {
+		for _, v := range *f.Values {
+			values = append(values, formatFilterValue(v.union))
+		}
+	}
+
+	for symbol, operator := range symbolOperators {
+		if operator == f.Operator {
+			return fmt.Sprintf("%s %s %s", key, symbol, strings.Join(values, ", "))
+		}
+	}
+	for keyword, ops := range keywordOperators {
+		prefix := ""
+		if ops[1] == f.Operator {
+			prefix = "not "
+		} else if ops[0] != f.Operator {
+			continue
+		}
+		switch keyword {
+		case "in":
+			return fmt.Sprintf("%s %sin (%s)", key, prefix, strings.Join(values, ", "))
+		case "exists":
+			return fmt.Sprintf("%s %sexists", key, prefix)
+		default:
+			return fmt.Sprintf("%s %s%s %s", key, prefix, keyword, strings.Join(values, ", "))
+		}
+	}
+	if f.Operator == AttributeFilterOperatorIsAny {
+		return key + " = *"
+	}
+	return strings.TrimSpace(fmt.Sprintf("%s %s %s", key, f.Operator, strings.Join(values, ", ")))
+}
+
+// formatFilterValue renders the JSON form of a filter value union.
+func formatFilterValue(raw json.RawMessage) string {
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		if bareFilterValuePattern.MatchString(s) {
+			return s
+		}
+		return strconv.Quote(s)
+	}
+
+	var v AnyValue
+	if err := json.Unmarshal(raw, &v); err != nil {
+		return strconv.Quote(string(raw))
+	}
+	if v.StringValue != nil || v.BytesValue != nil || v.String() == "" {
+		return strconv.Quote(v.String())
+	}
+	return v.String()
+}
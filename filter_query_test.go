@@ -0,0 +1,158 @@
+package dash0
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseFilter(t *testing.T) {
+	t.Run("parses conditions joined with AND", func(t *testing.T) {
+		filter, err := ParseFilter(`service.name = "checkout" AND http.status_code >= 500 and url.path ~ "^/api"`)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		want, _ := NewFilter().
+			Is("service.name", "checkout").
+			Gte("http.status_code", "500").
+			Matches("url.path", "^/api").
+			Build()
+		if FormatFilter(filter) != FormatFilter(want) || len(filter) != 3 {
+			t.Errorf("unexpected filter: %s", FormatFilter(filter))
+		}
+	})
+
+	t.Run("supports every operator", func(t *testing.T) {
+		tests := []struct {
+			input    string
+			operator AttributeFilterOperator
+		}{
+			{`a = 1`, AttributeFilterOperatorIs},
+			{`a != 1`, AttributeFilterOperatorIsNot},
+			{`a > 1`, AttributeFilterOperatorGt},
+			{`a >= 1`, AttributeFilterOperatorGte},
+			{`a < 1`, AttributeFilterOperatorLt},
+			{`a <= 1`, AttributeFilterOperatorLte},
+			{`a ~ "x"`, AttributeFilterOperatorMatches},
+			{`a !~ "x"`, AttributeFilterOperatorDoesNotMatch},
+			{`a in (1, "2")`, AttributeFilterOperatorIsOneOf},
+			{`a NOT IN (1)`, AttributeFilterOperatorIsNotOneOf},
+			{`a exists`, AttributeFilterOperatorIsSet},
+			{`a not exists`, AttributeFilterOperatorIsNotSet},
+			{`a contains x`, AttributeFilterOperatorContains},
+			{`a not contains x`, AttributeFilterOperatorDoesNotContain},
+			{`a starts_with x`, AttributeFilterOperatorStartsWith},
+			{`a not starts_with x`, AttributeFilterOperatorDoesNotStartWith},
+			{`a ends_with x`, AttributeFilterOperatorEndsWith},
+			{`a not ends_with x`, AttributeFilterOperatorDoesNotEndWith},
+			{`a = *`, AttributeFilterOperatorIsAny},
+		}
+		for _, tt := range tests {
+			t.Run(tt.input, func(t *testing.T) {
+				filter, err := ParseFilter(tt.input)
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if len(filter) != 1 || filter[0].Operator != tt.operator {
+					t.Errorf("expected %s, got %+v", tt.operator, filter)
+				}
+			})
+		}
+	})
+
+	t.Run("supports quoted keys and escapes", func(t *testing.T) {
+		filter, err := ParseFilter(`"my key" = "say \"hi\""`)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		value, _ := filter[0].Value.AsAttributeFilterStringValue()
+		if filter[0].Key != "my key" || value != `say "hi"` {
+			t.Errorf("unexpected filter: %s", FormatFilter(filter))
+		}
+	})
+
+	t.Run("returns nil for empty input", func(t *testing.T) {
+		filter, err := ParseFilter("  ")
+		if err != nil || filter != nil {
+			t.Errorf("expected nil filter, got %v, %v", filter, err)
+		}
+	})
+
+	t.Run("reports errors with columns", func(t *testing.T) {
+		tests := []struct {
+			input  string
+			column int
+		}{
+			{`a = `, 5},
+			{`a = 1 b = 2`, 7},
+			{`a = 1 OR b = 2`, 7},
+			{`a in (1, 2`, 11},
+			{`a is 1`, 3},
+			{`a = "unterminated`, 5},
+			{`a = 1 AND b ~ "("`, 11},
+			{`a = 1 AND = 2`, 11},
+			{`a # 1`, 3},
+			{`"ä" = 1 AND b`, 14},
+		}
+		for _, tt := range tests {
+			t.Run(tt.input, func(t *testing.T) {
+				_, err := ParseFilter(tt.input)
+				var syntaxErr *FilterSyntaxError
+				if !errors.As(err, &syntaxErr) {
+					t.Fatalf("expected FilterSyntaxError, got %v", err)
+				}
+				if syntaxErr.Column != tt.column {
+					t.Errorf("expected column %d, got %d (%v)", tt.column, syntaxErr.Column, err)
+				}
+			})
+		}
+	})
+}
+
+func TestFormatFilter(t *testing.T) {
+	t.Run("round-trips through ParseFilter", func(t *testing.T) {
+		filter, err := NewFilter().
+			Is("service.name", "checkout").
+			IsNot("my key", "a \"b\"").
+			IsOneOf("http.request.method", "GET", "POST").
+			IsNotOneOf("code", "1").
+			Gt("duration", "1.5").
+			DoesNotMatch("url.path", `^/health\b`).
+			StartsWith("a", "*").
+			DoesNotEndWith("a", "").
+			IsSet("error.type").
+			IsNotSet("b").
+			IsAny("c").
+			Build()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		text := FormatFilter(filter)
+		want := `service.name = "checkout" AND "my key" != "a \"b\"" AND http.request.method in ("GET", "POST") AND ` +
+			`code not in (1) AND duration > 1.5 AND url.path !~ "^/health\\b" AND a starts_with "*" AND ` +
+			`a not ends_with "" AND error.type exists AND b not exists AND c = *`
+		if text != want {
+			t.Errorf("unexpected text:\n%s\nwant:\n%s", text, want)
+		}
+
+		parsed, err := ParseFilter(text)
+		if err != nil {
+			t.Fatalf("failed to parse formatted filter: %v", err)
+		}
+		if FormatFilter(parsed) != text {
+			t.Errorf("round trip changed the filter:\n%s", FormatFilter(parsed))
+		}
+	})
+
+	t.Run("renders typed values", func(t *testing.T) {
+		filter, _ := NewFilter().
+			WhereValue("code", AttributeFilterOperatorGte, AnyValue{IntValue: Ptr("500")}).
+			WhereValue("ok", AttributeFilterOperatorIs, AnyValue{BoolValue: Bool(true)}).
+			Build()
+
+		if text := FormatFilter(filter); text != "code >= 500 AND ok = true" {
+			t.Errorf("unexpected text: %s", text)
+		}
+	})
+}
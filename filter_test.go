@@ -0,0 +1,144 @@
+package dash0
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestFilterBuilder(t *testing.T) {
+	t.Run("builds conditions for every operator", func(t *testing.T) {
+		filter, err := NewFilter().
+			Is("a", "1").
+			IsNot("a", "1").
+			IsSet("a").
+			IsNotSet("a").
+			IsOneOf("a", "1", "2").
+			IsNotOneOf("a", "1").
+			Gt("a", "1").
+			Gte("a", "1").
+			Lt("a", "1").
+			Lte("a", "1").
+			Matches("a", "^1$").
+			DoesNotMatch("a", "^1$").
+			Contains("a", "1").
+			DoesNotContain("a", "1").
+			StartsWith("a", "1").
+			DoesNotStartWith("a", "1").
+			EndsWith("a", "1").
+			DoesNotEndWith("a", "1").
+			IsAny("a").
+			Build()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(filter) != 19 {
+			t.Fatalf("expected 19 conditions, got %d", len(filter))
+		}
+
+		seen := make(map[AttributeFilterOperator]bool)
+		for _, f := range filter {
+			seen[f.Operator] = true
+		}
+		if len(seen) != 19 {
+			t.Errorf("expected 19 distinct operators, got %d", len(seen))
+		}
+	})
+
+	t.Run("serializes single and multiple values", func(t *testing.T) {
+		filter, err := NewFilter().
+			Is("service.name", "checkout").
+			IsOneOf("http.request.method", "GET", "POST").
+			IsSet("error.type").
+			Build()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		data, err := json.Marshal(filter)
+		if err != nil {
+			t.Fatalf("failed to marshal: %v", err)
+		}
+		want := `[{"key":"service.name","operator":"is","value":"checkout"},` +
+			`{"key":"http.request.method","operator":"is_one_of","values":["GET","POST"]},` +
+			`{"key":"error.type","operator":"is_set"}]`
+		if string(data) != want {
+			t.Errorf("unexpected JSON:\n%s\nwant:\n%s", data, want)
+		}
+	})
+
+	t.Run("supports typed values", func(t *testing.T) {
+		filter, err := NewFilter().
+			WhereValue("http.response.status_code", AttributeFilterOperatorGte, AnyValue{IntValue: Ptr("500")}).
+			Build()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		value, err := filter[0].Value.AsAttributeFilterAnyValue()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if StringValue(value.IntValue) != "500" {
+			t.Errorf("unexpected value: %+v", value)
+		}
+	})
+
+	t.Run("rejects invalid conditions", func(t *testing.T) {
+		tests := []struct {
+			name    string
+			builder *FilterBuilder
+		}{
+			{"empty key", NewFilter().Is("", "x")},
+			{"is_set with value", NewFilter().Where("a", AttributeFilterOperatorIsSet, "x")},
+			{"is_any with value", NewFilter().Where("a", AttributeFilterOperatorIsAny, "x")},
+			{"is_one_of without values", NewFilter().IsOneOf("a")},
+			{"is with two values", NewFilter().Where("a", AttributeFilterOperatorIs, "x", "y")},
+			{"is without value", NewFilter().Where("a", AttributeFilterOperatorIs)},
+			{"invalid regex", NewFilter().Matches("a", "(")},
+			{"unknown operator", NewFilter().Where("a", "equals", "x")},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				_, err := tt.builder.Build()
+				var filterErr *FilterError
+				if !errors.As(err, &filterErr) {
+					t.Fatalf("expected FilterError, got %v", err)
+				}
+				if filterErr.Index != 0 {
+					t.Errorf("expected index 0, got %d", filterErr.Index)
+				}
+			})
+		}
+	})
+
+	t.Run("reports all invalid conditions", func(t *testing.T) {
+		_, err := NewFilter().IsSet("").Is("ok", "x").IsOneOf("b").Build()
+		if err == nil {
+			t.Fatal("expected error")
+		}
+
+		joined, ok := err.(interface{ Unwrap() []error })
+		if !ok || len(joined.Unwrap()) != 2 {
+			t.Fatalf("expected 2 errors, got %v", err)
+		}
+	})
+}
+
+func TestValidateFilter(t *testing.T) {
+	t.Run("accepts hand-built filters", func(t *testing.T) {
+		var value AttributeFilter_Value
+		_ = value.FromAttributeFilterStringValue("checkout")
+
+		err := ValidateFilter(FilterCriteria{{Key: "service.name", Operator: AttributeFilterOperatorIs, Value: &value}})
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("accepts empty filters", func(t *testing.T) {
+		if err := ValidateFilter(nil); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+}
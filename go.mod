@@ -4,10 +4,19 @@ go 1.25.1
 
 require (
 	github.com/oapi-codegen/runtime v1.1.2
-	golang.org/x/sync v0.10.0
+	go.opentelemetry.io/proto/otlp v1.9.0
+	golang.org/x/sync v0.16.0
+	google.golang.org/protobuf v1.36.10
 )
 
 require (
 	github.com/apapsch/go-jsonmerge/v2 v2.0.0 // indirect
-	github.com/google/uuid v1.5.0 // indirect
+	github.com/google/uuid v1.6.0 // indirect
+	github.com/grpc-ecosystem/grpc-gateway/v2 v2.27.2 // indirect
+	golang.org/x/net v0.43.0 // indirect
+	golang.org/x/sys v0.35.0 // indirect
+	golang.org/x/text v0.28.0 // indirect
+	google.golang.org/genproto/googleapis/api v0.0.0-20250825161204-c5933d9347a5 // indirect
+	google.golang.org/genproto/googleapis/rpc v0.0.0-20250825161204-c5933d9347a5 // indirect
+	google.golang.org/grpc v1.75.1 // indirect
 )
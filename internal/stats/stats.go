@@ -0,0 +1,51 @@
+// Package stats provides the statistics shared by the aggregating packages of
+// the client, such as servicemap and red.
+package stats
+
+import (
+	"math"
+	"slices"
+	"time"
+)
+
+// Durations is a sample of durations for computing percentiles. The zero
+// value is an empty sample.
+type Durations struct {
+	values []time.Duration
+	sum    time.Duration
+	sorted bool
+}
+
+// Add adds a duration to the sample.
+func (d *Durations) Add(v time.Duration) {
+	d.values = append(d.values, v)
+	d.sum += v
+	d.sorted = false
+}
+
+// Len returns the number of durations in the sample.
+func (d *Durations) Len() int {
+	return len(d.values)
+}
+
+// Mean returns the arithmetic mean of the sample, or 0 for an empty sample.
+func (d *Durations) Mean() time.Duration {
+	if len(d.values) == 0 {
+		return 0
+	}
+	return d.sum / time.Duration(len(d.values))
+}
+
+// Percentile returns the p-th percentile of the sample, for p between 0 and
+// 100, using the nearest-rank method. It returns 0 for an empty sample.
+func (d *Durations) Percentile(p float64) time.Duration {
+	if len(d.values) == 0 {
+		return 0
+	}
+	if !d.sorted {
+		slices.Sort(d.values)
+		d.sorted = true
+	}
+	rank := int(math.Ceil(p / 100 * float64(len(d.values))))
+	return d.values[min(max(rank, 1), len(d.values))-1]
+}
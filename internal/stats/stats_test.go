@@ -0,0 +1,31 @@
+package stats
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDurations(t *testing.T) {
+	var d Durations
+	if got, mean := d.Percentile(50), d.Mean(); got != 0 || mean != 0 {
+		t.Errorf("expected 0 for an empty sample, got %v and %v", got, mean)
+	}
+
+	for _, v := range []time.Duration{5, 1, 4, 2, 3, 10, 9, 8, 7, 6} {
+		d.Add(v)
+	}
+	for p, want := range map[float64]time.Duration{0: 1, 10: 1, 50: 5, 95: 10, 99: 10, 100: 10} {
+		if got := d.Percentile(p); got != want {
+			t.Errorf("expected p%v to be %v, got %v", p, want, got)
+		}
+	}
+
+	if got := d.Mean(); got != 5 {
+		t.Errorf("expected mean 5, got %v", got)
+	}
+
+	d.Add(0)
+	if got, n := d.Percentile(0), d.Len(); got != 0 || n != 11 {
+		t.Errorf("expected added durations to be sorted in, got %v of %d", got, n)
+	}
+}
@@ -1,5 +1,7 @@
 package dash0
 
+import "iter"
+
 // Iter provides iteration over paginated API results.
 // Use Next() to advance, Current() to get the item, and Err() to check for errors.
 //
@@ -14,6 +16,15 @@ package dash0
 //	    // handle error
 //	}
 //
+// Alternatively, use All() or Items() to range over the results:
+//
+//	for span, err := range client.GetSpansIter(ctx, request).All() {
+//	    if err != nil {
+//	        // handle error
+//	    }
+//	    // process span
+//	}
+//
 // Iterators are not thread-safe. Do not share an iterator across goroutines.
 type Iter[T any] struct {
 	cur     *T
@@ -23,6 +34,13 @@ type Iter[T any] struct {
 	hasMore bool
 	fetch   func(cursor *string) ([]*T, *string, error)
 	cursor  *string
+
+	// opaque hides the cursor of iterators whose cursors cannot be used to
+	// resume, such as windowed iterators.
+	opaque bool
+
+	// close releases background resources, if any.
+	close func()
 }
 
 // Next advances the iterator to the next item.
@@ -73,6 +91,74 @@ func (it *Iter[T]) Err() error {
 	return it.err
 }
 
+// Cursor returns the cursor of the next page to be fetched, or nil if there
+// are no more pages. If fetching a page failed, it returns the cursor of the
+// page that failed, so iteration can be resumed from there.
+// Windowed iterators, such as GetSpansWindowedIter, always return nil.
+//
+// The cursor refers to a page boundary: items of the current page that have
+// not been consumed yet are not covered by it. Use the page-level iterators,
+// such as GetSpansPages, to checkpoint without skipping items.
+func (it *Iter[T]) Cursor() *string {
+	if !it.hasMore || it.opaque {
+		return nil
+	}
+	return it.cursor
+}
+
+// Close stops the iteration, after which Next returns false. For windowed
+// iterators it also stops prefetching windows in the background, so call it
+// when abandoning such an iterator before it is exhausted. It is safe to call
+// multiple times.
+func (it *Iter[T]) Close() {
+	it.items = nil
+	it.cur = nil
+	it.hasMore = false
+	it.release()
+}
+
+// release stops background work without ending the iteration, so that the
+// cursor remains available.
+func (it *Iter[T]) release() {
+	if it.close != nil {
+		it.close()
+		it.close = nil
+	}
+}
+
+// All returns an iterator over all items for use with range-over-func.
+// Pages are fetched lazily, so no further pages are requested once the loop
+// breaks, and background work of windowed iterators is stopped. If fetching a
+// page fails, the error is yielded together with a nil item as the last element.
+func (it *Iter[T]) All() iter.Seq2[*T, error] {
+	return func(yield func(*T, error) bool) {
+		for it.Next() {
+			if !yield(it.Current(), nil) {
+				it.release()
+				return
+			}
+		}
+		if err := it.Err(); err != nil {
+			yield(nil, err)
+		}
+	}
+}
+
+// Items returns an iterator over all items for use with range-over-func.
+// Pages are fetched lazily, so no further pages are requested once the loop
+// breaks, and background work of windowed iterators is stopped. Errors are
+// not yielded; check Err() after the loop.
+func (it *Iter[T]) Items() iter.Seq[*T] {
+	return func(yield func(*T) bool) {
+		for it.Next() {
+			if !yield(it.Current()) {
+				it.release()
+				return
+			}
+		}
+	}
+}
+
 // newIter creates a new iterator with the given initial items and fetch function.
 func newIter[T any](items []*T, hasMore bool, cursor *string, fetch func(cursor *string) ([]*T, *string, error)) *Iter[T] {
 	return &Iter[T]{
@@ -91,3 +177,21 @@ func newIterWithError[T any](err error) *Iter[T] {
 		idx: -1,
 	}
 }
+
+// afterCursor returns the cursor of the page following a response, or nil if
+// there is none.
+func afterCursor(cursors *NextCursors) *string {
+	if cursors == nil || cursors.After == nil {
+		return nil
+	}
+	return (*string)(cursors.After)
+}
+
+// beforeCursor returns the cursor of the page preceding a response, or nil if
+// there is none.
+func beforeCursor(cursors *NextCursors) *string {
+	if cursors == nil || cursors.Before == nil {
+		return nil
+	}
+	return (*string)(cursors.Before)
+}
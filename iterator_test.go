@@ -215,6 +215,188 @@ func TestNewIterWithError(t *testing.T) {
 	})
 }
 
+func TestIter_RangeOverFunc(t *testing.T) {
+	t.Run("All yields items across pages", func(t *testing.T) {
+		cursor := "cursor1"
+		fetch := func(c *string) ([]*string, *string, error) {
+			return []*string{ptr("c")}, nil, nil
+		}
+
+		var result []string
+		for item, err := range newIter([]*string{ptr("a"), ptr("b")}, true, &cursor, fetch).All() {
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			result = append(result, *item)
+		}
+
+		if len(result) != 3 || result[2] != "c" {
+			t.Errorf("unexpected items: %v", result)
+		}
+	})
+
+	t.Run("All yields fetch error last", func(t *testing.T) {
+		cursor := "cursor1"
+		fetchErr := errors.New("fetch failed")
+		fetch := func(c *string) ([]*string, *string, error) {
+			return nil, nil, fetchErr
+		}
+
+		var items int
+		var gotErr error
+		for item, err := range newIter([]*string{ptr("a")}, true, &cursor, fetch).All() {
+			if err != nil {
+				if item != nil {
+					t.Error("expected nil item with error")
+				}
+				gotErr = err
+				continue
+			}
+			items++
+		}
+
+		if items != 1 {
+			t.Errorf("expected 1 item, got %d", items)
+		}
+		if gotErr != fetchErr {
+			t.Errorf("expected fetch error, got %v", gotErr)
+		}
+	})
+
+	t.Run("All yields initial error", func(t *testing.T) {
+		expectedErr := errors.New("initial error")
+
+		var gotErr error
+		for _, err := range newIterWithError[string](expectedErr).All() {
+			gotErr = err
+		}
+
+		if gotErr != expectedErr {
+			t.Errorf("expected %v, got %v", expectedErr, gotErr)
+		}
+	})
+
+	t.Run("stops fetching when loop breaks", func(t *testing.T) {
+		cursor := "cursor1"
+		fetchCalled := false
+		fetch := func(c *string) ([]*string, *string, error) {
+			fetchCalled = true
+			return []*string{ptr("c")}, nil, nil
+		}
+
+		for range newIter([]*string{ptr("a"), ptr("b")}, true, &cursor, fetch).All() {
+			break
+		}
+		for item := range newIter([]*string{ptr("a"), ptr("b")}, true, &cursor, fetch).Items() {
+			if *item == "b" {
+				break
+			}
+		}
+
+		if fetchCalled {
+			t.Error("fetch should not be called after break")
+		}
+	})
+
+	t.Run("Items leaves error for Err", func(t *testing.T) {
+		cursor := "cursor1"
+		fetchErr := errors.New("fetch failed")
+		it := newIter([]*string{ptr("a")}, true, &cursor, func(c *string) ([]*string, *string, error) {
+			return nil, nil, fetchErr
+		})
+
+		var result []string
+		for item := range it.Items() {
+			result = append(result, *item)
+		}
+
+		if len(result) != 1 {
+			t.Errorf("expected 1 item, got %d", len(result))
+		}
+		if it.Err() != fetchErr {
+			t.Errorf("expected fetch error, got %v", it.Err())
+		}
+	})
+}
+
+func TestIter_Cursor(t *testing.T) {
+	t.Run("returns the next page cursor", func(t *testing.T) {
+		cursor := "cursor1"
+		fetchErr := errors.New("fetch failed")
+		fetch := func(c *string) ([]*string, *string, error) {
+			if *c == "cursor1" {
+				return []*string{ptr("b")}, ptr("cursor2"), nil
+			}
+			return nil, nil, fetchErr
+		}
+		it := newIter([]*string{ptr("a")}, true, &cursor, fetch)
+
+		if !it.Next() || *it.Cursor() != "cursor1" {
+			t.Errorf("expected cursor1, got %v", it.Cursor())
+		}
+		if !it.Next() || *it.Cursor() != "cursor2" {
+			t.Errorf("expected cursor2, got %v", it.Cursor())
+		}
+		if it.Next() {
+			t.Fatal("expected Next to fail")
+		}
+		// The failed page can be retried from its cursor
+		if it.Cursor() == nil || *it.Cursor() != "cursor2" {
+			t.Errorf("expected cursor2 after error, got %v", it.Cursor())
+		}
+	})
+
+	t.Run("returns nil on the last page", func(t *testing.T) {
+		it := newIter([]*string{ptr("a")}, false, nil, nil)
+		it.Next()
+
+		if it.Cursor() != nil {
+			t.Errorf("expected nil cursor, got %v", *it.Cursor())
+		}
+	})
+}
+
+func TestIter_Close(t *testing.T) {
+	t.Run("ends the iteration", func(t *testing.T) {
+		cursor := "cursor1"
+		closed := 0
+		it := newIter([]*string{ptr("a"), ptr("b")}, true, &cursor, func(c *string) ([]*string, *string, error) {
+			t.Error("fetch should not be called after Close")
+			return nil, nil, nil
+		})
+		it.close = func() { closed++ }
+
+		it.Next()
+		it.Close()
+		it.Close()
+
+		if it.Next() {
+			t.Error("expected Next to return false after Close")
+		}
+		if closed != 1 {
+			t.Errorf("expected close to be called once, got %d", closed)
+		}
+	})
+
+	t.Run("keeps the cursor when a range loop breaks", func(t *testing.T) {
+		cursor := "cursor1"
+		closed := false
+		it := newIter([]*string{ptr("a")}, true, &cursor, nil)
+		it.close = func() { closed = true }
+
+		for range it.Items() {
+			break
+		}
+
+		if !closed {
+			t.Error("expected background work to be stopped")
+		}
+		if it.Cursor() == nil || *it.Cursor() != "cursor1" {
+			t.Errorf("expected cursor1, got %v", it.Cursor())
+		}
+	})
+}
+
 func ptr[T any](v T) *T {
 	return &v
 }
@@ -0,0 +1,253 @@
+package dash0
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+
+	"golang.org/x/sync/errgroup"
+)
+
+const (
+	// DefaultLinkDepth is the default number of link hops FollowLinks follows.
+	DefaultLinkDepth = 2
+
+	// DefaultLinkFanOut is the default number of linked traces FollowLinks
+	// fetches per trace.
+	DefaultLinkFanOut = 10
+)
+
+// LinkOption configures FollowLinks.
+type LinkOption func(*linkConfig)
+
+type linkConfig struct {
+	depth  int
+	fanOut int
+}
+
+// WithLinkDepth sets the maximum number of link hops from the starting trace.
+// Defaults to DefaultLinkDepth.
+func WithLinkDepth(depth int) LinkOption {
+	return func(c *linkConfig) {
+		c.depth = depth
+	}
+}
+
+// WithLinkFanOut sets the maximum number of linked traces fetched per trace.
+// Further linked traces are recorded as edges, but not fetched.
+// Defaults to DefaultLinkFanOut.
+func WithLinkFanOut(n int) LinkOption {
+	return func(c *linkConfig) {
+		c.fanOut = n
+	}
+}
+
+// TraceGraph is a set of traces connected by span links.
+// Use FollowLinks to create one.
+type TraceGraph struct {
+	// Root is the trace the graph was built from.
+	Root *Trace
+
+	// Traces holds all fetched traces by trace ID, including Root.
+	Traces map[string]*Trace
+
+	// Links holds the span links between traces, each link once.
+	Links []TraceLink
+
+	// Missing holds the IDs of linked traces that were not found in the
+	// time range.
+	Missing []string
+
+	// Depth holds the number of link hops from Root by trace ID.
+	Depth map[string]int
+}
+
+// TraceLink is a span link between two traces. Source is the span that
+// declares the link in its Links, Target is the span it points to. Both are
+// given as hexadecimal IDs, since either span may not have been fetched.
+type TraceLink struct {
+	SourceTraceID string
+	SourceSpanID  string
+	TargetTraceID string
+	TargetSpanID  string
+
+	// Attributes are the attributes of the link. They are empty for links
+	// that are only known from the Dash0ForwardLinks of the target.
+	Attributes []KeyValue
+}
+
+// Span returns the span with the given IDs in hexadecimal notation, or nil if
+// its trace was not fetched or does not contain it.
+func (g *TraceGraph) Span(traceID, spanID string) *SpanNode {
+	if t := g.Traces[traceID]; t != nil {
+		return t.Span(spanID)
+	}
+	return nil
+}
+
+// LinksOf returns the links from or to the given trace.
+func (g *TraceGraph) LinksOf(traceID string) []TraceLink {
+	var links []TraceLink
+	for _, link := range g.Links {
+		if link.SourceTraceID == traceID || link.TargetTraceID == traceID {
+			links = append(links, link)
+		}
+	}
+	return links
+}
+
+// FollowLinks builds a graph of the traces linked to the given trace. It
+// follows the Links of every span and, when the OTLP schema extensions are
+// present, their Dash0ForwardLinks, which point from a span to the spans in
+// other traces that link to it. This connects e.g. producer and consumer
+// traces of messaging systems.
+//
+// Linked traces are fetched level by level with GetTrace, up to the depth
+// configured with WithLinkDepth and the number of traces per trace configured
+// with WithLinkFanOut. The traces of a level are fetched in parallel, bounded
+// by the client's limit on concurrent requests. Traces that are not found are
+// recorded in Missing, other errors abort the traversal. A nil trace is an
+// error.
+//
+// Example:
+//
+//	graph, err := dash0.FollowLinks(ctx, client, trace, dash0.TimeRangeLast(24*time.Hour), nil,
+//	    dash0.WithLinkDepth(3))
+//	if err != nil {
+//	    // handle error
+//	}
+//	for _, link := range graph.Links {
+//	    fmt.Printf("%s/%s -> %s/%s\n", link.SourceTraceID, link.SourceSpanID, link.TargetTraceID, link.TargetSpanID)
+//	}
+func FollowLinks(ctx context.Context, client Client, trace *Trace, timeRange TimeReferenceRange, dataset *string, opts ...LinkOption) (*TraceGraph, error) {
+	if trace == nil {
+		return nil, errors.New("dash0: trace is nil")
+	}
+	cfg := &linkConfig{depth: DefaultLinkDepth, fanOut: DefaultLinkFanOut}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	g := &TraceGraph{
+		Root:   trace,
+		Traces: map[string]*Trace{trace.TraceID: trace},
+		Depth:  map[string]int{trace.TraceID: 0},
+	}
+	links := make(map[traceLinkKey]int)
+	visited := map[string]bool{trace.TraceID: true}
+	level := []*Trace{trace}
+	for depth := 1; len(level) > 0; depth++ {
+		var next []string
+		for _, t := range level {
+			linked := g.addLinks(t, links)
+			if depth > cfg.depth {
+				continue
+			}
+			fetched := 0
+			for _, id := range linked {
+				if fetched == cfg.fanOut {
+					break
+				}
+				if visited[id] {
+					continue
+				}
+				visited[id] = true
+				next = append(next, id)
+				fetched++
+			}
+		}
+
+		traces, err := fetchTraces(ctx, client, next, timeRange, dataset)
+		if err != nil {
+			return nil, err
+		}
+		level = nil
+		for i, id := range next {
+			if traces[i] == nil {
+				g.Missing = append(g.Missing, id)
+				continue
+			}
+			g.Traces[id] = traces[i]
+			g.Depth[id] = depth
+			level = append(level, traces[i])
+		}
+	}
+	return g, nil
+}
+
+// traceLinkKey identifies a TraceLink regardless of its attributes.
+type traceLinkKey struct {
+	sourceTraceID, sourceSpanID, targetTraceID, targetSpanID string
+}
+
+// addLinks records the links of all spans of t and returns the IDs of the
+// linked traces in span order. links maps the links recorded so far to their
+// index in g.Links.
+func (g *TraceGraph) addLinks(t *Trace, links map[traceLinkKey]int) []string {
+	var linked []string
+	add := func(link TraceLink, other string) {
+		if other == t.TraceID {
+			return
+		}
+		key := traceLinkKey{link.SourceTraceID, link.SourceSpanID, link.TargetTraceID, link.TargetSpanID}
+		if i, ok := links[key]; ok {
+			// A link known from a forward link lacks the attributes
+			if len(g.Links[i].Attributes) == 0 {
+				g.Links[i].Attributes = link.Attributes
+			}
+			return
+		}
+		links[key] = len(g.Links)
+		g.Links = append(g.Links, link)
+		linked = append(linked, other)
+	}
+
+	for n := range t.DepthFirst() {
+		for _, l := range n.Span.Links {
+			target := hex.EncodeToString(l.TraceId)
+			add(TraceLink{
+				SourceTraceID: t.TraceID,
+				SourceSpanID:  n.SpanID(),
+				TargetTraceID: target,
+				TargetSpanID:  hex.EncodeToString(l.SpanId),
+				Attributes:    l.Attributes,
+			}, target)
+		}
+		if n.Span.Dash0ForwardLinks != nil {
+			for _, l := range *n.Span.Dash0ForwardLinks {
+				source := hex.EncodeToString(l.TraceId)
+				add(TraceLink{
+					SourceTraceID: source,
+					SourceSpanID:  hex.EncodeToString(l.SpanId),
+					TargetTraceID: t.TraceID,
+					TargetSpanID:  n.SpanID(),
+				}, source)
+			}
+		}
+	}
+	return linked
+}
+
+// fetchTraces fetches the traces with the given IDs in parallel. Traces that
+// are not found are returned as nil.
+func fetchTraces(ctx context.Context, client Client, ids []string, timeRange TimeReferenceRange, dataset *string) ([]*Trace, error) {
+	traces := make([]*Trace, len(ids))
+	g, ctx := errgroup.WithContext(ctx)
+	for i, id := range ids {
+		g.Go(func() error {
+			trace, err := client.GetTrace(ctx, id, timeRange, dataset)
+			if IsNotFound(err) {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			traces[i] = trace
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+	return traces, nil
+}
@@ -0,0 +1,169 @@
+package dash0
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// traceClient serves traces from memory via GetTrace.
+type traceClient struct {
+	Client
+
+	traces map[string]*Trace
+	err    error
+
+	mu      sync.Mutex
+	fetched []string
+}
+
+func (c *traceClient) GetTrace(ctx context.Context, traceID string, timeRange TimeReferenceRange, dataset *string) (*Trace, error) {
+	c.mu.Lock()
+	c.fetched = append(c.fetched, traceID)
+	c.mu.Unlock()
+	if c.err != nil {
+		return nil, c.err
+	}
+	if trace, ok := c.traces[traceID]; ok {
+		return trace, nil
+	}
+	return nil, &APIError{StatusCode: http.StatusNotFound}
+}
+
+// linkedTrace returns a trace with a single span "s" that links to span "s"
+// of each of the given traces. Trace IDs are padded to 16 bytes.
+func linkedTrace(id string, links ...string) *Trace {
+	span := Span{Name: id, TraceId: testLinkTraceID(id), SpanId: testSpanID("s")}
+	for _, link := range links {
+		span.Links = append(span.Links, SpanLink{TraceId: testLinkTraceID(link), SpanId: testSpanID("s")})
+	}
+	return NewTrace([]ResourceSpans{testResourceSpans("svc", span)})
+}
+
+func testLinkTraceID(id string) []byte {
+	b := make([]byte, 16)
+	copy(b, id)
+	return b
+}
+
+func traceIDs(ids ...string) []string {
+	var result []string
+	for _, id := range ids {
+		result = append(result, hex.EncodeToString(testLinkTraceID(id)))
+	}
+	return result
+}
+
+func TestFollowLinks(t *testing.T) {
+	traces := map[string]*Trace{}
+	for _, trace := range []*Trace{
+		linkedTrace("a", "b", "c"),
+		linkedTrace("b", "d"),
+		linkedTrace("c", "a"),
+		linkedTrace("d", "e"),
+		linkedTrace("e"),
+	} {
+		traces[trace.TraceID] = trace
+	}
+	root := traces[traceIDs("a")[0]]
+
+	t.Run("follows links up to the depth", func(t *testing.T) {
+		client := &traceClient{traces: traces}
+
+		graph, err := FollowLinks(context.Background(), client, root, TimeRangeLast(0), nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(graph.Traces) != 4 {
+			t.Errorf("expected 4 traces, got %d", len(graph.Traces))
+		}
+		if graph.Depth[traceIDs("d")[0]] != 2 {
+			t.Errorf("expected d at depth 2, got %v", graph.Depth)
+		}
+		if _, ok := graph.Traces[traceIDs("e")[0]]; ok {
+			t.Error("expected e not to be fetched")
+		}
+		// a->b, a->c, b->d, c->a and the unfollowed d->e
+		if len(graph.Links) != 5 {
+			t.Errorf("expected 5 links, got %d", len(graph.Links))
+		}
+		if len(graph.LinksOf(traceIDs("c")[0])) != 2 {
+			t.Errorf("expected 2 links of c, got %v", graph.LinksOf(traceIDs("c")[0]))
+		}
+		if graph.Span(traceIDs("b")[0], hex.EncodeToString(testSpanID("s"))) == nil {
+			t.Error("expected span of b")
+		}
+		if slices.Contains(client.fetched, root.TraceID) {
+			t.Error("expected root not to be fetched again")
+		}
+	})
+
+	t.Run("limits the fan-out", func(t *testing.T) {
+		client := &traceClient{traces: traces}
+
+		graph, err := FollowLinks(context.Background(), client, root, TimeRangeLast(0), nil, WithLinkDepth(1), WithLinkFanOut(1))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !slices.Equal(client.fetched, traceIDs("b")) {
+			t.Errorf("expected only b to be fetched, got %v", client.fetched)
+		}
+		if len(graph.Links) != 3 {
+			t.Errorf("expected 3 links, got %d", len(graph.Links))
+		}
+	})
+
+	t.Run("follows forward links", func(t *testing.T) {
+		consumer := linkedTrace("consumer", "producer")
+		producer := linkedTrace("producer")
+		span := producer.Roots[0].Span
+		span.Dash0ForwardLinks = &[]SpanLink{{TraceId: testLinkTraceID("consumer"), SpanId: testSpanID("s")}}
+		client := &traceClient{traces: map[string]*Trace{consumer.TraceID: consumer}}
+
+		graph, err := FollowLinks(context.Background(), client, producer, TimeRangeLast(0), nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(graph.Traces) != 2 || len(graph.Links) != 1 {
+			t.Fatalf("expected 2 traces and 1 link, got %d and %d", len(graph.Traces), len(graph.Links))
+		}
+		link := graph.Links[0]
+		if link.SourceTraceID != consumer.TraceID || link.TargetTraceID != producer.TraceID {
+			t.Errorf("expected link from consumer to producer, got %+v", link)
+		}
+	})
+
+	t.Run("records missing traces", func(t *testing.T) {
+		client := &traceClient{traces: map[string]*Trace{}}
+
+		graph, err := FollowLinks(context.Background(), client, root, TimeRangeLast(0), nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		slices.Sort(graph.Missing)
+		if strings.Join(graph.Missing, ",") != strings.Join(traceIDs("b", "c"), ",") {
+			t.Errorf("unexpected missing traces: %v", graph.Missing)
+		}
+	})
+
+	t.Run("stops at the first error", func(t *testing.T) {
+		client := &traceClient{err: errors.New("boom")}
+
+		if _, err := FollowLinks(context.Background(), client, root, TimeRangeLast(0), nil); err == nil {
+			t.Error("expected an error")
+		}
+	})
+
+	t.Run("rejects a nil trace", func(t *testing.T) {
+		client := &traceClient{traces: map[string]*Trace{}}
+
+		if _, err := FollowLinks(context.Background(), client, nil, TimeRangeLast(0), nil); err == nil {
+			t.Error("expected an error")
+		}
+	})
+}
@@ -0,0 +1,343 @@
+// Package logpattern groups similar log records into patterns, such as
+// "connection to <IP> timed out after <NUM>ms", to triage large numbers of
+// log records without exporting them elsewhere.
+//
+// The Miner follows the Drain algorithm: log bodies are masked and split into
+// tokens, routed through a fixed-depth tree by their token count and leading
+// tokens, and merged into the most similar cluster of the leaf if the share
+// of equal tokens reaches the similarity threshold. Tokens that differ between
+// the records of a cluster become the wildcard <*>.
+//
+// Example:
+//
+//	clusters, err := logpattern.Mine(client.GetLogRecordsIter(ctx, request).All())
+//	if err != nil {
+//	    // handle error
+//	}
+//	for _, c := range clusters {
+//	    fmt.Printf("%6d %s\n", c.Count, c.Template)
+//	}
+package logpattern
+
+import (
+	"cmp"
+	"iter"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dash0hq/dash0-api-client-go"
+)
+
+const (
+	// DefaultDepth is the default depth of the parse tree, including the
+	// root and the token count level.
+	DefaultDepth = 4
+
+	// DefaultSimilarity is the default share of equal tokens at which a
+	// log record joins a cluster.
+	DefaultSimilarity = 0.4
+
+	// DefaultMaxChildren is the default maximum number of children of a
+	// tree node. Further tokens are routed to the wildcard child.
+	DefaultMaxChildren = 100
+
+	// DefaultMaxExamples is the default number of example records kept
+	// per cluster.
+	DefaultMaxExamples = 3
+)
+
+// Wildcard is the template token of variable parts of a pattern.
+const Wildcard = "<*>"
+
+// Mask replaces the matches of Pattern in log bodies with <Name> before they
+// are split into tokens.
+type Mask struct {
+	Name    string
+	Pattern *regexp.Regexp
+}
+
+// DefaultMasks are the masks applied by default, in order: UUIDs, IPv4
+// addresses, hexadecimal numbers and IDs, and decimal numbers. Numbers
+// followed by a unit, like 250ms, keep their unit.
+var DefaultMasks = []Mask{
+	{"UUID", regexp.MustCompile(`\b[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}\b`)},
+	{"IP", regexp.MustCompile(`\b\d{1,3}(?:\.\d{1,3}){3}\b`)},
+	{"HEX", regexp.MustCompile(`\b(?:0x[0-9a-fA-F]+|[0-9a-fA-F]{16,})\b`)},
+	{"NUM", regexp.MustCompile(`\b\d+(?:\.\d+)?`)},
+}
+
+// Option configures a Miner.
+type Option func(*config)
+
+type config struct {
+	depth       int
+	similarity  float64
+	maxChildren int
+	maxExamples int
+	masks       []Mask
+}
+
+// WithDepth sets the depth of the parse tree. Log records are routed by
+// depth-2 leading tokens. Defaults to DefaultDepth.
+func WithDepth(depth int) Option {
+	return func(c *config) {
+		c.depth = depth
+	}
+}
+
+// WithSimilarity sets the share of equal tokens, between 0 and 1, at which a
+// log record joins a cluster. Higher values yield more, more specific
+// clusters. Defaults to DefaultSimilarity.
+func WithSimilarity(similarity float64) Option {
+	return func(c *config) {
+		c.similarity = similarity
+	}
+}
+
+// WithMaxChildren sets the maximum number of children of a tree node.
+// Defaults to DefaultMaxChildren.
+func WithMaxChildren(n int) Option {
+	return func(c *config) {
+		c.maxChildren = n
+	}
+}
+
+// WithMaxExamples sets the number of example records kept per cluster.
+// Defaults to DefaultMaxExamples.
+func WithMaxExamples(n int) Option {
+	return func(c *config) {
+		c.maxExamples = n
+	}
+}
+
+// WithMasks replaces the DefaultMasks.
+func WithMasks(masks ...Mask) Option {
+	return func(c *config) {
+		c.masks = masks
+	}
+}
+
+// Cluster is a group of log records that share a pattern.
+type Cluster struct {
+	// ID identifies the cluster within its Miner, in order of creation.
+	ID int
+
+	// Template is the pattern of the cluster, i.e. the tokens joined by
+	// spaces.
+	Template string
+	Tokens   []string
+
+	// Count is the number of log records in the cluster.
+	Count int
+
+	// Examples holds the first log records of the cluster.
+	Examples []*dash0.LogRecordEntry
+
+	// BySeverity counts the log records by severity name, or severity
+	// text if the severity number is not set.
+	BySeverity map[string]int
+
+	// ByService counts the log records by the service.name resource
+	// attribute.
+	ByService map[string]int
+
+	// First and Last are the earliest and the latest time of the log
+	// records in the cluster.
+	First time.Time
+	Last  time.Time
+}
+
+// Miner clusters log records incrementally. A Miner is not safe for
+// concurrent use.
+type Miner struct {
+	config   *config
+	root     *node
+	clusters []*Cluster
+}
+
+// node is a node of the parse tree. Inner nodes have children, leaves have
+// clusters.
+type node struct {
+	children map[string]*node
+	clusters []*Cluster
+}
+
+// NewMiner returns a Miner with the given options.
+func NewMiner(opts ...Option) *Miner {
+	cfg := &config{
+		depth:       DefaultDepth,
+		similarity:  DefaultSimilarity,
+		maxChildren: DefaultMaxChildren,
+		maxExamples: DefaultMaxExamples,
+		masks:       DefaultMasks,
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	cfg.depth = max(cfg.depth, 3)
+	cfg.maxChildren = max(cfg.maxChildren, 1)
+	return &Miner{config: cfg, root: &node{children: make(map[string]*node)}}
+}
+
+// Mine clusters all log records of resourceLogs, e.g. the items of
+// GetLogRecordsIter, and returns the clusters ordered by count. It stops at
+// the first error of resourceLogs.
+func Mine(resourceLogs iter.Seq2[*dash0.ResourceLogs, error], opts ...Option) ([]*Cluster, error) {
+	m := NewMiner(opts...)
+	for rl, err := range resourceLogs {
+		if err != nil {
+			return nil, err
+		}
+		m.AddResourceLogs(rl)
+	}
+	return m.Clusters(), nil
+}
+
+// AddResourceLogs adds all log records of the resource logs.
+func (m *Miner) AddResourceLogs(rl *dash0.ResourceLogs) {
+	for i := range rl.ScopeLogs {
+		sl := &rl.ScopeLogs[i]
+		for j := range sl.LogRecords {
+			m.Add(&dash0.LogRecordEntry{Resource: &rl.Resource, Scope: sl.Scope, LogRecord: &sl.LogRecords[j]})
+		}
+	}
+}
+
+// Add adds a log record and returns the cluster it was assigned to.
+func (m *Miner) Add(entry *dash0.LogRecordEntry) *Cluster {
+	tokens := m.tokenize(entry.LogRecord)
+	leaf := m.leaf(tokens)
+
+	cluster := m.match(leaf, tokens)
+	if cluster == nil {
+		cluster = &Cluster{
+			ID:         len(m.clusters) + 1,
+			Tokens:     tokens,
+			BySeverity: make(map[string]int),
+			ByService:  make(map[string]int),
+		}
+		leaf.clusters = append(leaf.clusters, cluster)
+		m.clusters = append(m.clusters, cluster)
+	} else {
+		for i, token := range tokens {
+			if cluster.Tokens[i] != token {
+				cluster.Tokens[i] = Wildcard
+			}
+		}
+	}
+	cluster.Template = strings.Join(cluster.Tokens, " ")
+
+	cluster.Count++
+	if len(cluster.Examples) < m.config.maxExamples {
+		cluster.Examples = append(cluster.Examples, entry)
+	}
+	cluster.BySeverity[dash0.LogRecordSeverity(entry.LogRecord)]++
+	cluster.ByService[entry.Resource.ServiceName()]++
+	if t := dash0.LogRecordTime(entry.LogRecord); !t.IsZero() {
+		if cluster.First.IsZero() || t.Before(cluster.First) {
+			cluster.First = t
+		}
+		if t.After(cluster.Last) {
+			cluster.Last = t
+		}
+	}
+	return cluster
+}
+
+// Clusters returns all clusters ordered by count, largest first, and then by
+// ID.
+func (m *Miner) Clusters() []*Cluster {
+	clusters := slices.Clone(m.clusters)
+	slices.SortStableFunc(clusters, func(a, b *Cluster) int {
+		return cmp.Compare(b.Count, a.Count)
+	})
+	return clusters
+}
+
+// tokenize masks the body of a log record and splits it into tokens.
+func (m *Miner) tokenize(record *dash0.LogRecord) []string {
+	var body string
+	if record.Body != nil {
+		body = record.Body.String()
+	}
+	for _, mask := range m.config.masks {
+		body = mask.Pattern.ReplaceAllLiteralString(body, "<"+mask.Name+">")
+	}
+	return strings.Fields(body)
+}
+
+// leaf returns the leaf node for the tokens, creating nodes as needed. The
+// first level is keyed by the token count, the following levels by the
+// leading tokens.
+func (m *Miner) leaf(tokens []string) *node {
+	n := m.root.child(strconv.Itoa(len(tokens)), m.config.maxChildren)
+	for i := 0; i < m.config.depth-2 && i < len(tokens); i++ {
+		key := tokens[i]
+		if hasDigit(key) {
+			key = Wildcard
+		}
+		n = n.child(key, m.config.maxChildren)
+	}
+	return n
+}
+
+// child returns the child for key, or the wildcard child if the node is full.
+func (n *node) child(key string, maxChildren int) *node {
+	if child, ok := n.children[key]; ok {
+		return child
+	}
+	if len(n.children) >= maxChildren {
+		key = Wildcard
+		if child, ok := n.children[key]; ok {
+			return child
+		}
+	}
+	child := &node{children: make(map[string]*node)}
+	n.children[key] = child
+	return child
+}
+
+// match returns the most similar cluster of the leaf, or nil if none reaches
+// the similarity threshold. Ties are broken by the number of wildcards, so
+// that more general clusters are preferred.
+func (m *Miner) match(leaf *node, tokens []string) *Cluster {
+	var best *Cluster
+	bestSimilarity, bestWildcards := -1.0, -1
+	for _, c := range leaf.clusters {
+		similarity, wildcards := similarity(c.Tokens, tokens)
+		if similarity > bestSimilarity || similarity == bestSimilarity && wildcards > bestWildcards {
+			best, bestSimilarity, bestWildcards = c, similarity, wildcards
+		}
+	}
+	if best == nil || bestSimilarity < m.config.similarity {
+		return nil
+	}
+	return best
+}
+
+// similarity returns the share of tokens that equal the template, and the
+// number of wildcards in the template. Both have the same length, since the
+// tree is keyed by token count.
+func similarity(template, tokens []string) (float64, int) {
+	if len(template) == 0 {
+		return 1, 0
+	}
+	equal, wildcards := 0, 0
+	for i, token := range template {
+		if token == Wildcard {
+			wildcards++
+			continue
+		}
+		if token == tokens[i] {
+			equal++
+		}
+	}
+	return float64(equal) / float64(len(template)), wildcards
+}
+
+func hasDigit(s string) bool {
+	return strings.ContainsAny(s, "0123456789")
+}
@@ -0,0 +1,131 @@
+package logpattern
+
+import (
+	"errors"
+	"fmt"
+	"iter"
+	"regexp"
+	"testing"
+
+	"github.com/dash0hq/dash0-api-client-go"
+)
+
+func entry(service string, severity dash0.SeverityNumber, timeUnixNano, body string) *dash0.LogRecordEntry {
+	return &dash0.LogRecordEntry{
+		Resource: &dash0.Resource{Attributes: []dash0.KeyValue{{Key: "service.name", Value: dash0.AnyValue{StringValue: dash0.String(service)}}}},
+		LogRecord: &dash0.LogRecord{
+			TimeUnixNano:   timeUnixNano,
+			SeverityNumber: dash0.Ptr(severity),
+			Body:           &dash0.AnyValue{StringValue: dash0.String(body)},
+		},
+	}
+}
+
+func templates(clusters []*Cluster) []string {
+	result := make([]string, len(clusters))
+	for i, c := range clusters {
+		result[i] = fmt.Sprintf("%d %s", c.Count, c.Template)
+	}
+	return result
+}
+
+func TestMiner(t *testing.T) {
+	m := NewMiner()
+	m.Add(entry("checkout", 17, "3", "connection to 10.0.0.1:5432 timed out after 250ms"))
+	m.Add(entry("checkout", 17, "1", "connection to 10.0.0.2:5432 timed out after 1200ms"))
+	m.Add(entry("payment", 13, "2", "connection to 192.168.1.7:6379 timed out after 30ms"))
+	m.Add(entry("checkout", 9, "4", "login succeeded for alice"))
+	m.Add(entry("checkout", 9, "5", "login succeeded for bob"))
+	m.Add(entry("checkout", 9, "6", "request 7c9e6679-7425-40de-944b-e07fc1f90ae7 finished"))
+
+	clusters := m.Clusters()
+	want := []string{
+		"3 connection to <IP>:<NUM> timed out after <NUM>ms",
+		"2 login succeeded for <*>",
+		"1 request <UUID> finished",
+	}
+	if got := templates(clusters); fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+
+	c := clusters[0]
+	if c.BySeverity["ERROR"] != 2 || c.BySeverity["WARN"] != 1 {
+		t.Errorf("unexpected severity breakdown %v", c.BySeverity)
+	}
+	if c.ByService["checkout"] != 2 || c.ByService["payment"] != 1 {
+		t.Errorf("unexpected service breakdown %v", c.ByService)
+	}
+	if c.First.UnixNano() != 1 || c.Last.UnixNano() != 3 {
+		t.Errorf("unexpected time range %v - %v", c.First, c.Last)
+	}
+	if len(c.Examples) != 3 || c.Examples[0].LogRecord.TimeUnixNano != "3" {
+		t.Errorf("unexpected examples %v", c.Examples)
+	}
+
+	t.Run("keeps different token counts apart", func(t *testing.T) {
+		m := NewMiner()
+		a := m.Add(entry("a", 9, "1", "cache miss"))
+		b := m.Add(entry("a", 9, "1", "cache miss for key"))
+		if a == b {
+			t.Error("expected separate clusters")
+		}
+	})
+
+	t.Run("respects the similarity threshold", func(t *testing.T) {
+		m := NewMiner(WithSimilarity(0.9))
+		m.Add(entry("a", 9, "1", "login succeeded for alice"))
+		m.Add(entry("a", 9, "1", "login succeeded for bob"))
+		if n := len(m.Clusters()); n != 2 {
+			t.Errorf("expected 2 clusters, got %d", n)
+		}
+	})
+
+	t.Run("applies custom masks", func(t *testing.T) {
+		m := NewMiner(WithMasks(Mask{"EMAIL", regexp.MustCompile(`\S+@\S+`)}), WithMaxExamples(1))
+		c := m.Add(entry("a", 9, "1", "sent mail to alice@example.com"))
+		m.Add(entry("a", 9, "1", "sent mail to bob@example.com"))
+		if c.Template != "sent mail to <EMAIL>" || len(c.Examples) != 1 {
+			t.Errorf("unexpected cluster %+v", c)
+		}
+	})
+
+	t.Run("limits children per node", func(t *testing.T) {
+		m := NewMiner(WithMaxChildren(2), WithDepth(3))
+		for _, word := range []string{"alpha", "beta", "gamma", "delta"} {
+			m.Add(entry("a", 9, "1", word+" started"))
+		}
+		if got := templates(m.Clusters()); fmt.Sprint(got) != fmt.Sprint([]string{"2 <*> started", "1 alpha started", "1 beta started"}) {
+			t.Errorf("expected overflowing tokens to share the wildcard node, got %q", got)
+		}
+	})
+}
+
+func TestMine(t *testing.T) {
+	resourceLogs := []dash0.ResourceLogs{{
+		Resource: dash0.Resource{Attributes: []dash0.KeyValue{{Key: "service.name", Value: dash0.AnyValue{StringValue: dash0.String("checkout")}}}},
+		ScopeLogs: []dash0.ScopeLogs{{LogRecords: []dash0.LogRecord{
+			{Body: &dash0.AnyValue{StringValue: dash0.String("retrying in 5s")}, SeverityText: dash0.String("warning")},
+			{Body: &dash0.AnyValue{StringValue: dash0.String("retrying in 10s")}},
+		}}},
+	}}
+	seq := func(err error) iter.Seq2[*dash0.ResourceLogs, error] {
+		return func(yield func(*dash0.ResourceLogs, error) bool) {
+			if yield(&resourceLogs[0], nil) && err != nil {
+				yield(nil, err)
+			}
+		}
+	}
+
+	clusters, err := Mine(seq(nil))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(clusters) != 1 || clusters[0].Template != "retrying in <NUM>s" || clusters[0].BySeverity["warning"] != 1 || clusters[0].BySeverity["UNSPECIFIED"] != 1 {
+		t.Errorf("unexpected clusters %+v", clusters)
+	}
+
+	errFetch := errors.New("fetch failed")
+	if _, err := Mine(seq(errFetch)); !errors.Is(err, errFetch) {
+		t.Errorf("expected iteration error, got %v", err)
+	}
+}
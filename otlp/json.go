@@ -0,0 +1,144 @@
+package otlp
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+
+	"github.com/dash0hq/dash0-api-client-go"
+	collogspb "go.opentelemetry.io/proto/otlp/collector/logs/v1"
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// idFields are the JSON fields that OTLP/JSON encodes in hexadecimal instead
+// of the base64 encoding protobuf JSON uses for bytes.
+var idFields = map[string]bool{
+	"traceId":      true,
+	"spanId":       true,
+	"parentSpanId": true,
+}
+
+// MarshalTracesJSON encodes resource spans as an OTLP/JSON
+// ExportTraceServiceRequest, e.g. for the /v1/traces endpoint of an
+// OpenTelemetry Collector.
+func MarshalTracesJSON(resourceSpans []dash0.ResourceSpans) ([]byte, error) {
+	req, err := TracesToProto(resourceSpans)
+	if err != nil {
+		return nil, err
+	}
+	return marshalJSON(req)
+}
+
+// UnmarshalTracesJSON decodes an OTLP/JSON ExportTraceServiceRequest.
+// Unknown fields are ignored.
+func UnmarshalTracesJSON(data []byte) ([]dash0.ResourceSpans, error) {
+	var req coltracepb.ExportTraceServiceRequest
+	if err := unmarshalJSON(data, &req); err != nil {
+		return nil, err
+	}
+	return TracesFromProto(&req), nil
+}
+
+// MarshalLogsJSON encodes resource logs as an OTLP/JSON
+// ExportLogsServiceRequest, e.g. for the /v1/logs endpoint of an
+// OpenTelemetry Collector.
+func MarshalLogsJSON(resourceLogs []dash0.ResourceLogs) ([]byte, error) {
+	req, err := LogsToProto(resourceLogs)
+	if err != nil {
+		return nil, err
+	}
+	return marshalJSON(req)
+}
+
+// UnmarshalLogsJSON decodes an OTLP/JSON ExportLogsServiceRequest.
+// Unknown fields are ignored.
+func UnmarshalLogsJSON(data []byte) ([]dash0.ResourceLogs, error) {
+	var req collogspb.ExportLogsServiceRequest
+	if err := unmarshalJSON(data, &req); err != nil {
+		return nil, err
+	}
+	return LogsFromProto(&req), nil
+}
+
+// marshalJSON encodes a message as OTLP/JSON. Unlike protojson, whose output
+// is deliberately unstable, the result is deterministic.
+func marshalJSON(m proto.Message) ([]byte, error) {
+	b, err := protojson.MarshalOptions{UseEnumNumbers: true}.Marshal(m)
+	if err != nil {
+		return nil, err
+	}
+	return transcodeIDs(b, func(s string) (string, error) {
+		id, err := base64.StdEncoding.DecodeString(s)
+		if err != nil {
+			return "", err
+		}
+		return hex.EncodeToString(id), nil
+	})
+}
+
+func unmarshalJSON(data []byte, m proto.Message) error {
+	b, err := transcodeIDs(data, func(s string) (string, error) {
+		id, err := hex.DecodeString(s)
+		if err != nil {
+			return "", err
+		}
+		return base64.StdEncoding.EncodeToString(id), nil
+	})
+	if err != nil {
+		return err
+	}
+	return protojson.UnmarshalOptions{DiscardUnknown: true}.Unmarshal(b, m)
+}
+
+// transcodeIDs re-encodes the string values of all idFields in a JSON
+// document with convert.
+func transcodeIDs(data []byte, convert func(string) (string, error)) ([]byte, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var doc any
+	if err := decoder.Decode(&doc); err != nil {
+		return nil, err
+	}
+
+	var walk func(v any) error
+	walk = func(v any) error {
+		switch v := v.(type) {
+		case map[string]any:
+			for key, value := range v {
+				if s, ok := value.(string); ok && idFields[key] {
+					id, err := convert(s)
+					if err != nil {
+						return fmt.Errorf("invalid %s %q: %w", key, s, err)
+					}
+					v[key] = id
+					continue
+				}
+				if err := walk(value); err != nil {
+					return err
+				}
+			}
+		case []any:
+			for _, item := range v {
+				if err := walk(item); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}
+	if err := walk(doc); err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(doc); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
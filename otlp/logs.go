@@ -0,0 +1,144 @@
+package otlp
+
+import (
+	"fmt"
+
+	"github.com/dash0hq/dash0-api-client-go"
+	collogspb "go.opentelemetry.io/proto/otlp/collector/logs/v1"
+	logspb "go.opentelemetry.io/proto/otlp/logs/v1"
+)
+
+// LogsToProto converts resource logs to an OTLP export request.
+// It returns an error if a timestamp or an integer value is not a valid
+// decimal number.
+func LogsToProto(resourceLogs []dash0.ResourceLogs) (*collogspb.ExportLogsServiceRequest, error) {
+	req := &collogspb.ExportLogsServiceRequest{ResourceLogs: make([]*logspb.ResourceLogs, len(resourceLogs))}
+	for i := range resourceLogs {
+		rl, err := resourceLogsToProto(&resourceLogs[i])
+		if err != nil {
+			return nil, fmt.Errorf("resourceLogs[%d]: %w", i, err)
+		}
+		req.ResourceLogs[i] = rl
+	}
+	return req, nil
+}
+
+// LogsFromProto converts an OTLP export request to resource logs.
+func LogsFromProto(req *collogspb.ExportLogsServiceRequest) []dash0.ResourceLogs {
+	result := make([]dash0.ResourceLogs, len(req.GetResourceLogs()))
+	for i, rl := range req.GetResourceLogs() {
+		result[i] = dash0.ResourceLogs{
+			Resource:  resourceFromProto(rl.GetResource()),
+			SchemaUrl: optionalString(rl.GetSchemaUrl()),
+			ScopeLogs: make([]dash0.ScopeLogs, len(rl.GetScopeLogs())),
+		}
+		for j, sl := range rl.GetScopeLogs() {
+			scopeLogs := dash0.ScopeLogs{
+				Scope:      scopeFromProto(sl.GetScope()),
+				SchemaUrl:  optionalString(sl.GetSchemaUrl()),
+				LogRecords: make([]dash0.LogRecord, len(sl.GetLogRecords())),
+			}
+			for k, record := range sl.GetLogRecords() {
+				scopeLogs.LogRecords[k] = logRecordFromProto(record)
+			}
+			result[i].ScopeLogs[j] = scopeLogs
+		}
+	}
+	return result
+}
+
+func resourceLogsToProto(rl *dash0.ResourceLogs) (*logspb.ResourceLogs, error) {
+	resource, err := resourceToProto(&rl.Resource)
+	if err != nil {
+		return nil, fmt.Errorf("resource: %w", err)
+	}
+	result := &logspb.ResourceLogs{
+		Resource:  resource,
+		SchemaUrl: dash0.StringValue(rl.SchemaUrl),
+		ScopeLogs: make([]*logspb.ScopeLogs, len(rl.ScopeLogs)),
+	}
+	for j := range rl.ScopeLogs {
+		sl := &rl.ScopeLogs[j]
+		scope, err := scopeToProto(sl.Scope)
+		if err != nil {
+			return nil, fmt.Errorf("scopeLogs[%d].scope: %w", j, err)
+		}
+		scopeLogs := &logspb.ScopeLogs{
+			Scope:      scope,
+			SchemaUrl:  dash0.StringValue(sl.SchemaUrl),
+			LogRecords: make([]*logspb.LogRecord, len(sl.LogRecords)),
+		}
+		for k := range sl.LogRecords {
+			record, err := logRecordToProto(&sl.LogRecords[k])
+			if err != nil {
+				return nil, fmt.Errorf("scopeLogs[%d].logRecords[%d]: %w", j, k, err)
+			}
+			scopeLogs.LogRecords[k] = record
+		}
+		result.ScopeLogs[j] = scopeLogs
+	}
+	return result, nil
+}
+
+func logRecordToProto(r *dash0.LogRecord) (*logspb.LogRecord, error) {
+	t, err := parseUnixNano("timeUnixNano", r.TimeUnixNano)
+	if err != nil {
+		return nil, err
+	}
+	observed, err := parseUnixNano("observedTimeUnixNano", r.ObservedTimeUnixNano)
+	if err != nil {
+		return nil, err
+	}
+	attributes, err := attributesToProto(r.Attributes)
+	if err != nil {
+		return nil, err
+	}
+
+	record := &logspb.LogRecord{
+		TimeUnixNano:           t,
+		ObservedTimeUnixNano:   observed,
+		SeverityText:           dash0.StringValue(r.SeverityText),
+		Attributes:             attributes,
+		DroppedAttributesCount: uint32(dash0.Int64Value(r.DroppedAttributesCount)),
+		Flags:                  uint32(dash0.Int64Value(r.Flags)),
+		EventName:              dash0.StringValue(r.EventName),
+	}
+	if r.SeverityNumber != nil {
+		record.SeverityNumber = logspb.SeverityNumber(*r.SeverityNumber)
+	}
+	if r.Body != nil {
+		body, err := valueToProto(r.Body)
+		if err != nil {
+			return nil, fmt.Errorf("body: %w", err)
+		}
+		record.Body = body
+	}
+	if r.TraceId != nil {
+		record.TraceId = *r.TraceId
+	}
+	if r.SpanId != nil {
+		record.SpanId = *r.SpanId
+	}
+	return record, nil
+}
+
+func logRecordFromProto(r *logspb.LogRecord) dash0.LogRecord {
+	record := dash0.LogRecord{
+		TimeUnixNano:           formatUnixNano(r.GetTimeUnixNano()),
+		ObservedTimeUnixNano:   formatUnixNano(r.GetObservedTimeUnixNano()),
+		SeverityText:           optionalString(r.GetSeverityText()),
+		Attributes:             attributesFromProto(r.GetAttributes()),
+		DroppedAttributesCount: optionalInt64(int64(r.GetDroppedAttributesCount())),
+		Flags:                  optionalInt64(int64(r.GetFlags())),
+		EventName:              optionalString(r.GetEventName()),
+		TraceId:                optionalBytes(r.GetTraceId()),
+		SpanId:                 optionalBytes(r.GetSpanId()),
+	}
+	if n := r.GetSeverityNumber(); n != logspb.SeverityNumber_SEVERITY_NUMBER_UNSPECIFIED {
+		record.SeverityNumber = dash0.Ptr(dash0.SeverityNumber(n))
+	}
+	if r.GetBody() != nil {
+		record.Body = dash0.Ptr(valueFromProto(r.GetBody()))
+	}
+	return record
+}
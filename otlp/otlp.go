@@ -0,0 +1,189 @@
+// Package otlp converts spans and log records returned by the Dash0 API to
+// and from OpenTelemetry Protocol (OTLP) payloads.
+//
+// The API types mirror OTLP, but use their own JSON shape: IDs are []byte,
+// timestamps are decimal strings and AnyValue.IntValue is a string. This
+// package converts them to ExportTraceServiceRequest and
+// ExportLogsServiceRequest messages of go.opentelemetry.io/proto/otlp, which
+// can be sent to an OpenTelemetry Collector, and to canonical OTLP/JSON as
+// described in the OTLP specification, with hexadecimal trace and span IDs.
+//
+// Conversion preserves all OTLP fields. Fields that only exist in the Dash0
+// API, such as the OTLP schema extensions and the derived Resource fields,
+// are dropped. Since proto3 does not distinguish zero values from missing
+// values, optional fields that are zero, such as a Flags of 0, are nil after
+// converting back. Array and key-value list values, which dash0.AnyValue
+// cannot hold, are converted to their OTLP/JSON encoding in StringValue.
+//
+// Example:
+//
+//	req, err := otlp.TracesToProto(resp.ResourceSpans)
+//	if err != nil {
+//	    // handle error
+//	}
+//	_, err = collectortrace.NewTraceServiceClient(conn).Export(ctx, req)
+package otlp
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/dash0hq/dash0-api-client-go"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	resourcepb "go.opentelemetry.io/proto/otlp/resource/v1"
+)
+
+func resourceToProto(r *dash0.Resource) (*resourcepb.Resource, error) {
+	attributes, err := attributesToProto(r.Attributes)
+	if err != nil {
+		return nil, err
+	}
+	return &resourcepb.Resource{
+		Attributes:             attributes,
+		DroppedAttributesCount: uint32(dash0.Int64Value(r.DroppedAttributesCount)),
+	}, nil
+}
+
+func resourceFromProto(r *resourcepb.Resource) dash0.Resource {
+	return dash0.Resource{
+		Attributes:             attributesFromProto(r.GetAttributes()),
+		DroppedAttributesCount: optionalInt64(int64(r.GetDroppedAttributesCount())),
+	}
+}
+
+func scopeToProto(s *dash0.InstrumentationScope) (*commonpb.InstrumentationScope, error) {
+	if s == nil {
+		return nil, nil
+	}
+	attributes, err := attributesToProto(s.Attributes)
+	if err != nil {
+		return nil, err
+	}
+	return &commonpb.InstrumentationScope{
+		Name:                   dash0.StringValue(s.Name),
+		Version:                dash0.StringValue(s.Version),
+		Attributes:             attributes,
+		DroppedAttributesCount: uint32(dash0.Int64Value(s.DroppedAttributesCount)),
+	}, nil
+}
+
+func scopeFromProto(s *commonpb.InstrumentationScope) *dash0.InstrumentationScope {
+	if s == nil {
+		return nil
+	}
+	return &dash0.InstrumentationScope{
+		Name:                   optionalString(s.GetName()),
+		Version:                optionalString(s.GetVersion()),
+		Attributes:             attributesFromProto(s.GetAttributes()),
+		DroppedAttributesCount: optionalInt64(int64(s.GetDroppedAttributesCount())),
+	}
+}
+
+func attributesToProto(attributes []dash0.KeyValue) ([]*commonpb.KeyValue, error) {
+	if len(attributes) == 0 {
+		return nil, nil
+	}
+	result := make([]*commonpb.KeyValue, len(attributes))
+	for i, kv := range attributes {
+		value, err := valueToProto(&kv.Value)
+		if err != nil {
+			return nil, fmt.Errorf("attribute %q: %w", kv.Key, err)
+		}
+		result[i] = &commonpb.KeyValue{Key: kv.Key, Value: value}
+	}
+	return result, nil
+}
+
+func attributesFromProto(attributes []*commonpb.KeyValue) []dash0.KeyValue {
+	if len(attributes) == 0 {
+		return nil
+	}
+	result := make([]dash0.KeyValue, len(attributes))
+	for i, kv := range attributes {
+		result[i] = dash0.KeyValue{Key: kv.GetKey(), Value: valueFromProto(kv.GetValue())}
+	}
+	return result
+}
+
+func valueToProto(v *dash0.AnyValue) (*commonpb.AnyValue, error) {
+	switch {
+	case v.StringValue != nil:
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: *v.StringValue}}, nil
+	case v.IntValue != nil:
+		n, err := strconv.ParseInt(*v.IntValue, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid intValue %q: %w", *v.IntValue, err)
+		}
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_IntValue{IntValue: n}}, nil
+	case v.DoubleValue != nil:
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_DoubleValue{DoubleValue: *v.DoubleValue}}, nil
+	case v.BoolValue != nil:
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_BoolValue{BoolValue: *v.BoolValue}}, nil
+	case v.BytesValue != nil:
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_BytesValue{BytesValue: *v.BytesValue}}, nil
+	default:
+		return &commonpb.AnyValue{}, nil
+	}
+}
+
+func valueFromProto(v *commonpb.AnyValue) dash0.AnyValue {
+	switch value := v.GetValue().(type) {
+	case *commonpb.AnyValue_StringValue:
+		return dash0.AnyValue{StringValue: dash0.String(value.StringValue)}
+	case *commonpb.AnyValue_IntValue:
+		return dash0.AnyValue{IntValue: dash0.String(strconv.FormatInt(value.IntValue, 10))}
+	case *commonpb.AnyValue_DoubleValue:
+		return dash0.AnyValue{DoubleValue: dash0.Float64(value.DoubleValue)}
+	case *commonpb.AnyValue_BoolValue:
+		return dash0.AnyValue{BoolValue: dash0.Bool(value.BoolValue)}
+	case *commonpb.AnyValue_BytesValue:
+		return dash0.AnyValue{BytesValue: dash0.Ptr(value.BytesValue)}
+	case *commonpb.AnyValue_ArrayValue, *commonpb.AnyValue_KvlistValue:
+		// dash0.AnyValue has no representation for nested values
+		b, err := marshalJSON(v)
+		if err != nil {
+			return dash0.AnyValue{}
+		}
+		return dash0.AnyValue{StringValue: dash0.String(string(b))}
+	default:
+		return dash0.AnyValue{}
+	}
+}
+
+// parseUnixNano parses a decimal timestamp in nanoseconds since the Unix
+// epoch. An empty timestamp is 0.
+func parseUnixNano(field, s string) (uint64, error) {
+	if s == "" {
+		return 0, nil
+	}
+	n, err := strconv.ParseUint(s, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s %q: %w", field, s, err)
+	}
+	return n, nil
+}
+
+func formatUnixNano(n uint64) string {
+	return strconv.FormatUint(n, 10)
+}
+
+func optionalString(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
+
+func optionalInt64(n int64) *int64 {
+	if n == 0 {
+		return nil
+	}
+	return &n
+}
+
+func optionalBytes(b []byte) *[]byte {
+	if len(b) == 0 {
+		return nil
+	}
+	return &b
+}
@@ -0,0 +1,206 @@
+package otlp
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/dash0hq/dash0-api-client-go"
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+var (
+	traceID = []byte{0x4b, 0xf9, 0x2f, 0x35, 0x77, 0xb3, 0x4d, 0xa6, 0xa3, 0xce, 0x92, 0x9d, 0x0e, 0x0e, 0x47, 0x36}
+	spanID  = []byte{0x00, 0xf0, 0x67, 0xaa, 0x0b, 0xa9, 0x02, 0xb7}
+)
+
+func testResourceSpans() []dash0.ResourceSpans {
+	return []dash0.ResourceSpans{{
+		Resource: dash0.Resource{
+			Attributes: []dash0.KeyValue{{Key: "service.name", Value: dash0.AnyValue{StringValue: dash0.String("checkout")}}},
+		},
+		SchemaUrl: dash0.String("https://opentelemetry.io/schemas/1.26.0"),
+		ScopeSpans: []dash0.ScopeSpans{{
+			Scope: &dash0.InstrumentationScope{Name: dash0.String("http"), Version: dash0.String("1.0.0")},
+			Spans: []dash0.Span{{
+				TraceId:           traceID,
+				SpanId:            spanID,
+				ParentSpanId:      dash0.Ptr([]byte{1, 2, 3, 4, 5, 6, 7, 8}),
+				TraceState:        dash0.String("vendor=value"),
+				Flags:             dash0.Int64(1),
+				Name:              "GET /cart",
+				Kind:              2,
+				StartTimeUnixNano: "1705329000123456789",
+				EndTimeUnixNano:   "1705329000223456789",
+				Attributes: []dash0.KeyValue{
+					{Key: "http.status_code", Value: dash0.AnyValue{IntValue: dash0.String("9007199254740993")}},
+					{Key: "ratio", Value: dash0.AnyValue{DoubleValue: dash0.Float64(0.5)}},
+					{Key: "cached", Value: dash0.AnyValue{BoolValue: dash0.Bool(true)}},
+					{Key: "payload", Value: dash0.AnyValue{BytesValue: dash0.Ptr([]byte("<&>"))}},
+				},
+				DroppedAttributesCount: dash0.Int64(2),
+				Events: []dash0.SpanEvent{{
+					Name:         "exception",
+					TimeUnixNano: "1705329000200000000",
+					Attributes:   []dash0.KeyValue{{Key: "exception.message", Value: dash0.AnyValue{StringValue: dash0.String("boom")}}},
+				}},
+				Links: []dash0.SpanLink{{
+					TraceId:    traceID,
+					SpanId:     []byte{8, 7, 6, 5, 4, 3, 2, 1},
+					Attributes: []dash0.KeyValue{{Key: "messaging.operation", Value: dash0.AnyValue{StringValue: dash0.String("receive")}}},
+				}},
+				Status: dash0.SpanStatus{Code: 2, Message: dash0.String("failed")},
+			}},
+		}},
+	}}
+}
+
+func testResourceLogs() []dash0.ResourceLogs {
+	return []dash0.ResourceLogs{{
+		Resource: dash0.Resource{
+			Attributes: []dash0.KeyValue{{Key: "service.name", Value: dash0.AnyValue{StringValue: dash0.String("checkout")}}},
+		},
+		ScopeLogs: []dash0.ScopeLogs{{
+			LogRecords: []dash0.LogRecord{{
+				TimeUnixNano:         "1705329000123456789",
+				ObservedTimeUnixNano: "1705329000123456790",
+				SeverityNumber:       dash0.Ptr(dash0.SeverityNumber(17)),
+				SeverityText:         dash0.String("ERROR"),
+				Body:                 &dash0.AnyValue{StringValue: dash0.String("payment failed")},
+				EventName:            dash0.String("payment.failed"),
+				TraceId:              dash0.Ptr(traceID),
+				SpanId:               dash0.Ptr(spanID),
+				Flags:                dash0.Int64(1),
+			}},
+		}},
+	}}
+}
+
+func TestTraces(t *testing.T) {
+	t.Run("round trips through protobuf", func(t *testing.T) {
+		req, err := TracesToProto(testResourceSpans())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		b, err := proto.Marshal(req)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		var decoded coltracepb.ExportTraceServiceRequest
+		if err := proto.Unmarshal(b, &decoded); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		span := decoded.ResourceSpans[0].ScopeSpans[0].Spans[0]
+		if span.StartTimeUnixNano != 1705329000123456789 || span.Attributes[0].Value.GetIntValue() != 9007199254740993 {
+			t.Errorf("unexpected span %v", span)
+		}
+		if got := TracesFromProto(&decoded); !reflect.DeepEqual(got, testResourceSpans()) {
+			t.Errorf("round trip mismatch:\n got %+v\nwant %+v", got, testResourceSpans())
+		}
+	})
+
+	t.Run("round trips through OTLP/JSON", func(t *testing.T) {
+		b, err := MarshalTracesJSON(testResourceSpans())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		for _, want := range []string{
+			`"traceId":"4bf92f3577b34da6a3ce929d0e0e4736"`,
+			`"spanId":"00f067aa0ba902b7"`,
+			`"parentSpanId":"0102030405060708"`,
+			`"kind":2`,
+			`"code":2`,
+			`"startTimeUnixNano":"1705329000123456789"`,
+			`"intValue":"9007199254740993"`,
+			`"bytesValue":"PCY+"`,
+		} {
+			if !strings.Contains(string(b), want) {
+				t.Errorf("expected %s in %s", want, b)
+			}
+		}
+
+		again, err := MarshalTracesJSON(testResourceSpans())
+		if err != nil || string(again) != string(b) {
+			t.Errorf("expected deterministic output, got %s", again)
+		}
+
+		got, err := UnmarshalTracesJSON(b)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !reflect.DeepEqual(got, testResourceSpans()) {
+			t.Errorf("round trip mismatch:\n got %+v\nwant %+v", got, testResourceSpans())
+		}
+	})
+
+	t.Run("accepts enum names and unknown fields", func(t *testing.T) {
+		got, err := UnmarshalTracesJSON([]byte(`{"resourceSpans":[{"scopeSpans":[{"spans":[
+			{"traceId":"4bf92f3577b34da6a3ce929d0e0e4736","spanId":"00f067aa0ba902b7","kind":"SPAN_KIND_CLIENT","startTimeUnixNano":1,"futureField":true}
+		]}]}]}`))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		span := got[0].ScopeSpans[0].Spans[0]
+		if span.Kind != 3 || span.StartTimeUnixNano != "1" || !reflect.DeepEqual(span.TraceId, traceID) {
+			t.Errorf("unexpected span %+v", span)
+		}
+	})
+
+	t.Run("rejects invalid input", func(t *testing.T) {
+		invalid := testResourceSpans()
+		invalid[0].ScopeSpans[0].Spans[0].StartTimeUnixNano = "yesterday"
+		if _, err := TracesToProto(invalid); err == nil || !strings.Contains(err.Error(), "scopeSpans[0].spans[0]") {
+			t.Errorf("expected error with location, got %v", err)
+		}
+		if _, err := UnmarshalTracesJSON([]byte(`{"resourceSpans":[{"scopeSpans":[{"spans":[{"traceId":"xyz"}]}]}]}`)); err == nil {
+			t.Error("expected error for invalid trace ID")
+		}
+	})
+
+	t.Run("encodes nested values as JSON strings", func(t *testing.T) {
+		req := &coltracepb.ExportTraceServiceRequest{ResourceSpans: []*tracepb.ResourceSpans{{
+			ScopeSpans: []*tracepb.ScopeSpans{{Spans: []*tracepb.Span{{
+				Attributes: []*commonpb.KeyValue{{Key: "tags", Value: &commonpb.AnyValue{Value: &commonpb.AnyValue_ArrayValue{
+					ArrayValue: &commonpb.ArrayValue{Values: []*commonpb.AnyValue{{Value: &commonpb.AnyValue_StringValue{StringValue: "a"}}}},
+				}}}},
+			}}}},
+		}}}
+
+		value := TracesFromProto(req)[0].ScopeSpans[0].Spans[0].Attributes[0].Value
+		var decoded map[string]any
+		if err := json.Unmarshal([]byte(dash0.StringValue(value.StringValue)), &decoded); err != nil || decoded["arrayValue"] == nil {
+			t.Errorf("expected arrayValue JSON, got %v", dash0.StringValue(value.StringValue))
+		}
+	})
+}
+
+func TestLogs(t *testing.T) {
+	b, err := MarshalLogsJSON(testResourceLogs())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(string(b), `"severityNumber":17`) || !strings.Contains(string(b), `"spanId":"00f067aa0ba902b7"`) {
+		t.Errorf("unexpected JSON %s", b)
+	}
+
+	got, err := UnmarshalLogsJSON(b)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(got, testResourceLogs()) {
+		t.Errorf("round trip mismatch:\n got %+v\nwant %+v", got, testResourceLogs())
+	}
+
+	req, err := LogsToProto(testResourceLogs())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(LogsFromProto(req), testResourceLogs()) {
+		t.Error("protobuf round trip mismatch")
+	}
+}
@@ -0,0 +1,192 @@
+package otlp
+
+import (
+	"fmt"
+
+	"github.com/dash0hq/dash0-api-client-go"
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+)
+
+// TracesToProto converts resource spans to an OTLP export request.
+// It returns an error if a timestamp or an integer value is not a valid
+// decimal number.
+func TracesToProto(resourceSpans []dash0.ResourceSpans) (*coltracepb.ExportTraceServiceRequest, error) {
+	req := &coltracepb.ExportTraceServiceRequest{ResourceSpans: make([]*tracepb.ResourceSpans, len(resourceSpans))}
+	for i := range resourceSpans {
+		rs, err := resourceSpansToProto(&resourceSpans[i])
+		if err != nil {
+			return nil, fmt.Errorf("resourceSpans[%d]: %w", i, err)
+		}
+		req.ResourceSpans[i] = rs
+	}
+	return req, nil
+}
+
+// TracesFromProto converts an OTLP export request to resource spans.
+func TracesFromProto(req *coltracepb.ExportTraceServiceRequest) []dash0.ResourceSpans {
+	result := make([]dash0.ResourceSpans, len(req.GetResourceSpans()))
+	for i, rs := range req.GetResourceSpans() {
+		result[i] = dash0.ResourceSpans{
+			Resource:   resourceFromProto(rs.GetResource()),
+			SchemaUrl:  optionalString(rs.GetSchemaUrl()),
+			ScopeSpans: make([]dash0.ScopeSpans, len(rs.GetScopeSpans())),
+		}
+		for j, ss := range rs.GetScopeSpans() {
+			scopeSpans := dash0.ScopeSpans{
+				Scope:     scopeFromProto(ss.GetScope()),
+				SchemaUrl: optionalString(ss.GetSchemaUrl()),
+				Spans:     make([]dash0.Span, len(ss.GetSpans())),
+			}
+			for k, span := range ss.GetSpans() {
+				scopeSpans.Spans[k] = spanFromProto(span)
+			}
+			result[i].ScopeSpans[j] = scopeSpans
+		}
+	}
+	return result
+}
+
+func resourceSpansToProto(rs *dash0.ResourceSpans) (*tracepb.ResourceSpans, error) {
+	resource, err := resourceToProto(&rs.Resource)
+	if err != nil {
+		return nil, fmt.Errorf("resource: %w", err)
+	}
+	result := &tracepb.ResourceSpans{
+		Resource:   resource,
+		SchemaUrl:  dash0.StringValue(rs.SchemaUrl),
+		ScopeSpans: make([]*tracepb.ScopeSpans, len(rs.ScopeSpans)),
+	}
+	for j := range rs.ScopeSpans {
+		ss := &rs.ScopeSpans[j]
+		scope, err := scopeToProto(ss.Scope)
+		if err != nil {
+			return nil, fmt.Errorf("scopeSpans[%d].scope: %w", j, err)
+		}
+		scopeSpans := &tracepb.ScopeSpans{
+			Scope:     scope,
+			SchemaUrl: dash0.StringValue(ss.SchemaUrl),
+			Spans:     make([]*tracepb.Span, len(ss.Spans)),
+		}
+		for k := range ss.Spans {
+			span, err := spanToProto(&ss.Spans[k])
+			if err != nil {
+				return nil, fmt.Errorf("scopeSpans[%d].spans[%d]: %w", j, k, err)
+			}
+			scopeSpans.Spans[k] = span
+		}
+		result.ScopeSpans[j] = scopeSpans
+	}
+	return result, nil
+}
+
+func spanToProto(s *dash0.Span) (*tracepb.Span, error) {
+	start, err := parseUnixNano("startTimeUnixNano", s.StartTimeUnixNano)
+	if err != nil {
+		return nil, err
+	}
+	end, err := parseUnixNano("endTimeUnixNano", s.EndTimeUnixNano)
+	if err != nil {
+		return nil, err
+	}
+	attributes, err := attributesToProto(s.Attributes)
+	if err != nil {
+		return nil, err
+	}
+
+	span := &tracepb.Span{
+		TraceId:                s.TraceId,
+		SpanId:                 s.SpanId,
+		TraceState:             dash0.StringValue(s.TraceState),
+		Flags:                  uint32(dash0.Int64Value(s.Flags)),
+		Name:                   s.Name,
+		Kind:                   tracepb.Span_SpanKind(s.Kind),
+		StartTimeUnixNano:      start,
+		EndTimeUnixNano:        end,
+		Attributes:             attributes,
+		DroppedAttributesCount: uint32(dash0.Int64Value(s.DroppedAttributesCount)),
+		DroppedEventsCount:     uint32(dash0.Int64Value(s.DroppedEventsCount)),
+		DroppedLinksCount:      uint32(dash0.Int64Value(s.DroppedLinksCount)),
+		Status: &tracepb.Status{
+			Code:    tracepb.Status_StatusCode(s.Status.Code),
+			Message: dash0.StringValue(s.Status.Message),
+		},
+	}
+	if s.ParentSpanId != nil {
+		span.ParentSpanId = *s.ParentSpanId
+	}
+	for i := range s.Events {
+		e := &s.Events[i]
+		t, err := parseUnixNano("timeUnixNano", e.TimeUnixNano)
+		if err != nil {
+			return nil, fmt.Errorf("events[%d]: %w", i, err)
+		}
+		attributes, err := attributesToProto(e.Attributes)
+		if err != nil {
+			return nil, fmt.Errorf("events[%d]: %w", i, err)
+		}
+		span.Events = append(span.Events, &tracepb.Span_Event{
+			TimeUnixNano:           t,
+			Name:                   e.Name,
+			Attributes:             attributes,
+			DroppedAttributesCount: uint32(dash0.Int64Value(e.DroppedAttributesCount)),
+		})
+	}
+	for i := range s.Links {
+		l := &s.Links[i]
+		attributes, err := attributesToProto(l.Attributes)
+		if err != nil {
+			return nil, fmt.Errorf("links[%d]: %w", i, err)
+		}
+		span.Links = append(span.Links, &tracepb.Span_Link{
+			TraceId:                l.TraceId,
+			SpanId:                 l.SpanId,
+			TraceState:             dash0.StringValue(l.TraceState),
+			Attributes:             attributes,
+			DroppedAttributesCount: uint32(dash0.Int64Value(l.DroppedAttributesCount)),
+			Flags:                  uint32(dash0.Int64Value(l.Flags)),
+		})
+	}
+	return span, nil
+}
+
+func spanFromProto(s *tracepb.Span) dash0.Span {
+	span := dash0.Span{
+		TraceId:                s.GetTraceId(),
+		SpanId:                 s.GetSpanId(),
+		ParentSpanId:           optionalBytes(s.GetParentSpanId()),
+		TraceState:             optionalString(s.GetTraceState()),
+		Flags:                  optionalInt64(int64(s.GetFlags())),
+		Name:                   s.GetName(),
+		Kind:                   dash0.SpanKind(s.GetKind()),
+		StartTimeUnixNano:      formatUnixNano(s.GetStartTimeUnixNano()),
+		EndTimeUnixNano:        formatUnixNano(s.GetEndTimeUnixNano()),
+		Attributes:             attributesFromProto(s.GetAttributes()),
+		DroppedAttributesCount: optionalInt64(int64(s.GetDroppedAttributesCount())),
+		DroppedEventsCount:     optionalInt64(int64(s.GetDroppedEventsCount())),
+		DroppedLinksCount:      optionalInt64(int64(s.GetDroppedLinksCount())),
+		Status: dash0.SpanStatus{
+			Code:    dash0.SpanStatusCode(s.GetStatus().GetCode()),
+			Message: optionalString(s.GetStatus().GetMessage()),
+		},
+	}
+	for _, e := range s.GetEvents() {
+		span.Events = append(span.Events, dash0.SpanEvent{
+			TimeUnixNano:           formatUnixNano(e.GetTimeUnixNano()),
+			Name:                   e.GetName(),
+			Attributes:             attributesFromProto(e.GetAttributes()),
+			DroppedAttributesCount: optionalInt64(int64(e.GetDroppedAttributesCount())),
+		})
+	}
+	for _, l := range s.GetLinks() {
+		span.Links = append(span.Links, dash0.SpanLink{
+			TraceId:                l.GetTraceId(),
+			SpanId:                 l.GetSpanId(),
+			TraceState:             optionalString(l.GetTraceState()),
+			Attributes:             attributesFromProto(l.GetAttributes()),
+			DroppedAttributesCount: optionalInt64(int64(l.GetDroppedAttributesCount())),
+			Flags:                  optionalInt64(int64(l.GetFlags())),
+		})
+	}
+	return span
+}
@@ -0,0 +1,459 @@
+package perses
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"strconv"
+	"strings"
+
+	"github.com/dash0hq/dash0-api-client-go"
+)
+
+// GridColumns is the width of the dashboard grid in grid units.
+const GridColumns = 24
+
+// DefaultDuration is the default time range of built dashboards.
+const DefaultDuration = "1h"
+
+// Plugin kinds used by the builder.
+const (
+	PluginTimeSeriesChart               = "TimeSeriesChart"
+	PluginStatChart                     = "StatChart"
+	PluginTable                         = "Table"
+	PluginPrometheusTimeSeriesQuery     = "PrometheusTimeSeriesQuery"
+	PluginPrometheusLabelValuesVariable = "PrometheusLabelValuesVariable"
+	PluginStaticListVariable            = "StaticListVariable"
+	PluginPrometheusDatasource          = "PrometheusDatasource"
+)
+
+// DashboardBuilder builds a DashboardDefinition. Use NewDashboard to create
+// one.
+//
+// Panels are added in rows, which become collapsible grid layouts, and are
+// laid out automatically: left to right in the order they are added,
+// wrapping to a new line when the grid is full. Panel keys are derived from
+// the panel titles, so they are stable across builds.
+//
+// Invalid panels and variables do not stop the chain; they are collected
+// and reported by Build.
+//
+// Example:
+//
+//	dashboard, err := perses.NewDashboard("checkout").
+//	    ID("service-checkout").
+//	    Duration("1h").
+//	    Variable(perses.LabelValuesVariable("pod", "k8s_pod_name", `{service_name="checkout"}`).AllowMultiple()).
+//	    Row("Requests",
+//	        perses.TimeSeries("Request rate").
+//	            Query(perses.PromQL(`sum by (route) (rate(http_requests_total{service_name="checkout"}[5m]))`).Legend("{{route}}")),
+//	        perses.Stat("Error rate").Unit("percent").
+//	            Query(perses.PromQL(`100 * sum(rate(http_requests_total{service_name="checkout",status=~"5.."}[5m])) / sum(rate(http_requests_total{service_name="checkout"}[5m]))`)),
+//	    ).
+//	    Build()
+//	if err != nil {
+//	    // handle error
+//	}
+//	_, err = client.CreateDashboard(ctx, dashboard, nil)
+type DashboardBuilder struct {
+	metadata dash0.DashboardMetadata
+	spec     DashboardSpec
+	errs     []error
+}
+
+// NewDashboard creates a DashboardBuilder for a dashboard with the given name.
+func NewDashboard(name string) *DashboardBuilder {
+	b := &DashboardBuilder{
+		metadata: dash0.DashboardMetadata{Name: name},
+		spec: DashboardSpec{
+			Display:   &Display{Name: dash0.String(name)},
+			Duration:  dash0.String(DefaultDuration),
+			Variables: []*Variable{},
+			Panels:    make(map[string]*Panel),
+			Layouts:   []*Layout{},
+		},
+	}
+	if strings.TrimSpace(name) == "" {
+		b.errs = append(b.errs, errors.New("dash0: perses: dashboard name is empty"))
+	}
+	return b
+}
+
+// ID sets the ID of the dashboard, i.e. DashboardMetadataExtensions.Id,
+// which identifies it for upserts and declarative sync.
+func (b *DashboardBuilder) ID(id string) *DashboardBuilder {
+	b.extensions().Id = dash0.String(id)
+	return b
+}
+
+// Description sets the description of the dashboard.
+func (b *DashboardBuilder) Description(description string) *DashboardBuilder {
+	b.spec.Display.Description = dash0.String(description)
+	return b
+}
+
+// Tags sets the tags of the dashboard.
+func (b *DashboardBuilder) Tags(tags ...string) *DashboardBuilder {
+	b.extensions().Tags = &tags
+	return b
+}
+
+// Folder sets the folder path of the dashboard, e.g. "services/checkout".
+func (b *DashboardBuilder) Folder(path string) *DashboardBuilder {
+	if b.metadata.Annotations == nil {
+		b.metadata.Annotations = &dash0.DashboardAnnotations{}
+	}
+	b.metadata.Annotations.Dash0ComfolderPath = dash0.String(path)
+	return b
+}
+
+// Duration sets the default time range of the dashboard, e.g. "6h". Defaults
+// to DefaultDuration.
+func (b *DashboardBuilder) Duration(duration string) *DashboardBuilder {
+	b.spec.Duration = dash0.String(duration)
+	return b
+}
+
+// RefreshInterval sets the default refresh interval, e.g. "30s".
+func (b *DashboardBuilder) RefreshInterval(interval string) *DashboardBuilder {
+	b.spec.RefreshInterval = dash0.String(interval)
+	return b
+}
+
+// Datasource adds a datasource under the given name. Queries refer to it via
+// QueryBuilder.Datasource.
+func (b *DashboardBuilder) Datasource(name string, datasource *Datasource) *DashboardBuilder {
+	if b.spec.Datasources == nil {
+		b.spec.Datasources = make(map[string]*Datasource)
+	}
+	b.spec.Datasources[name] = datasource
+	return b
+}
+
+// Variable adds a copy of the variable, so a VariableBuilder can be reused
+// and modified for other dashboards. Variable names must be unique.
+func (b *DashboardBuilder) Variable(v *VariableBuilder) *DashboardBuilder {
+	for _, existing := range b.spec.Variables {
+		if existing.Spec.Name == v.variable.Spec.Name {
+			b.errs = append(b.errs, fmt.Errorf("dash0: perses: duplicate variable %q", v.variable.Spec.Name))
+			return b
+		}
+	}
+	if v.variable.Spec.Name == "" {
+		b.errs = append(b.errs, errors.New("dash0: perses: variable name is empty"))
+		return b
+	}
+	if values, ok := v.variable.Spec.DefaultValue.([]string); ok && len(values) > 1 && !dash0.BoolValue(v.variable.Spec.AllowMultiple) {
+		b.errs = append(b.errs, fmt.Errorf("dash0: perses: variable %q has several default values, but does not allow multiple", v.variable.Spec.Name))
+		return b
+	}
+	variable, err := clone(&v.variable)
+	if err != nil {
+		b.errs = append(b.errs, fmt.Errorf("dash0: perses: variable %q: %w", v.variable.Spec.Name, err))
+		return b
+	}
+	b.spec.Variables = append(b.spec.Variables, variable)
+	return b
+}
+
+// Row adds a collapsible row with the given title and panels.
+func (b *DashboardBuilder) Row(title string, panels ...*PanelBuilder) *DashboardBuilder {
+	return b.addGrid(&GridDisplay{Title: title, Collapse: &GridCollapse{Open: true}}, panels)
+}
+
+// Panels adds panels outside of any row.
+func (b *DashboardBuilder) Panels(panels ...*PanelBuilder) *DashboardBuilder {
+	return b.addGrid(nil, panels)
+}
+
+// addGrid adds copies of the panels and a grid layout that places them, so
+// a PanelBuilder can be reused and modified for other rows or dashboards.
+func (b *DashboardBuilder) addGrid(display *GridDisplay, panels []*PanelBuilder) *DashboardBuilder {
+	grid := &Layout{Kind: KindGrid, Spec: GridLayoutSpec{Display: display, Items: []*GridItem{}}}
+	var x, y, lineHeight int
+	for _, p := range panels {
+		if err := p.validate(); err != nil {
+			b.errs = append(b.errs, err)
+			continue
+		}
+		panel, err := clone(&p.panel)
+		if err != nil {
+			b.errs = append(b.errs, fmt.Errorf("dash0: perses: panel %q: %w", p.title, err))
+			continue
+		}
+		if x+p.width > GridColumns {
+			x, y, lineHeight = 0, y+lineHeight, 0
+		}
+		key := b.panelKey(p.title)
+		b.spec.Panels[key] = panel
+		grid.Spec.Items = append(grid.Spec.Items, &GridItem{X: x, Y: y, Width: p.width, Height: p.height, Content: PanelRef(key)})
+		x += p.width
+		lineHeight = max(lineHeight, p.height)
+	}
+	b.spec.Layouts = append(b.spec.Layouts, grid)
+	return b
+}
+
+// panelKey returns a key for a panel derived from its title, with a numeric
+// suffix if the key is taken.
+func (b *DashboardBuilder) panelKey(title string) string {
+	var key strings.Builder
+	for _, r := range strings.ToLower(title) {
+		switch {
+		case r >= 'a' && r <= 'z' || r >= '0' && r <= '9':
+			key.WriteRune(r)
+		case key.Len() > 0 && !strings.HasSuffix(key.String(), "_"):
+			key.WriteByte('_')
+		}
+	}
+	base := strings.TrimSuffix(key.String(), "_")
+	if base == "" {
+		base = "panel"
+	}
+	candidate := base
+	for i := 2; b.spec.Panels[candidate] != nil; i++ {
+		candidate = base + "_" + strconv.Itoa(i)
+	}
+	return candidate
+}
+
+func (b *DashboardBuilder) extensions() *dash0.DashboardMetadataExtensions {
+	if b.metadata.Dash0Extensions == nil {
+		b.metadata.Dash0Extensions = &dash0.DashboardMetadataExtensions{}
+	}
+	return b.metadata.Dash0Extensions
+}
+
+// Spec validates the dashboard and returns a copy of its spec, so that the
+// builder can be extended further without affecting the returned spec. All
+// invalid panels and variables are reported together, joined with
+// errors.Join.
+func (b *DashboardBuilder) Spec() (*DashboardSpec, error) {
+	if err := errors.Join(b.errs...); err != nil {
+		return nil, err
+	}
+	m, err := b.spec.Map()
+	if err != nil {
+		return nil, err
+	}
+	return ParseSpec(m)
+}
+
+// Build validates the dashboard and returns it as a DashboardDefinition for
+// CreateDashboard or UpdateDashboard. Like Spec, it returns a copy that is
+// not affected by further calls on the builder.
+func (b *DashboardBuilder) Build() (*dash0.DashboardDefinition, error) {
+	spec, err := b.Spec()
+	if err != nil {
+		return nil, err
+	}
+	m, err := spec.Map()
+	if err != nil {
+		return nil, err
+	}
+	metadata, err := clone(&b.metadata)
+	if err != nil {
+		return nil, fmt.Errorf("dash0: perses: invalid dashboard metadata: %w", err)
+	}
+	return &dash0.DashboardDefinition{Kind: dash0.Dashboard, Metadata: *metadata, Spec: m}, nil
+}
+
+// PanelBuilder builds a panel. Use TimeSeries, Stat or Table to create one.
+type PanelBuilder struct {
+	title         string
+	panel         Panel
+	width, height int
+}
+
+func newPanel(title, plugin string, width, height int) *PanelBuilder {
+	return &PanelBuilder{
+		title: title,
+		panel: Panel{Kind: KindPanel, Spec: PanelSpec{
+			Display: &Display{Name: dash0.String(title)},
+			Plugin:  Plugin{Kind: plugin, Spec: map[string]any{}},
+			Queries: []*Query{},
+		}},
+		width:  width,
+		height: height,
+	}
+}
+
+// TimeSeries creates a time series chart panel, 12 by 6 grid units by
+// default.
+func TimeSeries(title string) *PanelBuilder {
+	return newPanel(title, PluginTimeSeriesChart, 12, 6)
+}
+
+// Stat creates a stat panel showing the last value of its query, 6 by 4
+// grid units by default.
+func Stat(title string) *PanelBuilder {
+	p := newPanel(title, PluginStatChart, 6, 4)
+	p.panel.Spec.Plugin.Spec["calculation"] = "last-number"
+	return p
+}
+
+// Table creates a table panel, 24 by 8 grid units by default.
+func Table(title string) *PanelBuilder {
+	return newPanel(title, PluginTable, GridColumns, 8)
+}
+
+// Description sets the description of the panel.
+func (p *PanelBuilder) Description(description string) *PanelBuilder {
+	p.panel.Spec.Display.Description = dash0.String(description)
+	return p
+}
+
+// Size sets the width and height of the panel in grid units. The width must
+// be between 1 and GridColumns.
+func (p *PanelBuilder) Size(width, height int) *PanelBuilder {
+	p.width, p.height = width, height
+	return p
+}
+
+// Unit sets the unit of the values of time series and stat panels, e.g.
+// "percent", "seconds" or "bytes".
+func (p *PanelBuilder) Unit(unit string) *PanelBuilder {
+	format := map[string]any{"unit": unit}
+	switch p.panel.Spec.Plugin.Kind {
+	case PluginTimeSeriesChart:
+		p.panel.Spec.Plugin.Spec["yAxis"] = map[string]any{"format": format}
+	case PluginStatChart:
+		p.panel.Spec.Plugin.Spec["format"] = format
+	}
+	return p
+}
+
+// Query adds copies of the queries to the panel, so a QueryBuilder can be
+// reused and modified for other panels.
+func (p *PanelBuilder) Query(queries ...*QueryBuilder) *PanelBuilder {
+	for _, q := range queries {
+		p.panel.Spec.Queries = append(p.panel.Spec.Queries, &Query{
+			Kind: KindTimeSeriesQuery,
+			Spec: QuerySpec{Plugin: Plugin{Kind: PluginPrometheusTimeSeriesQuery, Spec: maps.Clone(q.spec)}},
+		})
+	}
+	return p
+}
+
+func (p *PanelBuilder) validate() error {
+	switch {
+	case strings.TrimSpace(p.title) == "":
+		return errors.New("dash0: perses: panel title is empty")
+	case p.width < 1 || p.width > GridColumns:
+		return fmt.Errorf("dash0: perses: panel %q: width %d is not between 1 and %d", p.title, p.width, GridColumns)
+	case p.height < 1:
+		return fmt.Errorf("dash0: perses: panel %q: height %d is not positive", p.title, p.height)
+	case len(p.panel.Spec.Queries) == 0:
+		return fmt.Errorf("dash0: perses: panel %q has no queries", p.title)
+	}
+	return nil
+}
+
+// QueryBuilder builds a PromQL query. Use PromQL to create one.
+type QueryBuilder struct {
+	spec map[string]any
+}
+
+// PromQL creates a query with the given PromQL expression. Variables are
+// referenced as $name.
+func PromQL(expr string) *QueryBuilder {
+	return &QueryBuilder{spec: map[string]any{"query": expr}}
+}
+
+// Legend sets the name format of the series, e.g. "{{route}}".
+func (q *QueryBuilder) Legend(format string) *QueryBuilder {
+	q.spec["seriesNameFormat"] = format
+	return q
+}
+
+// Datasource queries the Prometheus datasource with the given name instead of
+// the default datasource.
+func (q *QueryBuilder) Datasource(name string) *QueryBuilder {
+	q.spec["datasource"] = map[string]any{"kind": PluginPrometheusDatasource, "name": name}
+	return q
+}
+
+// VariableBuilder builds a variable. Use LabelValuesVariable,
+// StaticListVariable or TextVariable to create one.
+type VariableBuilder struct {
+	variable Variable
+}
+
+func newListVariable(name string, plugin Plugin) *VariableBuilder {
+	return &VariableBuilder{variable: Variable{Kind: KindListVariable, Spec: VariableSpec{
+		Name:          name,
+		Display:       &Display{Name: dash0.String(name)},
+		Plugin:        &plugin,
+		AllowAllValue: dash0.Bool(false),
+		AllowMultiple: dash0.Bool(false),
+	}}}
+}
+
+// LabelValuesVariable creates a list variable with the values of a Prometheus
+// label, optionally restricted to the series matching the matchers, e.g.
+// `{service_name="checkout"}`.
+func LabelValuesVariable(name, labelName string, matchers ...string) *VariableBuilder {
+	return newListVariable(name, Plugin{Kind: PluginPrometheusLabelValuesVariable, Spec: map[string]any{
+		"labelName": labelName,
+		"matchers":  append([]string{}, matchers...),
+	}})
+}
+
+// StaticListVariable creates a list variable with fixed values.
+func StaticListVariable(name string, values ...string) *VariableBuilder {
+	return newListVariable(name, Plugin{Kind: PluginStaticListVariable, Spec: map[string]any{
+		"values": append([]string{}, values...),
+	}})
+}
+
+// TextVariable creates a text variable with the given value.
+func TextVariable(name, value string) *VariableBuilder {
+	return &VariableBuilder{variable: Variable{Kind: KindTextVariable, Spec: VariableSpec{
+		Name:    name,
+		Display: &Display{Name: dash0.String(name)},
+		Value:   dash0.String(value),
+	}}}
+}
+
+// DisplayName sets the name shown in the variable bar. Defaults to the
+// variable name.
+func (v *VariableBuilder) DisplayName(name string) *VariableBuilder {
+	v.variable.Spec.Display.Name = dash0.String(name)
+	return v
+}
+
+// Hidden hides the variable from the variable bar.
+func (v *VariableBuilder) Hidden() *VariableBuilder {
+	v.variable.Spec.Display.Hidden = dash0.Bool(true)
+	return v
+}
+
+// AllowMultiple allows selecting multiple values of a list variable.
+func (v *VariableBuilder) AllowMultiple() *VariableBuilder {
+	if v.variable.Kind == KindListVariable {
+		v.variable.Spec.AllowMultiple = dash0.Bool(true)
+	}
+	return v
+}
+
+// AllowAll adds an "All" option to a list variable.
+func (v *VariableBuilder) AllowAll() *VariableBuilder {
+	if v.variable.Kind == KindListVariable {
+		v.variable.Spec.AllowAllValue = dash0.Bool(true)
+	}
+	return v
+}
+
+// Default sets the default value of a list variable. A single value is set
+// as a string, several values as a list, which requires AllowMultiple.
+func (v *VariableBuilder) Default(values ...string) *VariableBuilder {
+	if v.variable.Kind != KindListVariable {
+		return v
+	}
+	if len(values) == 1 {
+		v.variable.Spec.DefaultValue = values[0]
+	} else {
+		v.variable.Spec.DefaultValue = values
+	}
+	return v
+}
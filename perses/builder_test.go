@@ -0,0 +1,184 @@
+package perses
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/dash0hq/dash0-api-client-go"
+)
+
+func TestDashboardBuilder(t *testing.T) {
+	dashboard, err := NewDashboard("checkout").
+		ID("service-checkout").
+		Tags("service", "generated").
+		Folder("services").
+		RefreshInterval("30s").
+		Variable(LabelValuesVariable("pod", "k8s_pod_name", `{service_name="checkout"}`).DisplayName("Pod").AllowMultiple().AllowAll().Default("a", "b")).
+		Variable(TextVariable("route", "/cart").Hidden()).
+		Row("Requests",
+			TimeSeries("Request rate").Unit("requests/sec").Query(PromQL(`sum(rate(http_requests_total[5m]))`).Legend("{{route}}")),
+			TimeSeries("Request rate").Query(PromQL(`sum(rate(http_requests_total{route="$route"}[5m]))`).Datasource("prom")),
+			Stat("Error %").Unit("percent").Query(PromQL(`100 * sum(rate(errors_total[5m]))`)),
+			Stat("P99").Query(PromQL(`histogram_quantile(0.99, sum by (le) (rate(duration_bucket[5m])))`)),
+		).
+		Panels(Table("Top routes").Query(PromQL(`topk(10, sum by (route) (rate(http_requests_total[5m])))`))).
+		Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if dashboard.Kind != dash0.Dashboard || dashboard.Metadata.Name != "checkout" || dash0.StringValue(dashboard.Metadata.Dash0Extensions.Id) != "service-checkout" {
+		t.Errorf("unexpected metadata %+v", dashboard.Metadata)
+	}
+	if tags := *dashboard.Metadata.Dash0Extensions.Tags; len(tags) != 2 || dash0.StringValue(dashboard.Metadata.Annotations.Dash0ComfolderPath) != "services" {
+		t.Errorf("unexpected tags %v or folder", tags)
+	}
+
+	spec, err := ParseSpec(dashboard.Spec)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if dash0.StringValue(spec.Duration) != DefaultDuration || dash0.StringValue(spec.RefreshInterval) != "30s" {
+		t.Errorf("unexpected duration %v or refresh interval %v", spec.Duration, spec.RefreshInterval)
+	}
+
+	pod := spec.Variables[0].Spec
+	if pod.Plugin.Kind != PluginPrometheusLabelValuesVariable || !dash0.BoolValue(pod.AllowMultiple) || !dash0.BoolValue(pod.AllowAllValue) || fmt.Sprint(pod.DefaultValue) != "[a b]" || dash0.StringValue(pod.Display.Name) != "Pod" {
+		t.Errorf("unexpected list variable %+v", pod)
+	}
+	if route := spec.Variables[1]; route.Kind != KindTextVariable || !dash0.BoolValue(route.Spec.Display.Hidden) {
+		t.Errorf("unexpected text variable %+v", route)
+	}
+
+	var layout []string
+	for _, l := range spec.Layouts {
+		title := "-"
+		if l.Spec.Display != nil {
+			title = l.Spec.Display.Title
+		}
+		for _, item := range l.Spec.Items {
+			key, _ := item.Content.PanelKey()
+			layout = append(layout, fmt.Sprintf("%s/%s@%d,%d:%dx%d", title, key, item.X, item.Y, item.Width, item.Height))
+		}
+	}
+	want := "Requests/request_rate@0,0:12x6 Requests/request_rate_2@12,0:12x6 Requests/error@0,6:6x4 Requests/p99@6,6:6x4 -/top_routes@0,0:24x8"
+	if got := strings.Join(layout, " "); got != want {
+		t.Errorf("expected layout %s, got %s", want, got)
+	}
+
+	panel, err := json.Marshal(spec.Panels["request_rate"])
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantPanel := `{"kind":"Panel","spec":{"display":{"name":"Request rate"},"plugin":{"kind":"TimeSeriesChart","spec":{"yAxis":{"format":{"unit":"requests/sec"}}}},` +
+		`"queries":[{"kind":"TimeSeriesQuery","spec":{"plugin":{"kind":"PrometheusTimeSeriesQuery","spec":{"query":"sum(rate(http_requests_total[5m]))","seriesNameFormat":"{{route}}"}}}}]}}`
+	if string(panel) != wantPanel {
+		t.Errorf("expected panel\n%s\ngot\n%s", wantPanel, panel)
+	}
+	if ds := spec.Panels["request_rate_2"].Spec.Queries[0].Spec.Plugin.Spec["datasource"]; fmt.Sprint(ds) != "map[kind:PrometheusDatasource name:prom]" {
+		t.Errorf("unexpected datasource %v", ds)
+	}
+
+	t.Run("returns specs independent of the builder", func(t *testing.T) {
+		b := NewDashboard("checkout").Panels(Stat("Up").Query(PromQL("up")))
+		first, err := b.Spec()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		first.Panels["up"].Spec.Plugin.Spec["calculation"] = "mean"
+		b.Panels(Stat("Down").Query(PromQL("down")))
+
+		second, err := b.Spec()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(first.Panels) != 1 || len(first.Layouts) != 1 {
+			t.Errorf("expected the first spec to be unchanged, got %d panels and %d layouts", len(first.Panels), len(first.Layouts))
+		}
+		if len(second.Panels) != 2 || second.Panels["up"].Spec.Plugin.Spec["calculation"] != "last-number" {
+			t.Errorf("expected the builder to be unchanged, got %+v", second.Panels["up"].Spec.Plugin)
+		}
+	})
+
+	t.Run("copies reused queries", func(t *testing.T) {
+		query := PromQL("up")
+		plain := TimeSeries("Plain").Query(query)
+		query.Legend("{{pod}}")
+		legend := TimeSeries("Legend").Query(query)
+
+		if _, ok := plain.panel.Spec.Queries[0].Spec.Plugin.Spec["seriesNameFormat"]; ok {
+			t.Error("expected the first panel to keep its query")
+		}
+		if got := legend.panel.Spec.Queries[0].Spec.Plugin.Spec["seriesNameFormat"]; got != "{{pod}}" {
+			t.Errorf("unexpected legend %v", got)
+		}
+	})
+
+	t.Run("returns definitions independent of the builder", func(t *testing.T) {
+		b := NewDashboard("checkout").ID("one").Tags("a").Folder("services").Panels(Stat("Up").Query(PromQL("up")))
+		first, err := b.Build()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		b.ID("two").Folder("other")
+		(*b.metadata.Dash0Extensions.Tags)[0] = "b"
+
+		if id := dash0.StringValue(first.Metadata.Dash0Extensions.Id); id != "one" {
+			t.Errorf("expected ID one, got %s", id)
+		}
+		if folder := dash0.StringValue(first.Metadata.Annotations.Dash0ComfolderPath); folder != "services" {
+			t.Errorf("expected folder services, got %s", folder)
+		}
+		if tags := *first.Metadata.Dash0Extensions.Tags; tags[0] != "a" {
+			t.Errorf("expected tag a, got %v", tags)
+		}
+	})
+
+	t.Run("copies reused panels and variables", func(t *testing.T) {
+		panel := Stat("Up").Query(PromQL("up"))
+		variable := StaticListVariable("env", "prod", "dev")
+		b := NewDashboard("checkout").Variable(variable).Row("A", panel)
+		panel.Description("changed").Unit("bytes")
+		variable.DisplayName("Environment").AllowMultiple()
+		b.Variable(TextVariable("route", "/")).Row("B", panel)
+
+		spec, err := b.Spec()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		first := spec.Panels["up"].Spec
+		if first.Display.Description != nil || first.Plugin.Spec["format"] != nil {
+			t.Errorf("expected the first panel to be unchanged, got %+v", first)
+		}
+		if second := spec.Panels["up_2"].Spec; dash0.StringValue(second.Display.Description) != "changed" {
+			t.Errorf("expected the second panel to be changed, got %+v", second)
+		}
+		env := spec.Variables[0].Spec
+		if dash0.StringValue(env.Display.Name) != "env" || dash0.BoolValue(env.AllowMultiple) {
+			t.Errorf("expected the variable to be unchanged, got %+v", env)
+		}
+	})
+
+	t.Run("reports all invalid panels and variables", func(t *testing.T) {
+		_, err := NewDashboard("").
+			Variable(StaticListVariable("env", "prod", "dev").Default("prod", "dev")).
+			Variable(TextVariable("route", "")).
+			Variable(TextVariable("route", "")).
+			Row("Broken",
+				TimeSeries("No queries"),
+				TimeSeries("").Query(PromQL("up")),
+				Stat("Too wide").Size(30, 4).Query(PromQL("up")),
+			).
+			Build()
+		if err == nil {
+			t.Fatal("expected an error")
+		}
+		for _, want := range []string{"dashboard name is empty", `"env" has several default values`, `duplicate variable "route"`, `"No queries" has no queries`, "panel title is empty", `"Too wide": width 30`} {
+			if !strings.Contains(err.Error(), want) {
+				t.Errorf("expected %q in %v", want, err)
+			}
+		}
+	})
+}
@@ -0,0 +1,90 @@
+package perses
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"reflect"
+)
+
+// field is a known field of a JSON object to encode. Omitted fields are not
+// written, unless Extra holds a value for them.
+type field struct {
+	key   string
+	value any
+	omit  bool
+}
+
+// absentFields holds the known fields that were missing or null in a decoded
+// JSON object. They are not written while they hold their zero value, so that
+// decoding and encoding an object does not add fields to it.
+type absentFields map[string]bool
+
+// marshalObject encodes the fields and the unknown fields in extra as a JSON
+// object. Known fields take precedence over unknown fields of the same name.
+// Fields that are absent and still zero are skipped.
+func marshalObject(extra map[string]json.RawMessage, absent absentFields, fields ...field) ([]byte, error) {
+	object := make(map[string]json.RawMessage, len(extra)+len(fields))
+	maps.Copy(object, extra)
+	for _, f := range fields {
+		if f.omit || absent[f.key] && isZero(f.value) {
+			continue
+		}
+		b, err := json.Marshal(f.value)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", f.key, err)
+		}
+		object[f.key] = b
+	}
+	return json.Marshal(object)
+}
+
+// unmarshalObject decodes a JSON object into the targets of its known fields,
+// by name, and returns the remaining fields and the absent known fields.
+// Known fields that are null are kept as unknown fields, so that they are
+// written back as null.
+func unmarshalObject(data []byte, fields map[string]any) (map[string]json.RawMessage, absentFields, error) {
+	var object map[string]json.RawMessage
+	if err := json.Unmarshal(data, &object); err != nil {
+		return nil, nil, err
+	}
+	var absent absentFields
+	for key, target := range fields {
+		value, ok := object[key]
+		if !ok || bytes.Equal(bytes.TrimSpace(value), []byte("null")) {
+			if absent == nil {
+				absent = make(absentFields)
+			}
+			absent[key] = true
+			continue
+		}
+		if err := json.Unmarshal(value, target); err != nil {
+			return nil, nil, fmt.Errorf("%s: %w", key, err)
+		}
+		delete(object, key)
+	}
+	if len(object) == 0 {
+		object = nil
+	}
+	return object, absent, nil
+}
+
+func isZero(v any) bool {
+	rv := reflect.ValueOf(v)
+	return !rv.IsValid() || rv.IsZero()
+}
+
+// clone returns a deep copy of v, made by encoding it as JSON and decoding
+// the result, which the types of this package support losslessly.
+func clone[T any](v *T) (*T, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var c T
+	if err := json.Unmarshal(b, &c); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
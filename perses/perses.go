@@ -0,0 +1,580 @@
+// Package perses provides a typed model of the Perses dashboard spec used by
+// DashboardDefinition.Spec, with panels, layouts, variables, datasources and
+// queries.
+//
+// ParseSpec and DashboardSpec.Map convert between the model and the map form
+// of DashboardDefinition.Spec. Every type keeps the fields it does not model
+// in Extra and writes them back unchanged, so a spec survives a round-trip
+// even if it uses fields of newer Perses or Dash0 versions. Known fields that
+// were missing from a parsed spec are not added by Map while they keep their
+// zero value. Plugin specs, such as the PromQL query of a
+// PrometheusTimeSeriesQuery, are kept in their generic JSON form.
+//
+// NewDashboard composes new dashboards programmatically, see
+// DashboardBuilder.
+//
+// Example:
+//
+//	dashboard, err := client.GetDashboard(ctx, id, nil)
+//	// ...
+//	spec, err := perses.ParseSpec(dashboard.Spec)
+//	if err != nil {
+//	    // handle error
+//	}
+//	spec.Duration = dash0.String("1h")
+//	if dashboard.Spec, err = spec.Map(); err != nil {
+//	    // handle error
+//	}
+//	_, err = client.UpdateDashboard(ctx, id, dashboard, nil)
+package perses
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Kinds of panels, layouts, variables and queries.
+const (
+	KindPanel           = "Panel"
+	KindGrid            = "Grid"
+	KindListVariable    = "ListVariable"
+	KindTextVariable    = "TextVariable"
+	KindTimeSeriesQuery = "TimeSeriesQuery"
+)
+
+// panelRefPrefix is the prefix of references from grid items to panels.
+const panelRefPrefix = "#/spec/panels/"
+
+// ParseSpec converts the map form of DashboardDefinition.Spec into a
+// DashboardSpec.
+func ParseSpec(spec map[string]any) (*DashboardSpec, error) {
+	b, err := json.Marshal(spec)
+	if err != nil {
+		return nil, fmt.Errorf("dash0: perses: invalid dashboard spec: %w", err)
+	}
+	var s DashboardSpec
+	if err := json.Unmarshal(b, &s); err != nil {
+		return nil, fmt.Errorf("dash0: perses: invalid dashboard spec: %w", err)
+	}
+	return &s, nil
+}
+
+// Map converts the spec into the map form of DashboardDefinition.Spec.
+func (s *DashboardSpec) Map() (map[string]any, error) {
+	b, err := json.Marshal(s)
+	if err != nil {
+		return nil, fmt.Errorf("dash0: perses: invalid dashboard spec: %w", err)
+	}
+	var spec map[string]any
+	if err := json.Unmarshal(b, &spec); err != nil {
+		return nil, fmt.Errorf("dash0: perses: invalid dashboard spec: %w", err)
+	}
+	return spec, nil
+}
+
+// DashboardSpec is the spec of a Perses dashboard.
+type DashboardSpec struct {
+	Display *Display
+
+	// Datasources holds the datasources of the dashboard by name.
+	Datasources map[string]*Datasource
+
+	Variables []*Variable
+
+	// Panels holds the panels by key. Layouts place them via PanelRef.
+	Panels map[string]*Panel
+
+	Layouts []*Layout
+
+	// Duration is the default time range of the dashboard, e.g. "1h".
+	Duration *string
+
+	// RefreshInterval is the default refresh interval, e.g. "30s".
+	RefreshInterval *string
+
+	// Extra holds the fields that are not modeled, by name.
+	Extra map[string]json.RawMessage
+}
+
+func (s DashboardSpec) MarshalJSON() ([]byte, error) {
+	return marshalObject(s.Extra, nil,
+		field{"display", s.Display, s.Display == nil},
+		field{"datasources", s.Datasources, s.Datasources == nil},
+		field{"variables", s.Variables, s.Variables == nil},
+		field{"panels", s.Panels, s.Panels == nil},
+		field{"layouts", s.Layouts, s.Layouts == nil},
+		field{"duration", s.Duration, s.Duration == nil},
+		field{"refreshInterval", s.RefreshInterval, s.RefreshInterval == nil},
+	)
+}
+
+func (s *DashboardSpec) UnmarshalJSON(data []byte) (err error) {
+	s.Extra, _, err = unmarshalObject(data, map[string]any{
+		"display":         &s.Display,
+		"datasources":     &s.Datasources,
+		"variables":       &s.Variables,
+		"panels":          &s.Panels,
+		"layouts":         &s.Layouts,
+		"duration":        &s.Duration,
+		"refreshInterval": &s.RefreshInterval,
+	})
+	return err
+}
+
+// Display holds the name and description of a dashboard, panel, datasource
+// or variable.
+type Display struct {
+	Name        *string
+	Description *string
+
+	// Hidden hides a variable from the variable bar.
+	Hidden *bool
+
+	// Extra holds the fields that are not modeled, by name.
+	Extra map[string]json.RawMessage
+}
+
+func (d Display) MarshalJSON() ([]byte, error) {
+	return marshalObject(d.Extra, nil,
+		field{"name", d.Name, d.Name == nil},
+		field{"description", d.Description, d.Description == nil},
+		field{"hidden", d.Hidden, d.Hidden == nil},
+	)
+}
+
+func (d *Display) UnmarshalJSON(data []byte) (err error) {
+	d.Extra, _, err = unmarshalObject(data, map[string]any{
+		"name":        &d.Name,
+		"description": &d.Description,
+		"hidden":      &d.Hidden,
+	})
+	return err
+}
+
+// Plugin is a panel, query, variable or datasource plugin, e.g. a
+// TimeSeriesChart or a PrometheusTimeSeriesQuery. The spec depends on the
+// kind and is kept in its generic JSON form.
+type Plugin struct {
+	Kind string
+	Spec map[string]any
+
+	// Extra holds the fields that are not modeled, by name.
+	Extra map[string]json.RawMessage
+
+	absent absentFields
+}
+
+func (p Plugin) MarshalJSON() ([]byte, error) {
+	return marshalObject(p.Extra, p.absent,
+		field{"kind", p.Kind, false},
+		field{"spec", p.Spec, p.Spec == nil},
+	)
+}
+
+func (p *Plugin) UnmarshalJSON(data []byte) (err error) {
+	p.Extra, p.absent, err = unmarshalObject(data, map[string]any{
+		"kind": &p.Kind,
+		"spec": &p.Spec,
+	})
+	return err
+}
+
+// Datasource is a datasource of a dashboard.
+type Datasource struct {
+	Display *Display
+
+	// Default marks the default datasource of its plugin kind.
+	Default *bool
+
+	Plugin Plugin
+
+	// Extra holds the fields that are not modeled, by name.
+	Extra map[string]json.RawMessage
+
+	absent absentFields
+}
+
+func (d Datasource) MarshalJSON() ([]byte, error) {
+	return marshalObject(d.Extra, d.absent,
+		field{"display", d.Display, d.Display == nil},
+		field{"default", d.Default, d.Default == nil},
+		field{"plugin", d.Plugin, false},
+	)
+}
+
+func (d *Datasource) UnmarshalJSON(data []byte) (err error) {
+	d.Extra, d.absent, err = unmarshalObject(data, map[string]any{
+		"display": &d.Display,
+		"default": &d.Default,
+		"plugin":  &d.Plugin,
+	})
+	return err
+}
+
+// Variable is a dashboard variable of kind KindListVariable or
+// KindTextVariable.
+type Variable struct {
+	Kind string
+	Spec VariableSpec
+
+	// Extra holds the fields that are not modeled, by name.
+	Extra map[string]json.RawMessage
+
+	absent absentFields
+}
+
+func (v Variable) MarshalJSON() ([]byte, error) {
+	return marshalObject(v.Extra, v.absent,
+		field{"kind", v.Kind, false},
+		field{"spec", v.Spec, false},
+	)
+}
+
+func (v *Variable) UnmarshalJSON(data []byte) (err error) {
+	v.Extra, v.absent, err = unmarshalObject(data, map[string]any{
+		"kind": &v.Kind,
+		"spec": &v.Spec,
+	})
+	return err
+}
+
+// VariableSpec is the spec of a list or text variable. Fields that only apply
+// to one kind are nil for the other.
+type VariableSpec struct {
+	// Name is referenced as $name in queries.
+	Name    string
+	Display *Display
+
+	// Value and Constant apply to text variables.
+	Value    *string
+	Constant *bool
+
+	// The following fields apply to list variables, whose values are
+	// provided by the plugin, e.g. a PrometheusLabelValuesVariable.
+	// DefaultValue is a string, or a list of strings if AllowMultiple is
+	// set.
+	Plugin          *Plugin
+	DefaultValue    any
+	AllowAllValue   *bool
+	AllowMultiple   *bool
+	CustomAllValue  *string
+	CapturingRegexp *string
+	Sort            *string
+
+	// Extra holds the fields that are not modeled, by name.
+	Extra map[string]json.RawMessage
+
+	absent absentFields
+}
+
+func (s VariableSpec) MarshalJSON() ([]byte, error) {
+	return marshalObject(s.Extra, s.absent,
+		field{"name", s.Name, false},
+		field{"display", s.Display, s.Display == nil},
+		field{"value", s.Value, s.Value == nil},
+		field{"constant", s.Constant, s.Constant == nil},
+		field{"plugin", s.Plugin, s.Plugin == nil},
+		field{"defaultValue", s.DefaultValue, s.DefaultValue == nil},
+		field{"allowAllValue", s.AllowAllValue, s.AllowAllValue == nil},
+		field{"allowMultiple", s.AllowMultiple, s.AllowMultiple == nil},
+		field{"customAllValue", s.CustomAllValue, s.CustomAllValue == nil},
+		field{"capturingRegexp", s.CapturingRegexp, s.CapturingRegexp == nil},
+		field{"sort", s.Sort, s.Sort == nil},
+	)
+}
+
+func (s *VariableSpec) UnmarshalJSON(data []byte) (err error) {
+	s.Extra, s.absent, err = unmarshalObject(data, map[string]any{
+		"name":            &s.Name,
+		"display":         &s.Display,
+		"value":           &s.Value,
+		"constant":        &s.Constant,
+		"plugin":          &s.Plugin,
+		"defaultValue":    &s.DefaultValue,
+		"allowAllValue":   &s.AllowAllValue,
+		"allowMultiple":   &s.AllowMultiple,
+		"customAllValue":  &s.CustomAllValue,
+		"capturingRegexp": &s.CapturingRegexp,
+		"sort":            &s.Sort,
+	})
+	return err
+}
+
+// Panel is a panel of kind KindPanel.
+type Panel struct {
+	Kind string
+	Spec PanelSpec
+
+	// Extra holds the fields that are not modeled, by name.
+	Extra map[string]json.RawMessage
+
+	absent absentFields
+}
+
+func (p Panel) MarshalJSON() ([]byte, error) {
+	return marshalObject(p.Extra, p.absent,
+		field{"kind", p.Kind, false},
+		field{"spec", p.Spec, false},
+	)
+}
+
+func (p *Panel) UnmarshalJSON(data []byte) (err error) {
+	p.Extra, p.absent, err = unmarshalObject(data, map[string]any{
+		"kind": &p.Kind,
+		"spec": &p.Spec,
+	})
+	return err
+}
+
+// PanelSpec is the spec of a panel. The plugin defines the visualization,
+// e.g. TimeSeriesChart, StatChart or Table.
+type PanelSpec struct {
+	Display *Display
+	Plugin  Plugin
+	Queries []*Query
+
+	// Extra holds the fields that are not modeled, by name, e.g. links.
+	Extra map[string]json.RawMessage
+
+	absent absentFields
+}
+
+func (s PanelSpec) MarshalJSON() ([]byte, error) {
+	return marshalObject(s.Extra, s.absent,
+		field{"display", s.Display, s.Display == nil},
+		field{"plugin", s.Plugin, false},
+		field{"queries", s.Queries, s.Queries == nil},
+	)
+}
+
+func (s *PanelSpec) UnmarshalJSON(data []byte) (err error) {
+	s.Extra, s.absent, err = unmarshalObject(data, map[string]any{
+		"display": &s.Display,
+		"plugin":  &s.Plugin,
+		"queries": &s.Queries,
+	})
+	return err
+}
+
+// Query is a query of a panel, typically of kind KindTimeSeriesQuery.
+type Query struct {
+	Kind string
+	Spec QuerySpec
+
+	// Extra holds the fields that are not modeled, by name.
+	Extra map[string]json.RawMessage
+
+	absent absentFields
+}
+
+func (q Query) MarshalJSON() ([]byte, error) {
+	return marshalObject(q.Extra, q.absent,
+		field{"kind", q.Kind, false},
+		field{"spec", q.Spec, false},
+	)
+}
+
+func (q *Query) UnmarshalJSON(data []byte) (err error) {
+	q.Extra, q.absent, err = unmarshalObject(data, map[string]any{
+		"kind": &q.Kind,
+		"spec": &q.Spec,
+	})
+	return err
+}
+
+// QuerySpec is the spec of a query. The plugin holds the query itself, e.g.
+// a PrometheusTimeSeriesQuery with the PromQL expression.
+type QuerySpec struct {
+	Plugin Plugin
+
+	// Extra holds the fields that are not modeled, by name.
+	Extra map[string]json.RawMessage
+
+	absent absentFields
+}
+
+func (s QuerySpec) MarshalJSON() ([]byte, error) {
+	return marshalObject(s.Extra, s.absent,
+		field{"plugin", s.Plugin, false},
+	)
+}
+
+func (s *QuerySpec) UnmarshalJSON(data []byte) (err error) {
+	s.Extra, s.absent, err = unmarshalObject(data, map[string]any{
+		"plugin": &s.Plugin,
+	})
+	return err
+}
+
+// Layout arranges panels, typically of kind KindGrid.
+type Layout struct {
+	Kind string
+	Spec GridLayoutSpec
+
+	// Extra holds the fields that are not modeled, by name.
+	Extra map[string]json.RawMessage
+
+	absent absentFields
+}
+
+func (l Layout) MarshalJSON() ([]byte, error) {
+	return marshalObject(l.Extra, l.absent,
+		field{"kind", l.Kind, false},
+		field{"spec", l.Spec, false},
+	)
+}
+
+func (l *Layout) UnmarshalJSON(data []byte) (err error) {
+	l.Extra, l.absent, err = unmarshalObject(data, map[string]any{
+		"kind": &l.Kind,
+		"spec": &l.Spec,
+	})
+	return err
+}
+
+// GridLayoutSpec is the spec of a grid layout. A grid with a display is
+// shown as a row with a title that can be collapsed.
+type GridLayoutSpec struct {
+	Display *GridDisplay
+	Items   []*GridItem
+
+	// Extra holds the fields that are not modeled, by name.
+	Extra map[string]json.RawMessage
+}
+
+func (s GridLayoutSpec) MarshalJSON() ([]byte, error) {
+	return marshalObject(s.Extra, nil,
+		field{"display", s.Display, s.Display == nil},
+		field{"items", s.Items, s.Items == nil},
+	)
+}
+
+func (s *GridLayoutSpec) UnmarshalJSON(data []byte) (err error) {
+	s.Extra, _, err = unmarshalObject(data, map[string]any{
+		"display": &s.Display,
+		"items":   &s.Items,
+	})
+	return err
+}
+
+// GridDisplay is the title row of a grid layout.
+type GridDisplay struct {
+	Title    string
+	Collapse *GridCollapse
+
+	// Extra holds the fields that are not modeled, by name.
+	Extra map[string]json.RawMessage
+
+	absent absentFields
+}
+
+func (d GridDisplay) MarshalJSON() ([]byte, error) {
+	return marshalObject(d.Extra, d.absent,
+		field{"title", d.Title, false},
+		field{"collapse", d.Collapse, d.Collapse == nil},
+	)
+}
+
+func (d *GridDisplay) UnmarshalJSON(data []byte) (err error) {
+	d.Extra, d.absent, err = unmarshalObject(data, map[string]any{
+		"title":    &d.Title,
+		"collapse": &d.Collapse,
+	})
+	return err
+}
+
+// GridCollapse makes a grid layout collapsible.
+type GridCollapse struct {
+	Open bool
+
+	// Extra holds the fields that are not modeled, by name.
+	Extra map[string]json.RawMessage
+
+	absent absentFields
+}
+
+func (c GridCollapse) MarshalJSON() ([]byte, error) {
+	return marshalObject(c.Extra, c.absent,
+		field{"open", c.Open, false},
+	)
+}
+
+func (c *GridCollapse) UnmarshalJSON(data []byte) (err error) {
+	c.Extra, c.absent, err = unmarshalObject(data, map[string]any{
+		"open": &c.Open,
+	})
+	return err
+}
+
+// GridItem places a panel on the grid. The grid is 24 columns wide, and
+// positions and sizes are given in grid units.
+type GridItem struct {
+	X      int
+	Y      int
+	Width  int
+	Height int
+
+	// Content references the panel, see PanelRef.
+	Content Reference
+
+	// Extra holds the fields that are not modeled, by name.
+	Extra map[string]json.RawMessage
+
+	absent absentFields
+}
+
+func (i GridItem) MarshalJSON() ([]byte, error) {
+	return marshalObject(i.Extra, i.absent,
+		field{"x", i.X, false},
+		field{"y", i.Y, false},
+		field{"width", i.Width, false},
+		field{"height", i.Height, false},
+		field{"content", i.Content, false},
+	)
+}
+
+func (i *GridItem) UnmarshalJSON(data []byte) (err error) {
+	i.Extra, i.absent, err = unmarshalObject(data, map[string]any{
+		"x":       &i.X,
+		"y":       &i.Y,
+		"width":   &i.Width,
+		"height":  &i.Height,
+		"content": &i.Content,
+	})
+	return err
+}
+
+// Reference is a JSON reference, e.g. from a grid item to a panel.
+type Reference struct {
+	Ref string
+
+	// Extra holds the fields that are not modeled, by name.
+	Extra map[string]json.RawMessage
+
+	absent absentFields
+}
+
+// PanelRef returns a reference to the panel with the given key.
+func PanelRef(key string) Reference {
+	return Reference{Ref: panelRefPrefix + key}
+}
+
+// PanelKey returns the key of the referenced panel, or false if the
+// reference does not point to a panel.
+func (r Reference) PanelKey() (string, bool) {
+	return strings.CutPrefix(r.Ref, panelRefPrefix)
+}
+
+func (r Reference) MarshalJSON() ([]byte, error) {
+	return marshalObject(r.Extra, r.absent,
+		field{"$ref", r.Ref, false},
+	)
+}
+
+func (r *Reference) UnmarshalJSON(data []byte) (err error) {
+	r.Extra, r.absent, err = unmarshalObject(data, map[string]any{
+		"$ref": &r.Ref,
+	})
+	return err
+}
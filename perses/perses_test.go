@@ -0,0 +1,173 @@
+package perses
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/dash0hq/dash0-api-client-go"
+)
+
+const testSpec = `{
+	"display": {"name": "Checkout", "description": ""},
+	"duration": "30m",
+	"refreshInterval": null,
+	"datasources": {
+		"prometheus": {"default": true, "plugin": {"kind": "PrometheusDatasource", "spec": {"directUrl": "/api/prometheus"}}}
+	},
+	"variables": [
+		{"kind": "ListVariable", "spec": {
+			"name": "service",
+			"display": {"name": "Service", "hidden": false},
+			"allowMultiple": true,
+			"defaultValue": ["checkout", "payment"],
+			"plugin": {"kind": "PrometheusLabelValuesVariable", "spec": {"labelName": "service_name", "matchers": []}}
+		}},
+		{"kind": "TextVariable", "spec": {"name": "route", "value": "/cart", "constant": false}}
+	],
+	"panels": {
+		"requests": {"kind": "Panel", "spec": {
+			"display": {"name": "Requests"},
+			"plugin": {"kind": "TimeSeriesChart", "spec": {"legend": {"position": "bottom"}, "yAxis": {"min": 0}}},
+			"queries": [{"kind": "TimeSeriesQuery", "spec": {"plugin": {"kind": "PrometheusTimeSeriesQuery", "spec": {"query": "sum(rate(http_requests_total[5m]))", "seriesNameFormat": "{{service}}"}}}}],
+			"links": [{"url": "https://example.com", "targetBlank": true}]
+		}}
+	},
+	"layouts": [
+		{"kind": "Grid", "spec": {
+			"display": {"title": "Overview", "collapse": {"open": true}},
+			"items": [{"x": 0, "y": 0, "width": 12, "height": 6, "content": {"$ref": "#/spec/panels/requests"}, "dash0Pinned": true}]
+		}}
+	],
+	"dash0Extensions": {"theme": "dark"}
+}`
+
+func testSpecMap(t *testing.T) map[string]any {
+	t.Helper()
+	var spec map[string]any
+	if err := json.Unmarshal([]byte(testSpec), &spec); err != nil {
+		t.Fatal(err)
+	}
+	return spec
+}
+
+func TestParseSpec(t *testing.T) {
+	spec, err := ParseSpec(testSpecMap(t))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if dash0.StringValue(spec.Display.Name) != "Checkout" || dash0.StringValue(spec.Duration) != "30m" || spec.RefreshInterval != nil {
+		t.Errorf("unexpected dashboard fields %+v", spec)
+	}
+	if ds := spec.Datasources["prometheus"]; ds == nil || !dash0.BoolValue(ds.Default) || ds.Plugin.Kind != "PrometheusDatasource" {
+		t.Errorf("unexpected datasource %+v", ds)
+	}
+	if v := spec.Variables[0]; v.Kind != KindListVariable || v.Spec.Name != "service" || !dash0.BoolValue(v.Spec.AllowMultiple) || v.Spec.Plugin.Kind != "PrometheusLabelValuesVariable" {
+		t.Errorf("unexpected list variable %+v", v)
+	}
+	if v := spec.Variables[1]; v.Kind != KindTextVariable || dash0.StringValue(v.Spec.Value) != "/cart" || v.Spec.Plugin != nil {
+		t.Errorf("unexpected text variable %+v", v)
+	}
+
+	panel := spec.Panels["requests"]
+	if panel.Kind != KindPanel || panel.Spec.Plugin.Kind != "TimeSeriesChart" || len(panel.Spec.Queries) != 1 {
+		t.Fatalf("unexpected panel %+v", panel)
+	}
+	if query := panel.Spec.Queries[0].Spec.Plugin.Spec["query"]; query != "sum(rate(http_requests_total[5m]))" {
+		t.Errorf("unexpected query %v", query)
+	}
+	if _, ok := panel.Spec.Extra["links"]; !ok {
+		t.Error("expected links to be kept as unknown field")
+	}
+
+	grid := spec.Layouts[0]
+	if grid.Kind != KindGrid || grid.Spec.Display.Title != "Overview" || !grid.Spec.Display.Collapse.Open {
+		t.Errorf("unexpected layout %+v", grid)
+	}
+	item := grid.Spec.Items[0]
+	if key, ok := item.Content.PanelKey(); !ok || key != "requests" || item.Width != 12 || item.Height != 6 {
+		t.Errorf("unexpected grid item %+v", item)
+	}
+
+	t.Run("rejects invalid specs", func(t *testing.T) {
+		_, err := ParseSpec(map[string]any{"layouts": []any{map[string]any{"spec": map[string]any{"items": "none"}}}})
+		if err == nil || !strings.Contains(err.Error(), "items") {
+			t.Errorf("expected error naming the field, got %v", err)
+		}
+	})
+}
+
+func TestDashboardSpec_Map(t *testing.T) {
+	t.Run("round-trips losslessly", func(t *testing.T) {
+		original := testSpecMap(t)
+		spec, err := ParseSpec(original)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		result, err := spec.Map()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !reflect.DeepEqual(result, original) {
+			got, _ := json.MarshalIndent(result, "", "  ")
+			t.Errorf("expected spec to survive the round-trip, got %s", got)
+		}
+	})
+
+	t.Run("round-trips partial specs losslessly", func(t *testing.T) {
+		for _, partial := range []string{
+			`{}`,
+			`{"panels": {"empty": {}}}`,
+			`{"panels": {"a": {"kind": "Panel", "spec": {"display": {"name": "A"}}}}}`,
+			`{"panels": {"a": {"spec": {"plugin": {"spec": {}}, "queries": [{"spec": {}}, {"kind": "TimeSeriesQuery"}]}}}}`,
+			`{"panels": {"a": {"spec": {"plugin": null}}}}`,
+			`{"datasources": {"prom": {"default": true}}}`,
+			`{"variables": [{"spec": {"display": {"hidden": true}}}, {"kind": "TextVariable"}]}`,
+			`{"layouts": [{"spec": {"display": {}, "items": [{"x": 0}, {"width": 6, "content": {}}]}}]}`,
+			`{"layouts": [{"kind": "Grid", "spec": {"display": {"title": "Row", "collapse": {}}}}]}`,
+		} {
+			var original map[string]any
+			if err := json.Unmarshal([]byte(partial), &original); err != nil {
+				t.Fatalf("invalid test spec %s: %v", partial, err)
+			}
+			spec, err := ParseSpec(original)
+			if err != nil {
+				t.Fatalf("unexpected error for %s: %v", partial, err)
+			}
+			result, err := spec.Map()
+			if err != nil {
+				t.Fatalf("unexpected error for %s: %v", partial, err)
+			}
+			if !reflect.DeepEqual(result, original) {
+				got, _ := json.Marshal(result)
+				t.Errorf("expected %s to survive the round-trip, got %s", partial, got)
+			}
+		}
+	})
+
+	t.Run("keeps unknown fields of modified specs", func(t *testing.T) {
+		spec, _ := ParseSpec(testSpecMap(t))
+		spec.RefreshInterval = dash0.String("1m")
+		spec.Layouts[0].Spec.Items[0].Width = 24
+		spec.Panels["errors"] = &Panel{Kind: KindPanel, Spec: PanelSpec{Plugin: Plugin{Kind: "StatChart"}}}
+
+		result, err := spec.Map()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		b, _ := json.Marshal(result)
+		for _, want := range []string{
+			`"refreshInterval":"1m"`,
+			`"dash0Extensions":{"theme":"dark"}`,
+			`"dash0Pinned":true`,
+			`"width":24`,
+			`"errors":{"kind":"Panel","spec":{"plugin":{"kind":"StatChart"}}}`,
+		} {
+			if !strings.Contains(string(b), want) {
+				t.Errorf("expected %s in %s", want, b)
+			}
+		}
+	})
+}
@@ -0,0 +1,35 @@
+package reconcile
+
+import (
+	"fmt"
+
+	"github.com/dash0hq/dash0-api-client-go"
+	"github.com/dash0hq/dash0-api-client-go/diff"
+)
+
+// compare returns the field-level changes that turn the current definition into
+// the desired one. Server-managed fields are ignored.
+func compare(current, desired any) ([]diff.Change, error) {
+	switch d := desired.(type) {
+	case *dash0.DashboardDefinition:
+		return compareAs(current, d)
+	case *dash0.PrometheusAlertRule:
+		return compareAs(current, d)
+	case *dash0.SyntheticCheckDefinition:
+		return compareAs(current, d)
+	case *dash0.ViewDefinition:
+		return compareAs(current, d)
+	case *dash0.SamplingDefinition:
+		return compareAs(current, d)
+	default:
+		return nil, fmt.Errorf("unexpected definition type %T", desired)
+	}
+}
+
+func compareAs[T diff.Definition](current any, desired *T) ([]diff.Change, error) {
+	c, ok := current.(*T)
+	if !ok {
+		return nil, fmt.Errorf("unexpected definition type %T", current)
+	}
+	return diff.Compare(c, desired)
+}
@@ -0,0 +1,284 @@
+package reconcile
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/dash0hq/dash0-api-client-go"
+)
+
+// desiredAsset is an asset from the desired state together with its identity.
+type desiredAsset struct {
+	id         string
+	name       string
+	definition any
+}
+
+// existingAsset is an asset as returned by a List call.
+type existingAsset struct {
+	id     string
+	origin string
+	name   string
+
+	// definition is set when the List call already returns full definitions,
+	// which saves a Get call during planning.
+	definition any
+}
+
+// desiredKind groups the desired assets of a single kind.
+type desiredKind struct {
+	kind   Kind
+	assets []desiredAsset
+}
+
+// handler binds the client calls for a single asset kind. There is no create
+// call: the Update calls upsert assets by their ID, whereas the Create calls
+// assign new IDs, which would not match the desired assets in the next plan.
+type handler struct {
+	kind   Kind
+	list   func(ctx context.Context, c dash0.Client, dataset *string) ([]*existingAsset, error)
+	get    func(ctx context.Context, c dash0.Client, id string, dataset *string) (any, error)
+	update func(ctx context.Context, c dash0.Client, id string, definition any, dataset *string) error
+	delete func(ctx context.Context, c dash0.Client, id string, dataset *string) error
+}
+
+// kinds returns the managed kinds of the desired state in a stable order.
+// It returns an error for nil definitions.
+func (s *DesiredState) kinds() ([]desiredKind, error) {
+	var kinds []desiredKind
+	if s.Dashboards != nil {
+		assets := make([]desiredAsset, len(s.Dashboards))
+		for i, d := range s.Dashboards {
+			if d == nil {
+				return nil, nilDefinitionError(KindDashboard, i)
+			}
+			assets[i] = desiredAsset{id: dashboardID(d), name: d.Metadata.Name, definition: d}
+		}
+		kinds = append(kinds, desiredKind{kind: KindDashboard, assets: assets})
+	}
+	if s.CheckRules != nil {
+		assets := make([]desiredAsset, len(s.CheckRules))
+		for i, r := range s.CheckRules {
+			if r == nil {
+				return nil, nilDefinitionError(KindCheckRule, i)
+			}
+			assets[i] = desiredAsset{id: dash0.StringValue(r.Id), name: r.Name, definition: r}
+		}
+		kinds = append(kinds, desiredKind{kind: KindCheckRule, assets: assets})
+	}
+	if s.SyntheticChecks != nil {
+		assets := make([]desiredAsset, len(s.SyntheticChecks))
+		for i, c := range s.SyntheticChecks {
+			if c == nil {
+				return nil, nilDefinitionError(KindSyntheticCheck, i)
+			}
+			var id string
+			if c.Metadata.Labels != nil {
+				id = dash0.StringValue(c.Metadata.Labels.Dash0Comid)
+			}
+			assets[i] = desiredAsset{id: id, name: c.Metadata.Name, definition: c}
+		}
+		kinds = append(kinds, desiredKind{kind: KindSyntheticCheck, assets: assets})
+	}
+	if s.Views != nil {
+		assets := make([]desiredAsset, len(s.Views))
+		for i, v := range s.Views {
+			if v == nil {
+				return nil, nilDefinitionError(KindView, i)
+			}
+			var id string
+			if v.Metadata.Labels != nil {
+				id = dash0.StringValue(v.Metadata.Labels.Dash0Comid)
+			}
+			assets[i] = desiredAsset{id: id, name: v.Metadata.Name, definition: v}
+		}
+		kinds = append(kinds, desiredKind{kind: KindView, assets: assets})
+	}
+	if s.SamplingRules != nil {
+		assets := make([]desiredAsset, len(s.SamplingRules))
+		for i, r := range s.SamplingRules {
+			if r == nil {
+				return nil, nilDefinitionError(KindSamplingRule, i)
+			}
+			assets[i] = desiredAsset{id: samplingRuleID(r), name: r.Metadata.Name, definition: r}
+		}
+		kinds = append(kinds, desiredKind{kind: KindSamplingRule, assets: assets})
+	}
+	return kinds, nil
+}
+
+func nilDefinitionError(kind Kind, index int) error {
+	return fmt.Errorf("dash0: reconcile: %s %d is nil", kind, index)
+}
+
+func dashboardID(d *dash0.DashboardDefinition) string {
+	if d.Metadata.Dash0Extensions == nil {
+		return ""
+	}
+	return dash0.StringValue(d.Metadata.Dash0Extensions.Id)
+}
+
+func samplingRuleID(r *dash0.SamplingDefinition) string {
+	if r.Metadata.Labels == nil {
+		return ""
+	}
+	return dash0.StringValue(r.Metadata.Labels.Dash0Comid)
+}
+
+// handlers holds the client bindings for every supported kind.
+var handlers = map[Kind]*handler{
+	KindDashboard: {
+		kind: KindDashboard,
+		list: func(ctx context.Context, c dash0.Client, dataset *string) ([]*existingAsset, error) {
+			items, err := c.ListDashboards(ctx, dataset)
+			if err != nil {
+				return nil, err
+			}
+			result := make([]*existingAsset, len(items))
+			for i, item := range items {
+				result[i] = &existingAsset{id: item.Id, origin: dash0.StringValue(item.Origin), name: dash0.StringValue(item.Name)}
+			}
+			return result, nil
+		},
+		get: func(ctx context.Context, c dash0.Client, id string, dataset *string) (any, error) {
+			return c.GetDashboard(ctx, id, dataset)
+		},
+		update: func(ctx context.Context, c dash0.Client, id string, definition any, dataset *string) error {
+			def, err := as[dash0.DashboardDefinition](definition)
+			if err != nil {
+				return err
+			}
+			_, err = c.UpdateDashboard(ctx, id, def, dataset)
+			return err
+		},
+		delete: func(ctx context.Context, c dash0.Client, id string, dataset *string) error {
+			return c.DeleteDashboard(ctx, id, dataset)
+		},
+	},
+	KindCheckRule: {
+		kind: KindCheckRule,
+		list: func(ctx context.Context, c dash0.Client, dataset *string) ([]*existingAsset, error) {
+			items, err := c.ListCheckRules(ctx, dataset)
+			if err != nil {
+				return nil, err
+			}
+			result := make([]*existingAsset, len(items))
+			for i, item := range items {
+				result[i] = &existingAsset{id: item.Id, origin: dash0.StringValue(item.Origin), name: dash0.StringValue(item.Name)}
+			}
+			return result, nil
+		},
+		get: func(ctx context.Context, c dash0.Client, id string, dataset *string) (any, error) {
+			return c.GetCheckRule(ctx, id, dataset)
+		},
+		update: func(ctx context.Context, c dash0.Client, id string, definition any, dataset *string) error {
+			def, err := as[dash0.PrometheusAlertRule](definition)
+			if err != nil {
+				return err
+			}
+			_, err = c.UpdateCheckRule(ctx, id, def, dataset)
+			return err
+		},
+		delete: func(ctx context.Context, c dash0.Client, id string, dataset *string) error {
+			return c.DeleteCheckRule(ctx, id, dataset)
+		},
+	},
+	KindSyntheticCheck: {
+		kind: KindSyntheticCheck,
+		list: func(ctx context.Context, c dash0.Client, dataset *string) ([]*existingAsset, error) {
+			items, err := c.ListSyntheticChecks(ctx, dataset)
+			if err != nil {
+				return nil, err
+			}
+			result := make([]*existingAsset, len(items))
+			for i, item := range items {
+				result[i] = &existingAsset{id: item.Id, origin: dash0.StringValue(item.Origin), name: dash0.StringValue(item.Name)}
+			}
+			return result, nil
+		},
+		get: func(ctx context.Context, c dash0.Client, id string, dataset *string) (any, error) {
+			return c.GetSyntheticCheck(ctx, id, dataset)
+		},
+		update: func(ctx context.Context, c dash0.Client, id string, definition any, dataset *string) error {
+			def, err := as[dash0.SyntheticCheckDefinition](definition)
+			if err != nil {
+				return err
+			}
+			_, err = c.UpdateSyntheticCheck(ctx, id, def, dataset)
+			return err
+		},
+		delete: func(ctx context.Context, c dash0.Client, id string, dataset *string) error {
+			return c.DeleteSyntheticCheck(ctx, id, dataset)
+		},
+	},
+	KindView: {
+		kind: KindView,
+		list: func(ctx context.Context, c dash0.Client, dataset *string) ([]*existingAsset, error) {
+			items, err := c.ListViews(ctx, dataset)
+			if err != nil {
+				return nil, err
+			}
+			result := make([]*existingAsset, len(items))
+			for i, item := range items {
+				result[i] = &existingAsset{id: item.Id, origin: dash0.StringValue(item.Origin), name: dash0.StringValue(item.Name)}
+			}
+			return result, nil
+		},
+		get: func(ctx context.Context, c dash0.Client, id string, dataset *string) (any, error) {
+			return c.GetView(ctx, id, dataset)
+		},
+		update: func(ctx context.Context, c dash0.Client, id string, definition any, dataset *string) error {
+			def, err := as[dash0.ViewDefinition](definition)
+			if err != nil {
+				return err
+			}
+			_, err = c.UpdateView(ctx, id, def, dataset)
+			return err
+		},
+		delete: func(ctx context.Context, c dash0.Client, id string, dataset *string) error {
+			return c.DeleteView(ctx, id, dataset)
+		},
+	},
+	KindSamplingRule: {
+		kind: KindSamplingRule,
+		list: func(ctx context.Context, c dash0.Client, dataset *string) ([]*existingAsset, error) {
+			// Sampling rules are listed with their full definitions.
+			items, err := c.ListSamplingRules(ctx, dataset)
+			if err != nil {
+				return nil, err
+			}
+			result := make([]*existingAsset, len(items))
+			for i, item := range items {
+				var origin string
+				if item.Metadata.Labels != nil {
+					origin = dash0.StringValue(item.Metadata.Labels.Dash0Comorigin)
+				}
+				result[i] = &existingAsset{id: samplingRuleID(item), origin: origin, name: item.Metadata.Name, definition: item}
+			}
+			return result, nil
+		},
+		get: func(ctx context.Context, c dash0.Client, id string, dataset *string) (any, error) {
+			return c.GetSamplingRule(ctx, id, dataset)
+		},
+		update: func(ctx context.Context, c dash0.Client, id string, definition any, dataset *string) error {
+			def, err := as[dash0.SamplingDefinition](definition)
+			if err != nil {
+				return err
+			}
+			_, err = c.UpdateSamplingRule(ctx, id, def, dataset)
+			return err
+		},
+		delete: func(ctx context.Context, c dash0.Client, id string, dataset *string) error {
+			return c.DeleteSamplingRule(ctx, id, dataset)
+		},
+	},
+}
+
+// as converts a definition to the concrete type expected by a handler.
+func as[T any](definition any) (*T, error) {
+	def, ok := definition.(*T)
+	if !ok || def == nil {
+		return nil, fmt.Errorf("unexpected definition type %T", definition)
+	}
+	return def, nil
+}
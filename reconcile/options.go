@@ -0,0 +1,45 @@
+package reconcile
+
+import "github.com/dash0hq/dash0-api-client-go"
+
+// Option configures a Reconciler.
+type Option func(*config)
+
+type config struct {
+	dataset     *string
+	prune       bool
+	concurrency int
+}
+
+func defaultConfig() *config {
+	return &config{
+		concurrency: dash0.DefaultMaxConcurrentRequests,
+	}
+}
+
+// WithDataset sets the dataset that assets are reconciled in.
+// Default is the organization's default dataset.
+func WithDataset(dataset string) Option {
+	return func(c *config) {
+		c.dataset = &dataset
+	}
+}
+
+// WithPrune enables deletion of existing assets that are not part of the desired state.
+// Pruning only applies to kinds that are managed by the desired state.
+// Default is false.
+func WithPrune(prune bool) Option {
+	return func(c *config) {
+		c.prune = prune
+	}
+}
+
+// WithConcurrency sets the maximum number of API calls issued concurrently while
+// planning and applying. Note that the client's own concurrency limit
+// (see dash0.WithMaxConcurrentRequests) still applies.
+// Default is dash0.DefaultMaxConcurrentRequests.
+func WithConcurrency(n int) Option {
+	return func(c *config) {
+		c.concurrency = n
+	}
+}
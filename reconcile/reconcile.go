@@ -0,0 +1,326 @@
+// Package reconcile reconciles Dash0 assets against a declarative desired state.
+//
+// A Reconciler compares desired dashboards, check rules, synthetic checks, views
+// and sampling rules with what the Dash0 API currently holds, computes a Plan of
+// create, update, delete and no-op changes, and applies that plan with bounded
+// concurrency. Assets are created and updated with the PUT endpoints of the API,
+// which upsert them by their ID, so that applying a plan converges: planning
+// the same desired state again yields only no-ops.
+//
+// The package implements what was proposed as a sync package. It is named
+// reconcile because it plans and applies changes in one direction, towards the
+// desired state, and to avoid shadowing the standard library's sync package.
+//
+// Example:
+//
+//	r := reconcile.NewReconciler(client, reconcile.WithDataset("default"), reconcile.WithPrune(true))
+//	plan, err := r.Plan(ctx, &reconcile.DesiredState{
+//	    Dashboards: dashboards,
+//	    CheckRules: checkRules,
+//	})
+//	if err != nil {
+//	    // handle error
+//	}
+//	if err := r.Apply(ctx, plan); err != nil {
+//	    // handle error
+//	}
+package reconcile
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/dash0hq/dash0-api-client-go"
+	"github.com/dash0hq/dash0-api-client-go/diff"
+	"golang.org/x/sync/errgroup"
+)
+
+// Kind identifies the type of asset a Change applies to.
+type Kind string
+
+const (
+	KindDashboard      Kind = "dashboard"
+	KindCheckRule      Kind = "check rule"
+	KindSyntheticCheck Kind = "synthetic check"
+	KindView           Kind = "view"
+	KindSamplingRule   Kind = "sampling rule"
+)
+
+// Action is the operation a Change performs.
+type Action string
+
+const (
+	ActionCreate Action = "create"
+	ActionUpdate Action = "update"
+	ActionDelete Action = "delete"
+	ActionNoop   Action = "no-op"
+)
+
+// DesiredState is the set of assets that should exist in Dash0.
+//
+// Only kinds with a non-nil slice are reconciled. A nil slice leaves that kind
+// untouched, while an empty, non-nil slice declares that no assets of that kind
+// should exist (which deletes all of them when pruning is enabled).
+//
+// Every asset must carry an ID that is used to match it against existing assets:
+//   - DashboardDefinition: Metadata.Dash0Extensions.Id
+//   - PrometheusAlertRule: Id
+//   - SyntheticCheckDefinition: Metadata.Labels.Dash0Comid
+//   - ViewDefinition: Metadata.Labels.Dash0Comid
+//   - SamplingDefinition: Metadata.Labels.Dash0Comid
+type DesiredState struct {
+	Dashboards      []*dash0.DashboardDefinition
+	CheckRules      []*dash0.PrometheusAlertRule
+	SyntheticChecks []*dash0.SyntheticCheckDefinition
+	Views           []*dash0.ViewDefinition
+	SamplingRules   []*dash0.SamplingDefinition
+}
+
+// Change is a single planned operation on an asset.
+type Change struct {
+	// Kind is the type of the asset.
+	Kind Kind
+
+	// Action is the operation to perform.
+	Action Action
+
+	// ID is the origin or ID used to address the asset in the API.
+	ID string
+
+	// Name is the human-readable name of the asset, if known.
+	Name string
+
+	// Desired is the desired definition, e.g. *dash0.DashboardDefinition.
+	// It is nil for deletions.
+	Desired any
+
+	// Current is the definition currently stored in Dash0.
+	// It is nil for creations and deletions.
+	Current any
+
+	// Diff holds the field-level changes of an update, ignoring server-managed
+	// fields. It is empty for all other actions.
+	Diff []diff.Change
+}
+
+// String returns a short description of the change, e.g. "create dashboard my-dashboard".
+func (c Change) String() string {
+	return fmt.Sprintf("%s %s %s", c.Action, c.Kind, c.ID)
+}
+
+// Plan is the ordered list of changes required to reach the desired state.
+type Plan struct {
+	Changes []Change
+}
+
+// HasChanges returns true if applying the plan would modify any asset.
+func (p *Plan) HasChanges() bool {
+	for _, c := range p.Changes {
+		if c.Action != ActionNoop {
+			return true
+		}
+	}
+	return false
+}
+
+// Count returns the number of changes with the given action.
+func (p *Plan) Count(action Action) int {
+	n := 0
+	for _, c := range p.Changes {
+		if c.Action == action {
+			n++
+		}
+	}
+	return n
+}
+
+// ChangeError is returned by Apply for each change that failed.
+type ChangeError struct {
+	Change Change
+	Err    error
+}
+
+// Error implements the error interface.
+func (e *ChangeError) Error() string {
+	return fmt.Sprintf("dash0: reconcile: %s failed: %v", e.Change, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *ChangeError) Unwrap() error {
+	return e.Err
+}
+
+// Reconciler plans and applies changes against the Dash0 API.
+// Use NewReconciler to create one.
+type Reconciler struct {
+	client dash0.Client
+	config *config
+}
+
+// NewReconciler creates a new Reconciler that uses the given client.
+func NewReconciler(client dash0.Client, opts ...Option) *Reconciler {
+	cfg := defaultConfig()
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	if cfg.concurrency < 1 {
+		cfg.concurrency = 1
+	}
+	return &Reconciler{
+		client: client,
+		config: cfg,
+	}
+}
+
+// Plan computes the changes required to reach the desired state.
+// It only reads from the API; nothing is modified until Apply is called.
+func (r *Reconciler) Plan(ctx context.Context, desired *DesiredState) (*Plan, error) {
+	if desired == nil {
+		return &Plan{}, nil
+	}
+
+	kinds, err := desired.kinds()
+	if err != nil {
+		return nil, err
+	}
+	var changes []Change
+	for _, k := range kinds {
+		kindChanges, err := r.planKind(ctx, handlers[k.kind], k.assets)
+		if err != nil {
+			return nil, err
+		}
+		changes = append(changes, kindChanges...)
+	}
+	return &Plan{Changes: changes}, nil
+}
+
+// Apply executes all changes of the plan that are not no-ops.
+// Changes are applied concurrently, bounded by WithConcurrency. A failing change
+// does not stop the remaining ones; all failures are returned together as
+// *ChangeError values joined with errors.Join.
+func (r *Reconciler) Apply(ctx context.Context, plan *Plan) error {
+	if plan == nil {
+		return nil
+	}
+
+	errs := make([]error, len(plan.Changes))
+	var g errgroup.Group
+	g.SetLimit(r.config.concurrency)
+	for i, change := range plan.Changes {
+		if change.Action == ActionNoop {
+			continue
+		}
+		g.Go(func() error {
+			if err := r.apply(ctx, change); err != nil {
+				errs[i] = &ChangeError{Change: change, Err: err}
+			}
+			return nil
+		})
+	}
+	_ = g.Wait()
+	return errors.Join(errs...)
+}
+
+// planKind computes the changes for a single asset kind.
+func (r *Reconciler) planKind(ctx context.Context, h *handler, desired []desiredAsset) ([]Change, error) {
+	seen := make(map[string]bool, len(desired))
+	for _, d := range desired {
+		if d.id == "" {
+			return nil, fmt.Errorf("dash0: reconcile: %s %q has no ID", h.kind, d.name)
+		}
+		if seen[d.id] {
+			return nil, fmt.Errorf("dash0: reconcile: duplicate %s ID %q", h.kind, d.id)
+		}
+		seen[d.id] = true
+	}
+
+	items, err := h.list(ctx, r.client, r.config.dataset)
+	if err != nil {
+		return nil, fmt.Errorf("dash0: reconcile: list %ss failed: %w", h.kind, err)
+	}
+
+	// Existing assets are addressable by origin as well as by ID.
+	byKey := make(map[string]*existingAsset, 2*len(items))
+	for _, item := range items {
+		if item.origin != "" {
+			byKey[item.origin] = item
+		}
+		if item.id != "" {
+			byKey[item.id] = item
+		}
+	}
+
+	changes := make([]Change, len(desired))
+	matched := make(map[*existingAsset]bool, len(items))
+	var g errgroup.Group
+	g.SetLimit(r.config.concurrency)
+	for i, d := range desired {
+		item, ok := byKey[d.id]
+		if !ok {
+			changes[i] = Change{Kind: h.kind, Action: ActionCreate, ID: d.id, Name: d.name, Desired: d.definition}
+			continue
+		}
+		matched[item] = true
+		g.Go(func() error {
+			current := item.definition
+			if current == nil {
+				var err error
+				current, err = h.get(ctx, r.client, d.id, r.config.dataset)
+				if err != nil {
+					return fmt.Errorf("dash0: reconcile: get %s %q failed: %w", h.kind, d.id, err)
+				}
+			}
+			fieldChanges, err := compare(current, d.definition)
+			if err != nil {
+				return fmt.Errorf("dash0: reconcile: compare %s %q failed: %w", h.kind, d.id, err)
+			}
+			action := ActionUpdate
+			if len(fieldChanges) == 0 {
+				action = ActionNoop
+			}
+			changes[i] = Change{Kind: h.kind, Action: action, ID: d.id, Name: d.name, Desired: d.definition, Current: current, Diff: fieldChanges}
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	if r.config.prune {
+		var deletes []Change
+		for _, item := range items {
+			if matched[item] {
+				continue
+			}
+			id := item.origin
+			if id == "" {
+				id = item.id
+			}
+			deletes = append(deletes, Change{Kind: h.kind, Action: ActionDelete, ID: id, Name: item.name})
+		}
+		sort.Slice(deletes, func(i, j int) bool { return deletes[i].ID < deletes[j].ID })
+		changes = append(changes, deletes...)
+	}
+
+	return changes, nil
+}
+
+// apply executes a single change.
+func (r *Reconciler) apply(ctx context.Context, change Change) error {
+	h, ok := handlers[change.Kind]
+	if !ok {
+		return fmt.Errorf("unknown kind %q", change.Kind)
+	}
+	switch change.Action {
+	case ActionCreate, ActionUpdate:
+		return h.update(ctx, r.client, change.ID, change.Desired, r.config.dataset)
+	case ActionDelete:
+		return h.delete(ctx, r.client, change.ID, r.config.dataset)
+	case ActionNoop:
+		return nil
+	default:
+		return fmt.Errorf("unknown action %q", change.Action)
+	}
+}
@@ -0,0 +1,303 @@
+package reconcile
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/dash0hq/dash0-api-client-go"
+	"github.com/dash0hq/dash0-api-client-go/dash0test"
+)
+
+func dashboard(id, name string) *dash0.DashboardDefinition {
+	return &dash0.DashboardDefinition{
+		Kind: dash0.Dashboard,
+		Metadata: dash0.DashboardMetadata{
+			Name:            name,
+			Dash0Extensions: &dash0.DashboardMetadataExtensions{Id: dash0.Ptr(id)},
+		},
+		Spec: map[string]interface{}{"duration": "1h"},
+	}
+}
+
+func TestReconciler_Plan(t *testing.T) {
+	t.Run("computes create, update, no-op and delete", func(t *testing.T) {
+		now := time.Now()
+		mock := &dash0test.MockClient{
+			ListDashboardsFunc: func(ctx context.Context, dataset *string) ([]*dash0.DashboardApiListItem, error) {
+				return []*dash0.DashboardApiListItem{
+					{Id: "uuid-1", Origin: dash0.Ptr("unchanged")},
+					{Id: "uuid-2", Origin: dash0.Ptr("changed")},
+					{Id: "uuid-3", Origin: dash0.Ptr("obsolete")},
+				}, nil
+			},
+			GetDashboardFunc: func(ctx context.Context, originOrID string, dataset *string) (*dash0.DashboardDefinition, error) {
+				d := dashboard(originOrID, originOrID)
+				// Server-managed fields must not cause updates
+				d.Metadata.CreatedAt = &now
+				d.Metadata.Version = dash0.Int64(7)
+				if originOrID == "changed" {
+					d.Spec["duration"] = "30m"
+				}
+				return d, nil
+			},
+		}
+
+		r := NewReconciler(mock, WithPrune(true))
+		plan, err := r.Plan(context.Background(), &DesiredState{
+			Dashboards: []*dash0.DashboardDefinition{
+				dashboard("unchanged", "unchanged"),
+				dashboard("changed", "changed"),
+				dashboard("new", "new"),
+			},
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		want := []struct {
+			action Action
+			id     string
+		}{
+			{ActionNoop, "unchanged"},
+			{ActionUpdate, "changed"},
+			{ActionCreate, "new"},
+			{ActionDelete, "obsolete"},
+		}
+		if len(plan.Changes) != len(want) {
+			t.Fatalf("expected %d changes, got %d: %v", len(want), len(plan.Changes), plan.Changes)
+		}
+		for i, w := range want {
+			c := plan.Changes[i]
+			if c.Action != w.action || c.ID != w.id || c.Kind != KindDashboard {
+				t.Errorf("change %d: expected %s dashboard %s, got %s", i, w.action, w.id, c)
+			}
+		}
+		if !plan.HasChanges() {
+			t.Error("expected plan to have changes")
+		}
+		if d := plan.Changes[1].Diff; len(d) != 1 || d[0].Path != "/spec/duration" {
+			t.Errorf("expected diff of /spec/duration, got %v", d)
+		}
+	})
+
+	t.Run("does not delete without prune", func(t *testing.T) {
+		mock := &dash0test.MockClient{
+			ListCheckRulesFunc: func(ctx context.Context, dataset *string) ([]*dash0.PrometheusAlertRuleApiListItem, error) {
+				return []*dash0.PrometheusAlertRuleApiListItem{{Id: "obsolete"}}, nil
+			},
+		}
+
+		plan, err := NewReconciler(mock).Plan(context.Background(), &DesiredState{
+			CheckRules: []*dash0.PrometheusAlertRule{},
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if plan.HasChanges() {
+			t.Errorf("expected no changes, got %v", plan.Changes)
+		}
+	})
+
+	t.Run("skips kinds that are not managed", func(t *testing.T) {
+		mock := &dash0test.MockClient{
+			ListViewsFunc: func(ctx context.Context, dataset *string) ([]*dash0.ViewApiListItem, error) {
+				t.Error("views should not be listed")
+				return nil, nil
+			},
+		}
+
+		plan, err := NewReconciler(mock, WithPrune(true)).Plan(context.Background(), &DesiredState{})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(plan.Changes) != 0 {
+			t.Errorf("expected empty plan, got %v", plan.Changes)
+		}
+	})
+
+	t.Run("uses listed sampling rules without get", func(t *testing.T) {
+		rule := &dash0.SamplingDefinition{
+			Kind: dash0.Dash0Sampling,
+			Metadata: dash0.SamplingMetadata{
+				Name:   "errors",
+				Labels: &dash0.SamplingLabels{Dash0Comid: dash0.Ptr("errors")},
+			},
+		}
+		mock := &dash0test.MockClient{
+			ListSamplingRulesFunc: func(ctx context.Context, dataset *string) ([]*dash0.SamplingDefinition, error) {
+				return []*dash0.SamplingDefinition{rule}, nil
+			},
+			GetSamplingRuleFunc: func(ctx context.Context, originOrID string, dataset *string) (*dash0.SamplingDefinition, error) {
+				t.Error("sampling rule should not be fetched")
+				return nil, nil
+			},
+		}
+
+		plan, err := NewReconciler(mock).Plan(context.Background(), &DesiredState{
+			SamplingRules: []*dash0.SamplingDefinition{rule},
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if plan.Count(ActionNoop) != 1 {
+			t.Errorf("expected 1 no-op, got %v", plan.Changes)
+		}
+	})
+
+	t.Run("rejects assets without ID", func(t *testing.T) {
+		_, err := NewReconciler(&dash0test.MockClient{}).Plan(context.Background(), &DesiredState{
+			Views: []*dash0.ViewDefinition{{Metadata: dash0.ViewMetadata{Name: "no id"}}},
+		})
+		if err == nil {
+			t.Fatal("expected error for missing ID")
+		}
+	})
+
+	t.Run("rejects duplicate IDs", func(t *testing.T) {
+		_, err := NewReconciler(&dash0test.MockClient{}).Plan(context.Background(), &DesiredState{
+			Dashboards: []*dash0.DashboardDefinition{dashboard("a", "a"), dashboard("a", "b")},
+		})
+		if err == nil {
+			t.Fatal("expected error for duplicate ID")
+		}
+	})
+
+	t.Run("rejects nil definitions", func(t *testing.T) {
+		_, err := NewReconciler(&dash0test.MockClient{}).Plan(context.Background(), &DesiredState{
+			Views: []*dash0.ViewDefinition{{}, nil},
+		})
+		if err == nil || err.Error() != "dash0: reconcile: view 1 is nil" {
+			t.Errorf("expected error naming the nil view, got %v", err)
+		}
+	})
+}
+
+func TestReconciler_Apply(t *testing.T) {
+	t.Run("applies all changes and collects errors", func(t *testing.T) {
+		var mu sync.Mutex
+		var calls []string
+		record := func(call string) {
+			mu.Lock()
+			defer mu.Unlock()
+			calls = append(calls, call)
+		}
+		deleteErr := errors.New("delete failed")
+
+		mock := &dash0test.MockClient{
+			CreateDashboardFunc: func(ctx context.Context, dashboard *dash0.DashboardDefinition, dataset *string) (*dash0.DashboardDefinition, error) {
+				t.Error("expected creations to upsert by ID")
+				return dashboard, nil
+			},
+			UpdateDashboardFunc: func(ctx context.Context, originOrID string, dashboard *dash0.DashboardDefinition, dataset *string) (*dash0.DashboardDefinition, error) {
+				record("update " + originOrID)
+				return dashboard, nil
+			},
+			DeleteDashboardFunc: func(ctx context.Context, originOrID string, dataset *string) error {
+				record("delete " + originOrID)
+				return deleteErr
+			},
+		}
+
+		plan := &Plan{Changes: []Change{
+			{Kind: KindDashboard, Action: ActionCreate, ID: "a", Desired: dashboard("a", "a")},
+			{Kind: KindDashboard, Action: ActionUpdate, ID: "b", Desired: dashboard("b", "b")},
+			{Kind: KindDashboard, Action: ActionNoop, ID: "c", Desired: dashboard("c", "c")},
+			{Kind: KindDashboard, Action: ActionDelete, ID: "d"},
+		}}
+
+		err := NewReconciler(mock, WithConcurrency(2)).Apply(context.Background(), plan)
+		if !errors.Is(err, deleteErr) {
+			t.Fatalf("expected delete error, got %v", err)
+		}
+		var changeErr *ChangeError
+		if !errors.As(err, &changeErr) || changeErr.Change.ID != "d" {
+			t.Errorf("expected ChangeError for d, got %v", err)
+		}
+		slices.Sort(calls)
+		if want := []string{"delete d", "update a", "update b"}; !slices.Equal(calls, want) {
+			t.Errorf("expected calls %v, got %v", want, calls)
+		}
+	})
+
+	t.Run("converges to the desired state", func(t *testing.T) {
+		stored := map[string]*dash0.DashboardDefinition{
+			"unchanged": dashboard("unchanged", "unchanged"),
+			"changed":   dashboard("changed", "changed"),
+			"obsolete":  dashboard("obsolete", "obsolete"),
+		}
+		stored["changed"].Spec["duration"] = "30m"
+		var mu sync.Mutex
+		mock := &dash0test.MockClient{
+			ListDashboardsFunc: func(ctx context.Context, dataset *string) ([]*dash0.DashboardApiListItem, error) {
+				mu.Lock()
+				defer mu.Unlock()
+				var items []*dash0.DashboardApiListItem
+				for origin := range stored {
+					items = append(items, &dash0.DashboardApiListItem{Id: "uuid-" + origin, Origin: dash0.Ptr(origin)})
+				}
+				return items, nil
+			},
+			GetDashboardFunc: func(ctx context.Context, originOrID string, dataset *string) (*dash0.DashboardDefinition, error) {
+				mu.Lock()
+				defer mu.Unlock()
+				return stored[originOrID], nil
+			},
+			CreateDashboardFunc: func(ctx context.Context, dashboard *dash0.DashboardDefinition, dataset *string) (*dash0.DashboardDefinition, error) {
+				// The API assigns new IDs to created dashboards.
+				mu.Lock()
+				defer mu.Unlock()
+				stored[fmt.Sprintf("generated-%d", len(stored))] = dashboard
+				return dashboard, nil
+			},
+			UpdateDashboardFunc: func(ctx context.Context, originOrID string, dashboard *dash0.DashboardDefinition, dataset *string) (*dash0.DashboardDefinition, error) {
+				mu.Lock()
+				defer mu.Unlock()
+				stored[originOrID] = dashboard
+				return dashboard, nil
+			},
+			DeleteDashboardFunc: func(ctx context.Context, originOrID string, dataset *string) error {
+				mu.Lock()
+				defer mu.Unlock()
+				delete(stored, originOrID)
+				return nil
+			},
+		}
+		desired := &DesiredState{Dashboards: []*dash0.DashboardDefinition{
+			dashboard("unchanged", "unchanged"),
+			dashboard("changed", "changed"),
+			dashboard("new", "new"),
+		}}
+
+		r := NewReconciler(mock, WithPrune(true))
+		plan, err := r.Plan(context.Background(), desired)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if err := r.Apply(context.Background(), plan); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		plan, err = r.Plan(context.Background(), desired)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if plan.HasChanges() || plan.Count(ActionNoop) != 3 {
+			t.Errorf("expected 3 no-ops, got %v", plan.Changes)
+		}
+	})
+
+	t.Run("rejects mismatched definition types", func(t *testing.T) {
+		plan := &Plan{Changes: []Change{
+			{Kind: KindView, Action: ActionCreate, ID: "a", Desired: dashboard("a", "a")},
+		}}
+
+		err := NewReconciler(&dash0test.MockClient{}).Apply(context.Background(), plan)
+		if err == nil {
+			t.Fatal("expected error for mismatched type")
+		}
+	})
+}
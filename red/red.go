@@ -0,0 +1,328 @@
+// Package red aggregates spans into RED metrics: the rate of spans, the rate
+// and percentage of errors, and duration percentiles. The metrics are
+// grouped by attribute keys, like ViewSpec.GroupBy, and bucketed into time
+// series. They correspond to the span metrics of views (see
+// dash0.ViewVisualizationMetric), but are computed on the client from the
+// results of GetSpansIter, e.g. for release reports.
+//
+// Spans are assigned to buckets by their start time, and spans without a
+// start time are ignored. Errors are spans with status code
+// STATUS_CODE_ERROR.
+//
+// Example:
+//
+//	series, err := red.Aggregate(client.GetSpansIter(ctx, request).All(),
+//	    red.WithGroupBy("service.name", "otel.span.name"),
+//	    red.WithInterval(5*time.Minute))
+//	if err != nil {
+//	    // handle error
+//	}
+//	for _, s := range series {
+//	    fmt.Printf("%v %.2f/s %.1f%% p95=%v\n", s.Group, s.Total.Rate, s.Total.ErrorPercentage(), s.Total.P95)
+//	}
+package red
+
+import (
+	"iter"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/dash0hq/dash0-api-client-go"
+	"github.com/dash0hq/dash0-api-client-go/internal/stats"
+)
+
+// DefaultInterval is the default width of the buckets of a series.
+const DefaultInterval = time.Minute
+
+// Option configures an Aggregator.
+type Option func(*config)
+
+type config struct {
+	groupBy  []string
+	interval time.Duration
+	from, to time.Time
+}
+
+// WithGroupBy groups the spans by the values of the given keys. Keys are
+// looked up like dash0.LookupSpanKey does, so they may name span fields such
+// as dash0.FilterKeySpanName as well as span, scope and resource attributes.
+// Without keys, all spans form a single series.
+func WithGroupBy(keys ...string) Option {
+	return func(c *config) {
+		c.groupBy = keys
+	}
+}
+
+// WithInterval sets the width of the buckets. Defaults to DefaultInterval.
+func WithInterval(interval time.Duration) Option {
+	return func(c *config) {
+		c.interval = interval
+	}
+}
+
+// WithTimeRange sets the time range of the series, typically the time range
+// of the query. Buckets start at from, spans starting outside of the range
+// are ignored, and rates are computed over the whole range. A zero from or to
+// leaves that end of the range open, so that it ends at the bucket of the
+// first or last span. By default, the range spans the buckets from the first
+// to the last span, aligned to the interval.
+func WithTimeRange(from, to time.Time) Option {
+	return func(c *config) {
+		c.from, c.to = from, to
+	}
+}
+
+// Series holds the metrics of a group of spans.
+type Series struct {
+	// Group holds the values of the WithGroupBy keys, in order. Missing
+	// keys have an empty value.
+	Group []string
+
+	// Total holds the metrics over the whole time range.
+	Total Stats
+
+	// Buckets cover the time range without gaps, in order. Buckets
+	// without spans have zero metrics.
+	Buckets []Bucket
+}
+
+// Bucket holds the metrics of the spans starting within an interval.
+type Bucket struct {
+	Start time.Time
+	Stats
+}
+
+// Stats are the RED metrics of a set of spans.
+type Stats struct {
+	// Count and Errors are the number of spans and failed spans.
+	Count  int
+	Errors int
+
+	// Rate and ErrorRate are the number of spans and failed spans per
+	// second.
+	Rate      float64
+	ErrorRate float64
+
+	// Avg is the mean duration, P50 to P99 are duration percentiles.
+	Avg time.Duration
+	P50 time.Duration
+	P75 time.Duration
+	P90 time.Duration
+	P95 time.Duration
+	P99 time.Duration
+}
+
+// ErrorPercentage returns the percentage of failed spans, between 0 and 100.
+func (s Stats) ErrorPercentage() float64 {
+	if s.Count == 0 {
+		return 0
+	}
+	return 100 * float64(s.Errors) / float64(s.Count)
+}
+
+// Metric returns the value of a span metric of views. Durations are returned
+// in seconds. It returns false for metrics of log records and unknown metrics.
+func (s Stats) Metric(metric dash0.ViewVisualizationMetric) (float64, bool) {
+	switch metric {
+	case dash0.SpansTotal:
+		return float64(s.Count), true
+	case dash0.SpansRate:
+		return s.Rate, true
+	case dash0.SpansErrorsTotal:
+		return float64(s.Errors), true
+	case dash0.SpansErrorsRate:
+		return s.ErrorRate, true
+	case dash0.SpansErrorsPercentage:
+		return s.ErrorPercentage(), true
+	case dash0.SpansDurationAvg:
+		return s.Avg.Seconds(), true
+	case dash0.SpansDurationP50:
+		return s.P50.Seconds(), true
+	case dash0.SpansDurationP75:
+		return s.P75.Seconds(), true
+	case dash0.SpansDurationP90:
+		return s.P90.Seconds(), true
+	case dash0.SpansDurationP95:
+		return s.P95.Seconds(), true
+	case dash0.SpansDurationP99:
+		return s.P99.Seconds(), true
+	}
+	return 0, false
+}
+
+// Aggregator aggregates spans incrementally. An Aggregator is not safe for
+// concurrent use.
+type Aggregator struct {
+	config *config
+	groups map[string]*group
+
+	// spans is the number of spans added, and first and last are the
+	// earliest and latest bucket with spans, in nanoseconds.
+	spans       int
+	first, last int64
+}
+
+// group accumulates the spans of a series.
+type group struct {
+	values  []string
+	total   accumulator
+	buckets map[int64]*accumulator
+}
+
+type accumulator struct {
+	count, errors int
+	durations     stats.Durations
+}
+
+// NewAggregator returns an Aggregator with the given options.
+func NewAggregator(opts ...Option) *Aggregator {
+	cfg := &config{interval: DefaultInterval}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	if cfg.interval <= 0 {
+		cfg.interval = DefaultInterval
+	}
+	return &Aggregator{config: cfg, groups: make(map[string]*group)}
+}
+
+// Aggregate aggregates all spans of resourceSpans, e.g. the items of
+// GetSpansIter, and returns the series. It stops at the first error of
+// resourceSpans.
+func Aggregate(resourceSpans iter.Seq2[*dash0.ResourceSpans, error], opts ...Option) ([]*Series, error) {
+	a := NewAggregator(opts...)
+	for rs, err := range resourceSpans {
+		if err != nil {
+			return nil, err
+		}
+		a.AddResourceSpans(rs)
+	}
+	return a.Series(), nil
+}
+
+// AddResourceSpans adds all spans of the resource spans.
+func (a *Aggregator) AddResourceSpans(rs *dash0.ResourceSpans) {
+	for i := range rs.ScopeSpans {
+		ss := &rs.ScopeSpans[i]
+		for j := range ss.Spans {
+			a.Add(&dash0.SpanEntry{Resource: &rs.Resource, Scope: ss.Scope, Span: &ss.Spans[j]})
+		}
+	}
+}
+
+// Add adds a span. Spans without a start time or starting outside of the
+// WithTimeRange range are ignored.
+func (a *Aggregator) Add(entry *dash0.SpanEntry) {
+	start, end := dash0.UnixNano(entry.Span.StartTimeUnixNano), dash0.UnixNano(entry.Span.EndTimeUnixNano)
+	if start == 0 {
+		return
+	}
+	if !a.config.from.IsZero() && start < a.config.from.UnixNano() {
+		return
+	}
+	if !a.config.to.IsZero() && start >= a.config.to.UnixNano() {
+		return
+	}
+
+	values := make([]string, len(a.config.groupBy))
+	for i, key := range a.config.groupBy {
+		values[i], _ = dash0.LookupSpanKey(entry.Resource, entry.Scope, entry.Span, key)
+	}
+	key := strings.Join(values, "\x00")
+	g, ok := a.groups[key]
+	if !ok {
+		g = &group{values: values, buckets: make(map[int64]*accumulator)}
+		a.groups[key] = g
+	}
+
+	bucket := a.bucket(start)
+	acc, ok := g.buckets[bucket]
+	if !ok {
+		acc = &accumulator{}
+		g.buckets[bucket] = acc
+	}
+	if a.spans == 0 || bucket < a.first {
+		a.first = bucket
+	}
+	if a.spans == 0 || bucket > a.last {
+		a.last = bucket
+	}
+	a.spans++
+
+	failed := entry.Span.Status.Code == dash0.SpanStatusCodeError
+	duration := time.Duration(max(end-start, 0))
+	acc.add(failed, duration)
+	g.total.add(failed, duration)
+}
+
+// bucket returns the start of the bucket of a timestamp in nanoseconds.
+func (a *Aggregator) bucket(t int64) int64 {
+	var origin int64
+	if !a.config.from.IsZero() {
+		origin = a.config.from.UnixNano()
+	}
+	interval := int64(a.config.interval)
+	offset := (t - origin) % interval
+	if offset < 0 {
+		offset += interval
+	}
+	return t - offset
+}
+
+// Series returns the series of the spans added so far, ordered by group.
+func (a *Aggregator) Series() []*Series {
+	from, to := a.first, a.last+int64(a.config.interval)
+	if !a.config.from.IsZero() {
+		from = a.config.from.UnixNano()
+	}
+	if !a.config.to.IsZero() {
+		to = a.config.to.UnixNano()
+	}
+
+	var result []*Series
+	for _, g := range a.groups {
+		s := &Series{Group: g.values, Total: g.total.stats(time.Duration(to - from))}
+		for start := from; start < to; start += int64(a.config.interval) {
+			acc, ok := g.buckets[start]
+			if !ok {
+				acc = &accumulator{}
+			}
+			width := time.Duration(min(to-start, int64(a.config.interval)))
+			s.Buckets = append(s.Buckets, Bucket{Start: time.Unix(0, start).UTC(), Stats: acc.stats(width)})
+		}
+		result = append(result, s)
+	}
+	slices.SortFunc(result, func(a, b *Series) int {
+		return slices.Compare(a.Group, b.Group)
+	})
+	return result
+}
+
+func (acc *accumulator) add(failed bool, duration time.Duration) {
+	acc.count++
+	if failed {
+		acc.errors++
+	}
+	acc.durations.Add(duration)
+}
+
+// stats returns the metrics of the accumulated spans, with rates over the
+// given period.
+func (acc *accumulator) stats(period time.Duration) Stats {
+	s := Stats{
+		Count:  acc.count,
+		Errors: acc.errors,
+		Avg:    acc.durations.Mean(),
+		P50:    acc.durations.Percentile(50),
+		P75:    acc.durations.Percentile(75),
+		P90:    acc.durations.Percentile(90),
+		P95:    acc.durations.Percentile(95),
+		P99:    acc.durations.Percentile(99),
+	}
+	if seconds := period.Seconds(); seconds > 0 {
+		s.Rate = float64(acc.count) / seconds
+		s.ErrorRate = float64(acc.errors) / seconds
+	}
+	return s
+}
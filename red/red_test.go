@@ -0,0 +1,152 @@
+package red
+
+import (
+	"errors"
+	"fmt"
+	"iter"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/dash0hq/dash0-api-client-go"
+)
+
+var base = time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+
+func testSpan(name string, start time.Duration, duration time.Duration, failed bool) dash0.Span {
+	span := dash0.Span{
+		Name:              name,
+		StartTimeUnixNano: strconv.FormatInt(base.Add(start).UnixNano(), 10),
+		EndTimeUnixNano:   strconv.FormatInt(base.Add(start+duration).UnixNano(), 10),
+	}
+	if failed {
+		span.Status.Code = dash0.SpanStatusCodeError
+	}
+	return span
+}
+
+func testResourceSpans(service string, spans ...dash0.Span) dash0.ResourceSpans {
+	return dash0.ResourceSpans{
+		Resource:   dash0.Resource{Attributes: []dash0.KeyValue{{Key: "service.name", Value: dash0.AnyValue{StringValue: dash0.String(service)}}}},
+		ScopeSpans: []dash0.ScopeSpans{{Spans: spans}},
+	}
+}
+
+func seq(err error) iter.Seq2[*dash0.ResourceSpans, error] {
+	resourceSpans := []dash0.ResourceSpans{
+		testResourceSpans("checkout",
+			testSpan("GET /cart", 10*time.Second, 100*time.Millisecond, false),
+			testSpan("GET /cart", 20*time.Second, 300*time.Millisecond, true),
+			testSpan("GET /cart", 2*time.Minute+5*time.Second, 200*time.Millisecond, false),
+			testSpan("POST /order", 30*time.Second, time.Second, false),
+		),
+		testResourceSpans("payment", testSpan("charge", 50*time.Second, 50*time.Millisecond, true)),
+	}
+	return func(yield func(*dash0.ResourceSpans, error) bool) {
+		for i := range resourceSpans {
+			if !yield(&resourceSpans[i], nil) {
+				return
+			}
+		}
+		if err != nil {
+			yield(nil, err)
+		}
+	}
+}
+
+func counts(buckets []Bucket) string {
+	var s string
+	for _, b := range buckets {
+		s += fmt.Sprintf("%s=%d/%d ", b.Start.Format("15:04"), b.Errors, b.Count)
+	}
+	return s
+}
+
+func TestAggregate(t *testing.T) {
+	series, err := Aggregate(seq(nil), WithGroupBy("service.name", dash0.FilterKeySpanName))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(series) != 3 {
+		t.Fatalf("expected 3 series, got %d", len(series))
+	}
+	if got := fmt.Sprint(series[0].Group, series[1].Group, series[2].Group); got != "[checkout GET /cart] [checkout POST /order] [payment charge]" {
+		t.Errorf("unexpected groups %s", got)
+	}
+
+	cart := series[0]
+	if got := counts(cart.Buckets); got != "10:00=1/2 10:01=0/0 10:02=0/1 " {
+		t.Errorf("unexpected buckets %s", got)
+	}
+	total := cart.Total
+	if total.Count != 3 || total.Errors != 1 || total.Rate != 3.0/180 || total.ErrorRate != 1.0/180 {
+		t.Errorf("unexpected totals %+v", total)
+	}
+	if total.Avg != 200*time.Millisecond || total.P50 != 200*time.Millisecond || total.P99 != 300*time.Millisecond {
+		t.Errorf("unexpected durations %+v", total)
+	}
+	if p := total.ErrorPercentage(); p < 33.3 || p > 33.4 {
+		t.Errorf("unexpected error percentage %v", p)
+	}
+	if rate := cart.Buckets[0].Rate; rate != 2.0/60 {
+		t.Errorf("unexpected bucket rate %v", rate)
+	}
+	if v, ok := total.Metric(dash0.SpansDurationP95); !ok || v != 0.3 {
+		t.Errorf("unexpected p95 metric %v", v)
+	}
+	if _, ok := total.Metric(dash0.LogsTotal); ok {
+		t.Error("expected no value for log metrics")
+	}
+
+	t.Run("aggregates all spans without group keys", func(t *testing.T) {
+		series, _ := Aggregate(seq(nil), WithInterval(time.Hour))
+		if len(series) != 1 || len(series[0].Group) != 0 || series[0].Total.Count != 5 || len(series[0].Buckets) != 1 {
+			t.Errorf("unexpected series %+v", series)
+		}
+	})
+
+	t.Run("restricts series to the time range", func(t *testing.T) {
+		from := base.Add(15 * time.Second)
+		series, _ := Aggregate(seq(nil), WithTimeRange(from, from.Add(90*time.Second)), WithInterval(time.Minute))
+		if len(series) != 1 {
+			t.Fatalf("expected 1 series, got %d", len(series))
+		}
+		s := series[0]
+		if got := counts(s.Buckets); got != "10:00=2/3 10:01=0/0 " {
+			t.Errorf("unexpected buckets %s", got)
+		}
+		if s.Buckets[1].Start != from.Add(time.Minute) || s.Total.Rate != 3.0/90 {
+			t.Errorf("unexpected bucket start %v or rate %v", s.Buckets[1].Start, s.Total.Rate)
+		}
+	})
+
+	t.Run("leaves a zero end of the time range open", func(t *testing.T) {
+		from := base.Add(15 * time.Second)
+		series, _ := Aggregate(seq(nil), WithTimeRange(from, time.Time{}), WithInterval(time.Minute))
+		if len(series) != 1 {
+			t.Fatalf("expected 1 series, got %d", len(series))
+		}
+		if got := counts(series[0].Buckets); got != "10:00=2/3 10:01=0/1 " {
+			t.Errorf("unexpected buckets %s", got)
+		}
+	})
+
+	t.Run("ignores spans without start time", func(t *testing.T) {
+		a := NewAggregator()
+		a.AddResourceSpans(&dash0.ResourceSpans{ScopeSpans: []dash0.ScopeSpans{{Spans: []dash0.Span{
+			testSpan("GET /cart", 0, time.Second, false),
+			{Name: "GET /cart", EndTimeUnixNano: "1000"},
+		}}}})
+		series := a.Series()
+		if len(series) != 1 || series[0].Total.Count != 1 || len(series[0].Buckets) != 1 {
+			t.Errorf("unexpected series %+v", series)
+		}
+	})
+
+	t.Run("returns iteration errors", func(t *testing.T) {
+		errFetch := errors.New("fetch failed")
+		if _, err := Aggregate(seq(errFetch)); !errors.Is(err, errFetch) {
+			t.Errorf("expected iteration error, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,131 @@
+package render
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/dash0hq/dash0-api-client-go"
+)
+
+// ConsoleTimeFormat is the default timestamp layout of the console renderer,
+// RFC 3339 with milliseconds.
+const ConsoleTimeFormat = "2006-01-02T15:04:05.000Z07:00"
+
+// ANSI escape sequences of the console renderer.
+const (
+	ansiReset   = "\x1b[0m"
+	ansiBold    = "\x1b[1m"
+	ansiDim     = "\x1b[2m"
+	ansiRed     = "\x1b[31m"
+	ansiGreen   = "\x1b[32m"
+	ansiYellow  = "\x1b[33m"
+	ansiBlue    = "\x1b[34m"
+	ansiMagenta = "\x1b[35m"
+	ansiCyan    = "\x1b[36m"
+)
+
+// Console returns a renderer that writes log records as colored lines for
+// terminals, e.g.
+//
+//	2024-01-15T14:30:00.123Z ERROR [checkout] payment failed user.id=42 trace_id=4bf92f3577b34da6a3ce929d0e0e4736
+//
+// The line consists of the time, the severity colored by its range, the
+// values of the resource attributes selected by WithResourceAttributes, the
+// body, and the event name, attributes and trace context as key-value pairs.
+// Bodies and resource attribute values that contain control characters, such
+// as newlines or escape sequences, are quoted, so that every log record is
+// written as a single line. Use WithColor(false) to write plain text.
+func Console(opts ...Option) Renderer {
+	return &consoleRenderer{config: newConfig(ConsoleTimeFormat, opts)}
+}
+
+type consoleRenderer struct {
+	config *config
+}
+
+func (r *consoleRenderer) Render(w io.Writer, entry *dash0.LogRecordEntry) error {
+	record := entry.LogRecord
+	var b strings.Builder
+
+	if t := dash0.LogRecordTime(record); !t.IsZero() {
+		r.write(&b, ansiDim, t.Format(r.config.timeFormat))
+		b.WriteByte(' ')
+	}
+	severity := dash0.LogRecordSeverity(record)
+	r.write(&b, severityColor(record), fmt.Sprintf("%-5s", severity))
+
+	if fields := r.config.resourceFields(entry.Resource); len(fields) > 0 {
+		values := make([]string, len(fields))
+		for i, f := range fields {
+			values[i] = consoleText(f.value)
+		}
+		b.WriteByte(' ')
+		r.write(&b, ansiCyan, "["+strings.Join(values, " ")+"]")
+	}
+	if record.Body != nil {
+		b.WriteByte(' ')
+		b.WriteString(consoleText(body(record)))
+	}
+
+	var fields []field
+	if name := dash0.StringValue(record.EventName); name != "" {
+		fields = append(fields, field{"event", name})
+	}
+	fields = append(fields, attributeFields(record)...)
+	fields = append(fields, traceFields(record)...)
+	for _, f := range fields {
+		b.WriteByte(' ')
+		r.write(&b, ansiDim, logfmtKey(f.key)+"=")
+		b.WriteString(logfmtValue(f.value))
+	}
+	b.WriteByte('\n')
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// consoleText quotes s if it contains control characters or invalid UTF-8.
+func consoleText(s string) string {
+	for _, r := range s {
+		if unicode.IsControl(r) || r == utf8.RuneError {
+			return strconv.Quote(s)
+		}
+	}
+	return s
+}
+
+// write writes s in the given color if colors are enabled.
+func (r *consoleRenderer) write(b *strings.Builder, color, s string) {
+	if !r.config.color {
+		b.WriteString(s)
+		return
+	}
+	b.WriteString(color)
+	b.WriteString(s)
+	b.WriteString(ansiReset)
+}
+
+// severityColor returns the color of the severity range of a log record.
+func severityColor(record *dash0.LogRecord) string {
+	var n dash0.SeverityNumber
+	if record.SeverityNumber != nil {
+		n = *record.SeverityNumber
+	}
+	switch {
+	case n >= 21:
+		return ansiBold + ansiMagenta
+	case n >= 17:
+		return ansiBold + ansiRed
+	case n >= 13:
+		return ansiYellow
+	case n >= 9:
+		return ansiGreen
+	case n >= 5:
+		return ansiBlue
+	default:
+		return ansiDim
+	}
+}
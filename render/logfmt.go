@@ -0,0 +1,86 @@
+package render
+
+import (
+	"io"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/dash0hq/dash0-api-client-go"
+)
+
+// Logfmt returns a renderer that writes log records in logfmt, e.g.
+//
+//	time=2024-01-15T14:30:00.123Z level=ERROR service.name=checkout msg="payment failed" trace_id=4bf92f3577b34da6a3ce929d0e0e4736 user.id=42
+//
+// The fields are the time, the severity, the resource attributes selected by
+// WithResourceAttributes, the event name, the body, the trace and span IDs,
+// and the attributes of the log record. Unset fields are omitted. Values are
+// quoted if they are empty or contain spaces, quotes, equal signs or control
+// characters. Timestamps default to time.RFC3339Nano.
+func Logfmt(opts ...Option) Renderer {
+	return &logfmtRenderer{config: newConfig(time.RFC3339Nano, opts)}
+}
+
+type logfmtRenderer struct {
+	config *config
+}
+
+func (r *logfmtRenderer) Render(w io.Writer, entry *dash0.LogRecordEntry) error {
+	record := entry.LogRecord
+	var b strings.Builder
+
+	var fields []field
+	if t := dash0.LogRecordTime(record); !t.IsZero() {
+		fields = append(fields, field{"time", t.Format(r.config.timeFormat)})
+	}
+	fields = append(fields, field{"level", dash0.LogRecordSeverity(record)})
+	fields = append(fields, r.config.resourceFields(entry.Resource)...)
+	if name := dash0.StringValue(record.EventName); name != "" {
+		fields = append(fields, field{"event", name})
+	}
+	if record.Body != nil {
+		fields = append(fields, field{"msg", body(record)})
+	}
+	fields = append(fields, traceFields(record)...)
+	fields = append(fields, attributeFields(record)...)
+
+	for i, f := range fields {
+		if i > 0 {
+			b.WriteByte(' ')
+		}
+		b.WriteString(logfmtKey(f.key))
+		b.WriteByte('=')
+		b.WriteString(logfmtValue(f.value))
+	}
+	b.WriteByte('\n')
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// logfmtKey replaces characters that are not allowed in logfmt keys by
+// underscores.
+func logfmtKey(key string) string {
+	if key == "" {
+		return "_"
+	}
+	return strings.Map(func(r rune) rune {
+		if r <= ' ' || r == '=' || r == '"' || r == utf8.RuneError {
+			return '_'
+		}
+		return r
+	}, key)
+}
+
+func logfmtValue(value string) string {
+	if value == "" {
+		return `""`
+	}
+	for _, r := range value {
+		if r <= ' ' || r == '=' || r == '"' || r == '\\' || r == utf8.RuneError || r == 0x7f {
+			return strconv.Quote(value)
+		}
+	}
+	return value
+}
@@ -0,0 +1,145 @@
+// Package render formats log records as human-readable text: as logfmt, as
+// colored console lines, or through a text/template.
+//
+// Renderers take a dash0.LogRecordEntry, i.e. a log record together with its
+// resource and scope, so they can be used directly with the entries of
+// TailLogRecords. Timestamps are rendered in RFC 3339, severities by their
+// names (see dash0.LogRecordSeverity), and bodies and attributes as strings.
+//
+// Example:
+//
+//	r := render.Console(render.WithResourceAttributes("service.name", "k8s.pod.name"))
+//	err := client.TailLogRecords(ctx, request, func(entry *dash0.LogRecordEntry) error {
+//	    return r.Render(os.Stdout, entry)
+//	})
+package render
+
+import (
+	"encoding/hex"
+	"io"
+
+	"github.com/dash0hq/dash0-api-client-go"
+)
+
+// DefaultResourceAttributes are the resource attributes rendered by default.
+var DefaultResourceAttributes = []string{"service.name"}
+
+// Renderer renders log records.
+type Renderer interface {
+	// Render writes a log record as a single line, terminated by a newline.
+	Render(w io.Writer, entry *dash0.LogRecordEntry) error
+}
+
+// Option configures a renderer.
+type Option func(*config)
+
+type config struct {
+	resourceAttributes []string
+	timeFormat         string
+	color              bool
+}
+
+// WithResourceAttributes sets the resource attributes to render, in the given
+// order. Resource attributes that are not set are omitted. Defaults to
+// DefaultResourceAttributes.
+func WithResourceAttributes(keys ...string) Option {
+	return func(c *config) {
+		c.resourceAttributes = keys
+	}
+}
+
+// WithTimeFormat sets the layout of timestamps, as understood by
+// time.Time.Format. Timestamps are always rendered in UTC.
+func WithTimeFormat(layout string) Option {
+	return func(c *config) {
+		c.timeFormat = layout
+	}
+}
+
+// WithColor enables or disables ANSI colors of the console renderer, e.g. to
+// disable colors when not writing to a terminal. Colors are enabled by
+// default.
+func WithColor(enabled bool) Option {
+	return func(c *config) {
+		c.color = enabled
+	}
+}
+
+func newConfig(timeFormat string, opts []Option) *config {
+	cfg := &config{
+		resourceAttributes: DefaultResourceAttributes,
+		timeFormat:         timeFormat,
+		color:              true,
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	return cfg
+}
+
+// RenderAll renders all log records of the resource logs.
+func RenderAll(w io.Writer, r Renderer, resourceLogs []dash0.ResourceLogs) error {
+	for i := range resourceLogs {
+		rl := &resourceLogs[i]
+		for j := range rl.ScopeLogs {
+			sl := &rl.ScopeLogs[j]
+			for k := range sl.LogRecords {
+				if err := r.Render(w, &dash0.LogRecordEntry{Resource: &rl.Resource, Scope: sl.Scope, LogRecord: &sl.LogRecords[k]}); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// body returns the body of a log record as string.
+func body(record *dash0.LogRecord) string {
+	if record.Body == nil {
+		return ""
+	}
+	return record.Body.String()
+}
+
+// field is a key-value pair of a rendered line.
+type field struct {
+	key, value string
+}
+
+// resourceFields returns the configured resource attributes that are set.
+func (c *config) resourceFields(resource *dash0.Resource) []field {
+	if resource == nil {
+		return nil
+	}
+	var fields []field
+	for _, key := range c.resourceAttributes {
+		for _, kv := range resource.Attributes {
+			if kv.Key == key {
+				fields = append(fields, field{key, kv.Value.String()})
+				break
+			}
+		}
+	}
+	return fields
+}
+
+// traceFields returns the trace and span IDs of a log record, if set.
+func traceFields(record *dash0.LogRecord) []field {
+	var fields []field
+	if record.TraceId != nil && len(*record.TraceId) > 0 {
+		fields = append(fields, field{"trace_id", hex.EncodeToString(*record.TraceId)})
+	}
+	if record.SpanId != nil && len(*record.SpanId) > 0 {
+		fields = append(fields, field{"span_id", hex.EncodeToString(*record.SpanId)})
+	}
+	return fields
+}
+
+// attributeFields returns the attributes of a log record.
+func attributeFields(record *dash0.LogRecord) []field {
+	fields := make([]field, len(record.Attributes))
+	for i, kv := range record.Attributes {
+		fields[i] = field{kv.Key, kv.Value.String()}
+	}
+	return fields
+}
@@ -0,0 +1,115 @@
+package render
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/dash0hq/dash0-api-client-go"
+)
+
+func stringValue(s string) dash0.AnyValue {
+	return dash0.AnyValue{StringValue: dash0.String(s)}
+}
+
+func testEntry() *dash0.LogRecordEntry {
+	return &dash0.LogRecordEntry{
+		Resource: &dash0.Resource{Attributes: []dash0.KeyValue{
+			{Key: "service.name", Value: stringValue("checkout")},
+			{Key: "k8s.pod.name", Value: stringValue("checkout-7d9f")},
+		}},
+		LogRecord: &dash0.LogRecord{
+			TimeUnixNano:   "1705329000123456789",
+			SeverityNumber: dash0.Ptr(dash0.SeverityNumber(17)),
+			Body:           dash0.Ptr(stringValue("payment failed")),
+			TraceId:        dash0.Ptr([]byte{0x4b, 0xf9, 0x2f, 0x35, 0x77, 0xb3, 0x4d, 0xa6, 0xa3, 0xce, 0x92, 0x9d, 0x0e, 0x0e, 0x47, 0x36}),
+			Attributes: []dash0.KeyValue{
+				{Key: "user.id", Value: dash0.AnyValue{IntValue: dash0.String("42")}},
+				{Key: "reason", Value: stringValue(`card "declined"`)},
+			},
+		},
+	}
+}
+
+func render(t *testing.T, r Renderer, entry *dash0.LogRecordEntry) string {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := r.Render(&buf, entry); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return buf.String()
+}
+
+func TestLogfmt(t *testing.T) {
+	got := render(t, Logfmt(), testEntry())
+	want := `time=2024-01-15T14:30:00.123456789Z level=ERROR service.name=checkout msg="payment failed" trace_id=4bf92f3577b34da6a3ce929d0e0e4736 user.id=42 reason="card \"declined\""` + "\n"
+	if got != want {
+		t.Errorf("unexpected logfmt:\n got %s\nwant %s", got, want)
+	}
+
+	t.Run("renders selected resource attributes", func(t *testing.T) {
+		entry := testEntry()
+		entry.LogRecord.Body = nil
+		got := render(t, Logfmt(WithResourceAttributes("k8s.pod.name", "missing"), WithTimeFormat("15:04:05")), entry)
+		if !strings.HasPrefix(got, "time=14:30:00 level=ERROR k8s.pod.name=checkout-7d9f trace_id=") {
+			t.Errorf("unexpected logfmt %s", got)
+		}
+	})
+}
+
+func TestConsole(t *testing.T) {
+	got := render(t, Console(WithColor(false)), testEntry())
+	want := `2024-01-15T14:30:00.123Z ERROR [checkout] payment failed user.id=42 reason="card \"declined\"" trace_id=4bf92f3577b34da6a3ce929d0e0e4736` + "\n"
+	if got != want {
+		t.Errorf("unexpected console line:\n got %s\nwant %s", got, want)
+	}
+
+	colored := render(t, Console(), testEntry())
+	if !strings.Contains(colored, ansiBold+ansiRed+"ERROR"+ansiReset) {
+		t.Errorf("expected colored severity, got %q", colored)
+	}
+
+	t.Run("escapes control characters", func(t *testing.T) {
+		entry := testEntry()
+		entry.Resource.Attributes[0].Value = stringValue("check\x1b[2Jout")
+		entry.LogRecord.Body = dash0.Ptr(stringValue("payment failed\n\tat Checkout.pay\x1b[31m"))
+		got := render(t, Console(WithColor(false)), entry)
+		want := `2024-01-15T14:30:00.123Z ERROR ["check\x1b[2Jout"] "payment failed\n\tat Checkout.pay\x1b[31m" user.id=42 `
+		if !strings.HasPrefix(got, want) || strings.Count(got, "\n") != 1 || strings.Contains(got, "\x1b") {
+			t.Errorf("unexpected console line %q", got)
+		}
+	})
+}
+
+func TestTemplate(t *testing.T) {
+	r, err := Template(`{{.Time.Format "2006-01-02"}} {{.Severity}}/{{.SeverityNumber}} {{index .Resource "service.name"}} {{.Attributes.missing}}{{.Body}}`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := render(t, r, testEntry()); got != "2024-01-15 ERROR/17 checkout payment failed\n" {
+		t.Errorf("unexpected output %q", got)
+	}
+
+	if _, err := Template(`{{.Body`); err == nil {
+		t.Error("expected parse error")
+	}
+	r, _ = Template(`{{.Missing}}`)
+	if err := r.Render(&bytes.Buffer{}, testEntry()); err == nil {
+		t.Error("expected execution error for unknown fields")
+	}
+}
+
+func TestRenderAll(t *testing.T) {
+	entry := testEntry()
+	resourceLogs := []dash0.ResourceLogs{{
+		Resource:  *entry.Resource,
+		ScopeLogs: []dash0.ScopeLogs{{LogRecords: []dash0.LogRecord{*entry.LogRecord, *entry.LogRecord}}},
+	}}
+	var buf bytes.Buffer
+	if err := RenderAll(&buf, Logfmt(), resourceLogs); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if n := strings.Count(buf.String(), "\n"); n != 2 {
+		t.Errorf("expected 2 lines, got %d", n)
+	}
+}
@@ -0,0 +1,102 @@
+package render
+
+import (
+	"bytes"
+	"encoding/hex"
+	"io"
+	"text/template"
+	"time"
+
+	"github.com/dash0hq/dash0-api-client-go"
+)
+
+// TemplateData is the data a template is executed with.
+type TemplateData struct {
+	// Time is the time of the log record, or its observed time if the
+	// time is not set, in UTC.
+	Time time.Time
+
+	// Severity is the severity name of the log record, or its severity
+	// text if the severity number is not set.
+	Severity       string
+	SeverityNumber dash0.SeverityNumber
+	SeverityText   string
+
+	Body      string
+	EventName string
+	TraceID   string
+	SpanID    string
+
+	// Attributes holds the attributes of the log record as strings.
+	Attributes map[string]string
+
+	// Resource holds all resource attributes as strings.
+	Resource map[string]string
+
+	// Entry is the rendered log record with its resource and scope.
+	Entry *dash0.LogRecordEntry
+}
+
+// Template returns a renderer that executes a text/template for every log
+// record, with TemplateData as data. A newline is appended unless the output
+// already ends with one. Timestamps can be formatted with the Format method
+// of time.Time, e.g.
+//
+//	{{.Time.Format "2006-01-02T15:04:05Z07:00"}} [{{.Severity}}] {{index .Resource "service.name"}}: {{.Body}}
+//
+// Missing map keys render as empty strings.
+func Template(text string) (Renderer, error) {
+	tmpl, err := template.New("log").Option("missingkey=zero").Parse(text)
+	if err != nil {
+		return nil, err
+	}
+	return &templateRenderer{template: tmpl}, nil
+}
+
+type templateRenderer struct {
+	template *template.Template
+}
+
+func (r *templateRenderer) Render(w io.Writer, entry *dash0.LogRecordEntry) error {
+	record := entry.LogRecord
+	data := TemplateData{
+		Time:         dash0.LogRecordTime(record),
+		Severity:     dash0.LogRecordSeverity(record),
+		SeverityText: dash0.StringValue(record.SeverityText),
+		Body:         body(record),
+		EventName:    dash0.StringValue(record.EventName),
+		Attributes:   attributeMap(record.Attributes),
+		Resource:     map[string]string{},
+		Entry:        entry,
+	}
+	if record.SeverityNumber != nil {
+		data.SeverityNumber = *record.SeverityNumber
+	}
+	if record.TraceId != nil {
+		data.TraceID = hex.EncodeToString(*record.TraceId)
+	}
+	if record.SpanId != nil {
+		data.SpanID = hex.EncodeToString(*record.SpanId)
+	}
+	if entry.Resource != nil {
+		data.Resource = attributeMap(entry.Resource.Attributes)
+	}
+
+	var buf bytes.Buffer
+	if err := r.template.Execute(&buf, data); err != nil {
+		return err
+	}
+	if !bytes.HasSuffix(buf.Bytes(), []byte("\n")) {
+		buf.WriteByte('\n')
+	}
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+func attributeMap(attributes []dash0.KeyValue) map[string]string {
+	m := make(map[string]string, len(attributes))
+	for _, kv := range attributes {
+		m[kv.Key] = kv.Value.String()
+	}
+	return m
+}
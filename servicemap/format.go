@@ -0,0 +1,90 @@
+package servicemap
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// WriteDOT writes the map as a Graphviz digraph. Call edges are solid, link
+// edges dashed, and each edge is labeled with its requests, error rate and
+// latency percentiles.
+//
+// Example:
+//
+//	f, err := os.Create("services.dot")
+//	// ...
+//	err = m.WriteDOT(f)
+//	// dot -Tsvg services.dot > services.svg
+func (m *Map) WriteDOT(w io.Writer) error {
+	var b bytes.Buffer
+	b.WriteString("digraph services {\n")
+	b.WriteString("\trankdir=LR;\n")
+	b.WriteString("\tnode [shape=box];\n")
+	for _, n := range m.Nodes {
+		fmt.Fprintf(&b, "\t%s;\n", dotQuote(n.Service))
+	}
+	for _, e := range m.Edges {
+		fmt.Fprintf(&b, "\t%s -> %s [label=%s", dotQuote(e.From), dotQuote(e.To), dotQuote(edgeLabel(e, "\n")))
+		if e.Kind == EdgeLink {
+			b.WriteString(", style=dashed")
+		}
+		b.WriteString("];\n")
+	}
+	b.WriteString("}\n")
+	_, err := w.Write(b.Bytes())
+	return err
+}
+
+// WriteMermaid writes the map as a Mermaid flowchart, e.g. for embedding in
+// Markdown documentation. Call edges are solid, link edges dotted, and each
+// edge is labeled like in WriteDOT.
+func (m *Map) WriteMermaid(w io.Writer) error {
+	ids := make(map[string]string, len(m.Nodes))
+	var b bytes.Buffer
+	b.WriteString("flowchart LR\n")
+	for i, n := range m.Nodes {
+		ids[n.Service] = fmt.Sprintf("n%d", i)
+		fmt.Fprintf(&b, "    %s[\"%s\"]\n", ids[n.Service], mermaidEscape(n.Service))
+	}
+	for _, e := range m.Edges {
+		arrow := "-->"
+		if e.Kind == EdgeLink {
+			arrow = "-.->"
+		}
+		fmt.Fprintf(&b, "    %s %s|\"%s\"| %s\n", ids[e.From], arrow, mermaidEscape(edgeLabel(e, "<br/>")), ids[e.To])
+	}
+	_, err := w.Write(b.Bytes())
+	return err
+}
+
+// edgeLabel returns the label of an edge with its lines separated by sep.
+func edgeLabel(e *Edge, sep string) string {
+	return fmt.Sprintf("%d req, %.1f%% err%sp50 %s, p95 %s, p99 %s",
+		e.Requests, 100*e.ErrorRate(), sep, formatLatency(e.P50), formatLatency(e.P95), formatLatency(e.P99))
+}
+
+// formatLatency rounds a latency to three significant digits or less.
+func formatLatency(d time.Duration) string {
+	switch {
+	case d >= time.Second:
+		d = d.Round(10 * time.Millisecond)
+	case d >= time.Millisecond:
+		d = d.Round(10 * time.Microsecond)
+	case d >= time.Microsecond:
+		d = d.Round(10 * time.Nanosecond)
+	}
+	return d.String()
+}
+
+// dotQuote returns s as a quoted DOT string. Newlines become DOT line breaks.
+func dotQuote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s) + `"`
+}
+
+// mermaidEscape escapes s for a quoted Mermaid label.
+func mermaidEscape(s string) string {
+	return strings.ReplaceAll(s, `"`, "#quot;")
+}
@@ -0,0 +1,234 @@
+// Package servicemap derives a service dependency map from spans, with
+// request counts, error rates and latency percentiles per edge, and renders
+// it as Graphviz DOT or Mermaid flowchart.
+//
+// Nodes are the service.name attributes of the resources. Call edges connect
+// the service of a SPAN_KIND_CLIENT span with the service of a
+// SPAN_KIND_SERVER child span. Link edges connect the service of a linked
+// span with the service of the span declaring the link, e.g. a producer with
+// its consumer. Spans whose counterpart is not part of the queried spans do
+// not contribute edges, so query a time range that covers whole requests.
+//
+// Example:
+//
+//	m, err := servicemap.Build(client.GetSpansIter(ctx, request).All())
+//	if err != nil {
+//	    // handle error
+//	}
+//	err = m.WriteMermaid(os.Stdout)
+package servicemap
+
+import (
+	"cmp"
+	"iter"
+	"slices"
+	"time"
+
+	"github.com/dash0hq/dash0-api-client-go"
+	"github.com/dash0hq/dash0-api-client-go/internal/stats"
+)
+
+// EdgeKind is the kind of relation an Edge is derived from.
+type EdgeKind string
+
+const (
+	// EdgeCall is an edge from a client span to a server span.
+	EdgeCall EdgeKind = "call"
+
+	// EdgeLink is an edge from a linked span to the span declaring the
+	// link.
+	EdgeLink EdgeKind = "link"
+)
+
+// Map is a service dependency map.
+type Map struct {
+	// Nodes are ordered by service name.
+	Nodes []*Node
+
+	// Edges are ordered by From, To and Kind.
+	Edges []*Edge
+}
+
+// Node is a service.
+type Node struct {
+	Service string
+
+	// Spans is the number of spans of the service, and Errors the number
+	// of those with status code STATUS_CODE_ERROR.
+	Spans  int
+	Errors int
+}
+
+// Edge is a dependency between two services.
+type Edge struct {
+	From string
+	To   string
+	Kind EdgeKind
+
+	// Requests is the number of client and server span pairs of a call
+	// edge, or the number of span links of a link edge.
+	Requests int
+
+	// Errors is the number of requests where either span has status code
+	// STATUS_CODE_ERROR for call edges, or the span declaring the link for
+	// link edges.
+	Errors int
+
+	// P50, P95 and P99 are latency percentiles of the requests. The latency
+	// is the duration of the client span for call edges, which includes
+	// the network, and the duration of the span declaring the link for
+	// link edges.
+	P50 time.Duration
+	P95 time.Duration
+	P99 time.Duration
+}
+
+// ErrorRate returns the share of requests with errors, between 0 and 1.
+func (e *Edge) ErrorRate() float64 {
+	if e.Requests == 0 {
+		return 0
+	}
+	return float64(e.Errors) / float64(e.Requests)
+}
+
+// Builder collects spans incrementally and builds a Map from them. Spans may
+// be added in any order, since edges are only resolved by Map. A Builder is
+// not safe for concurrent use.
+type Builder struct {
+	services map[string]struct{}
+	spans    map[string]*span
+	order    []*span
+}
+
+// span holds what a Builder needs of a span.
+type span struct {
+	service  string
+	kind     dash0.SpanKind
+	parent   string
+	links    []string
+	err      bool
+	duration time.Duration
+}
+
+// NewBuilder returns an empty Builder.
+func NewBuilder() *Builder {
+	return &Builder{
+		services: make(map[string]struct{}),
+		spans:    make(map[string]*span),
+	}
+}
+
+// Build builds a Map from all spans of resourceSpans, e.g. the items of
+// GetSpansIter. It stops at the first error of resourceSpans.
+func Build(resourceSpans iter.Seq2[*dash0.ResourceSpans, error]) (*Map, error) {
+	b := NewBuilder()
+	for rs, err := range resourceSpans {
+		if err != nil {
+			return nil, err
+		}
+		b.AddResourceSpans(rs)
+	}
+	return b.Map(), nil
+}
+
+// AddResourceSpans adds the service of the resource and all of its spans.
+// Spans that were already added are skipped.
+func (b *Builder) AddResourceSpans(rs *dash0.ResourceSpans) {
+	service := rs.Resource.ServiceName()
+	b.services[service] = struct{}{}
+	for i := range rs.ScopeSpans {
+		for j := range rs.ScopeSpans[i].Spans {
+			b.addSpan(service, &rs.ScopeSpans[i].Spans[j])
+		}
+	}
+}
+
+func (b *Builder) addSpan(service string, s *dash0.Span) {
+	id := spanKey(s.TraceId, s.SpanId)
+	if _, ok := b.spans[id]; ok {
+		return
+	}
+	start, end := dash0.UnixNano(s.StartTimeUnixNano), dash0.UnixNano(s.EndTimeUnixNano)
+	sp := &span{
+		service:  service,
+		kind:     s.Kind,
+		err:      s.Status.Code == dash0.SpanStatusCodeError,
+		duration: time.Duration(max(end-start, 0)),
+	}
+	if s.ParentSpanId != nil && len(*s.ParentSpanId) > 0 {
+		sp.parent = spanKey(s.TraceId, *s.ParentSpanId)
+	}
+	for _, link := range s.Links {
+		sp.links = append(sp.links, spanKey(link.TraceId, link.SpanId))
+	}
+	b.spans[id] = sp
+	b.order = append(b.order, sp)
+}
+
+// Map builds the map of the spans added so far.
+func (b *Builder) Map() *Map {
+	nodes := make(map[string]*Node, len(b.services))
+	for service := range b.services {
+		nodes[service] = &Node{Service: service}
+	}
+
+	type edgeKey struct {
+		from, to string
+		kind     EdgeKind
+	}
+	edges := make(map[edgeKey]*Edge)
+	latencies := make(map[*Edge]*stats.Durations)
+	add := func(from, to string, kind EdgeKind, err bool, latency time.Duration) {
+		key := edgeKey{from, to, kind}
+		e, ok := edges[key]
+		if !ok {
+			e = &Edge{From: from, To: to, Kind: kind}
+			edges[key] = e
+			latencies[e] = &stats.Durations{}
+		}
+		e.Requests++
+		if err {
+			e.Errors++
+		}
+		latencies[e].Add(latency)
+	}
+
+	for _, s := range b.order {
+		node := nodes[s.service]
+		node.Spans++
+		if s.err {
+			node.Errors++
+		}
+
+		if parent, ok := b.spans[s.parent]; ok && s.parent != "" && s.kind == dash0.SpanKindServer && parent.kind == dash0.SpanKindClient && parent.service != s.service {
+			add(parent.service, s.service, EdgeCall, parent.err || s.err, parent.duration)
+		}
+		for _, link := range s.links {
+			if linked, ok := b.spans[link]; ok && linked.service != s.service {
+				add(linked.service, s.service, EdgeLink, s.err, s.duration)
+			}
+		}
+	}
+
+	m := &Map{}
+	for _, n := range nodes {
+		m.Nodes = append(m.Nodes, n)
+	}
+	slices.SortFunc(m.Nodes, func(a, b *Node) int {
+		return cmp.Compare(a.Service, b.Service)
+	})
+	for _, e := range edges {
+		l := latencies[e]
+		e.P50, e.P95, e.P99 = l.Percentile(50), l.Percentile(95), l.Percentile(99)
+		m.Edges = append(m.Edges, e)
+	}
+	slices.SortFunc(m.Edges, func(a, b *Edge) int {
+		return cmp.Or(cmp.Compare(a.From, b.From), cmp.Compare(a.To, b.To), cmp.Compare(a.Kind, b.Kind))
+	})
+	return m
+}
+
+// spanKey identifies a span by its trace ID and span ID.
+func spanKey(traceID, spanID []byte) string {
+	return string(traceID) + string(spanID)
+}
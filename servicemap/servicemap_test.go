@@ -0,0 +1,171 @@
+package servicemap
+
+import (
+	"errors"
+	"fmt"
+	"iter"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dash0hq/dash0-api-client-go"
+)
+
+var testTraceID = []byte("0123456789abcdef")
+
+// testSpan returns a span of the test trace with the given kind, parent and
+// duration. IDs are given as short strings.
+func testSpan(id, parent string, kind dash0.SpanKind, duration time.Duration, failed bool) dash0.Span {
+	span := dash0.Span{
+		Name:              id,
+		Kind:              kind,
+		TraceId:           testTraceID,
+		SpanId:            []byte(id),
+		StartTimeUnixNano: "1000",
+		EndTimeUnixNano:   strconv.FormatInt(1000+int64(duration), 10),
+	}
+	if parent != "" {
+		span.ParentSpanId = dash0.Ptr([]byte(parent))
+	}
+	if failed {
+		span.Status.Code = dash0.SpanStatusCodeError
+	}
+	return span
+}
+
+func testResourceSpans(service string, spans ...dash0.Span) dash0.ResourceSpans {
+	return dash0.ResourceSpans{
+		Resource:   dash0.Resource{Attributes: []dash0.KeyValue{{Key: "service.name", Value: dash0.AnyValue{StringValue: dash0.String(service)}}}},
+		ScopeSpans: []dash0.ScopeSpans{{Spans: spans}},
+	}
+}
+
+func seq(resourceSpans []dash0.ResourceSpans, err error) iter.Seq2[*dash0.ResourceSpans, error] {
+	return func(yield func(*dash0.ResourceSpans, error) bool) {
+		for i := range resourceSpans {
+			if !yield(&resourceSpans[i], nil) {
+				return
+			}
+		}
+		if err != nil {
+			yield(nil, err)
+		}
+	}
+}
+
+func testMap(t *testing.T) *Map {
+	t.Helper()
+	consumer := testSpan("consume", "", 5, 30*time.Millisecond, false)
+	consumer.Links = []dash0.SpanLink{{TraceId: testTraceID, SpanId: []byte("publish")}}
+
+	m, err := Build(seq([]dash0.ResourceSpans{
+		// server spans before their client spans
+		testResourceSpans("checkout",
+			testSpan("s1", "c1", dash0.SpanKindServer, 80*time.Millisecond, false),
+			testSpan("s2", "c2", dash0.SpanKindServer, 150*time.Millisecond, true),
+			testSpan("c3", "s1", dash0.SpanKindClient, 20*time.Millisecond, false),
+			testSpan("c4", "s1", dash0.SpanKindClient, 5*time.Millisecond, false),
+			testSpan("s4", "c4", dash0.SpanKindServer, 4*time.Millisecond, false),
+			testSpan("publish", "s2", 4, time.Millisecond, false),
+		),
+		testResourceSpans("frontend",
+			testSpan("c1", "", dash0.SpanKindClient, 100*time.Millisecond, false),
+			testSpan("c2", "", dash0.SpanKindClient, 200*time.Millisecond, false),
+			testSpan("c5", "", dash0.SpanKindClient, time.Millisecond, false),
+		),
+		testResourceSpans("payment", testSpan("s3", "c3", dash0.SpanKindServer, 15*time.Millisecond, true)),
+		testResourceSpans("shipping", consumer),
+		testResourceSpans("frontend", testSpan("c1", "", dash0.SpanKindClient, 100*time.Millisecond, false)),
+		testResourceSpans("idle"),
+	}, nil))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return m
+}
+
+func TestBuild(t *testing.T) {
+	m := testMap(t)
+
+	var nodes []string
+	for _, n := range m.Nodes {
+		nodes = append(nodes, fmt.Sprintf("%s %d/%d", n.Service, n.Errors, n.Spans))
+	}
+	if got := strings.Join(nodes, ", "); got != "checkout 1/6, frontend 0/3, idle 0/0, payment 1/1, shipping 0/1" {
+		t.Errorf("unexpected nodes %s", got)
+	}
+
+	var edges []string
+	for _, e := range m.Edges {
+		edges = append(edges, fmt.Sprintf("%s->%s %s %d/%d p50=%v p99=%v", e.From, e.To, e.Kind, e.Errors, e.Requests, e.P50, e.P99))
+	}
+	want := []string{
+		"checkout->payment call 1/1 p50=20ms p99=20ms",
+		"checkout->shipping link 0/1 p50=30ms p99=30ms",
+		"frontend->checkout call 1/2 p50=100ms p99=200ms",
+	}
+	if got := strings.Join(edges, "\n"); got != strings.Join(want, "\n") {
+		t.Errorf("expected edges\n%s\ngot\n%s", strings.Join(want, "\n"), got)
+	}
+	if rate := m.Edges[2].ErrorRate(); rate != 0.5 {
+		t.Errorf("expected error rate 0.5, got %v", rate)
+	}
+
+	t.Run("returns iteration errors", func(t *testing.T) {
+		errFetch := errors.New("fetch failed")
+		if _, err := Build(seq(nil, errFetch)); !errors.Is(err, errFetch) {
+			t.Errorf("expected iteration error, got %v", err)
+		}
+	})
+}
+
+func TestWriteDOT(t *testing.T) {
+	var b strings.Builder
+	if err := testMap(t).WriteDOT(&b); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := `digraph services {
+	rankdir=LR;
+	node [shape=box];
+	"checkout";
+	"frontend";
+	"idle";
+	"payment";
+	"shipping";
+	"checkout" -> "payment" [label="1 req, 100.0% err\np50 20ms, p95 20ms, p99 20ms"];
+	"checkout" -> "shipping" [label="1 req, 0.0% err\np50 30ms, p95 30ms, p99 30ms", style=dashed];
+	"frontend" -> "checkout" [label="2 req, 50.0% err\np50 100ms, p95 200ms, p99 200ms"];
+}
+`
+	if b.String() != want {
+		t.Errorf("expected\n%s\ngot\n%s", want, b.String())
+	}
+}
+
+func TestWriteMermaid(t *testing.T) {
+	var b strings.Builder
+	if err := testMap(t).WriteMermaid(&b); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := `flowchart LR
+    n0["checkout"]
+    n1["frontend"]
+    n2["idle"]
+    n3["payment"]
+    n4["shipping"]
+    n0 -->|"1 req, 100.0% err<br/>p50 20ms, p95 20ms, p99 20ms"| n3
+    n0 -.->|"1 req, 0.0% err<br/>p50 30ms, p95 30ms, p99 30ms"| n4
+    n1 -->|"2 req, 50.0% err<br/>p50 100ms, p95 200ms, p99 200ms"| n0
+`
+	if b.String() != want {
+		t.Errorf("expected\n%s\ngot\n%s", want, b.String())
+	}
+
+	if got := mermaidEscape(`say "hi"`); got != "say #quot;hi#quot;" {
+		t.Errorf("unexpected escaping %s", got)
+	}
+	if got := formatLatency(1234567 * time.Nanosecond); got != "1.23ms" {
+		t.Errorf("unexpected latency %s", got)
+	}
+}
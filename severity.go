@@ -0,0 +1,45 @@
+package dash0
+
+import (
+	"strconv"
+	"time"
+)
+
+// severityNames are the short names of the severity ranges of the
+// OpenTelemetry log data model, each covering four severity numbers.
+var severityNames = [...]string{"TRACE", "DEBUG", "INFO", "WARN", "ERROR", "FATAL"}
+
+// SeverityName returns the short name of a severity number as defined by the
+// OpenTelemetry log data model, e.g. "ERROR" for 17 and "ERROR2" for 18. It
+// returns "UNSPECIFIED" for 0 and numbers outside of the defined range.
+func SeverityName(n SeverityNumber) string {
+	if n < 1 || n > 4*int32(len(severityNames)) {
+		return "UNSPECIFIED"
+	}
+	name := severityNames[(n-1)/4]
+	if offset := (n - 1) % 4; offset > 0 {
+		name += strconv.Itoa(int(offset) + 1)
+	}
+	return name
+}
+
+// LogRecordSeverity returns the severity name of a log record, falling back to
+// its severity text if the severity number is not set.
+func LogRecordSeverity(record *LogRecord) string {
+	if record.SeverityNumber != nil && *record.SeverityNumber != 0 {
+		return SeverityName(*record.SeverityNumber)
+	}
+	if text := StringValue(record.SeverityText); text != "" {
+		return text
+	}
+	return SeverityName(0)
+}
+
+// LogRecordTime returns the time of a log record, falling back to its observed
+// time if the time is not set. It returns the zero time if neither is set.
+func LogRecordTime(record *LogRecord) time.Time {
+	if n := logRecordUnixNano(record); n != 0 {
+		return time.Unix(0, n).UTC()
+	}
+	return time.Time{}
+}
@@ -0,0 +1,47 @@
+package dash0
+
+import "testing"
+
+func TestSeverityName(t *testing.T) {
+	for n, want := range map[SeverityNumber]string{
+		0:  "UNSPECIFIED",
+		1:  "TRACE",
+		4:  "TRACE4",
+		9:  "INFO",
+		14: "WARN2",
+		17: "ERROR",
+		24: "FATAL4",
+		25: "UNSPECIFIED",
+		-1: "UNSPECIFIED",
+	} {
+		if got := SeverityName(n); got != want {
+			t.Errorf("SeverityName(%d) = %s, want %s", n, got, want)
+		}
+	}
+}
+
+func TestLogRecordSeverity(t *testing.T) {
+	record := &LogRecord{SeverityText: String("warning")}
+	if got := LogRecordSeverity(record); got != "warning" {
+		t.Errorf("expected severity text fallback, got %s", got)
+	}
+	record.SeverityNumber = Ptr(SeverityNumber(14))
+	if got := LogRecordSeverity(record); got != "WARN2" {
+		t.Errorf("expected severity name, got %s", got)
+	}
+	if got := LogRecordSeverity(&LogRecord{}); got != "UNSPECIFIED" {
+		t.Errorf("expected UNSPECIFIED, got %s", got)
+	}
+}
+
+func TestLogRecordTime(t *testing.T) {
+	if got := LogRecordTime(&LogRecord{ObservedTimeUnixNano: "1705329000000000000"}); got.Format("2006-01-02T15:04:05Z07:00") != "2024-01-15T14:30:00Z" {
+		t.Errorf("expected observed time fallback, got %v", got)
+	}
+	if got := LogRecordTime(&LogRecord{TimeUnixNano: "1705329000000000000", ObservedTimeUnixNano: "1"}); got.UnixNano() != 1705329000000000000 {
+		t.Errorf("expected time, got %v", got)
+	}
+	if got := LogRecordTime(&LogRecord{}); !got.IsZero() {
+		t.Errorf("expected zero time, got %v", got)
+	}
+}
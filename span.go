@@ -0,0 +1,18 @@
+package dash0
+
+// Span kinds of the OTLP data model.
+const (
+	SpanKindUnspecified SpanKind = 0
+	SpanKindInternal    SpanKind = 1
+	SpanKindServer      SpanKind = 2
+	SpanKindClient      SpanKind = 3
+	SpanKindProducer    SpanKind = 4
+	SpanKindConsumer    SpanKind = 5
+)
+
+// Span status codes of the OTLP data model.
+const (
+	SpanStatusCodeUnset SpanStatusCode = 0
+	SpanStatusCodeOK    SpanStatusCode = 1
+	SpanStatusCodeError SpanStatusCode = 2
+)
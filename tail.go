@@ -0,0 +1,244 @@
+package dash0
+
+import (
+	"cmp"
+	"context"
+	"encoding/hex"
+	"slices"
+	"strconv"
+	"time"
+)
+
+const (
+	// DefaultTailInterval is the default time between two polls of a tail.
+	DefaultTailInterval = 5 * time.Second
+
+	// DefaultTailOverlap is the default time by which the window of a poll
+	// reaches back before the end of the previous window, to pick up records
+	// that arrive late.
+	DefaultTailOverlap = time.Minute
+)
+
+// TailOption configures tails such as TailLogRecords and TailSpans.
+type TailOption func(*tailConfig)
+
+type tailConfig struct {
+	interval time.Duration
+	overlap  time.Duration
+	maxWait  time.Duration
+	now      func() time.Time
+}
+
+// WithTailInterval sets the time between two polls. Defaults to
+// DefaultTailInterval.
+func WithTailInterval(interval time.Duration) TailOption {
+	return func(c *tailConfig) {
+		c.interval = interval
+	}
+}
+
+// WithTailOverlap sets how far each poll reaches back before the end of the
+// previous poll. Records that arrive later than this are missed. Defaults to
+// DefaultTailOverlap.
+func WithTailOverlap(overlap time.Duration) TailOption {
+	return func(c *tailConfig) {
+		c.overlap = overlap
+	}
+}
+
+// LogRecordEntry is a log record together with the resource and scope it
+// belongs to.
+type LogRecordEntry struct {
+	Resource  *Resource
+	Scope     *InstrumentationScope
+	LogRecord *LogRecord
+}
+
+// SpanEntry is a span together with the resource and scope it belongs to.
+type SpanEntry struct {
+	Resource *Resource
+	Scope    *InstrumentationScope
+	Span     *Span
+}
+
+// tailFetchFunc fetches all items of a window.
+type tailFetchFunc[T any] func(ctx context.Context, window timeWindow) ([]T, error)
+
+// tailer polls a sliding time window and emits items it has not seen before.
+type tailer[T any] struct {
+	config    *tailConfig
+	fetch     tailFetchFunc[T]
+	key       func(T) string
+	timestamp func(T) int64
+
+	// seen holds the keys of the items returned by the previous poll.
+	seen map[string]struct{}
+}
+
+// run polls until ctx is done or fn returns an error. The first poll covers r,
+// and every further poll covers the time from the end of the previous window
+// minus the overlap until now.
+func (t *tailer[T]) run(ctx context.Context, r TimeRange, fn func(T) error) error {
+	t.seen = make(map[string]struct{})
+	window := timeWindow{from: r.From, to: r.To}
+	failures := 0
+	for {
+		items, err := t.fetch(ctx, window)
+		wait := t.config.interval
+		switch {
+		case ctx.Err() != nil:
+			return ctx.Err()
+		case err != nil && !IsRateLimited(err) && !IsServerError(err):
+			return err
+		case err != nil:
+			// The client has already retried the request, so back off
+			// further before polling the same window again.
+			failures++
+			wait = min(t.config.interval<<min(failures, 16), max(t.config.maxWait, t.config.interval))
+		default:
+			failures = 0
+			if err := t.emit(items, fn); err != nil {
+				return err
+			}
+			window.from = window.to.Add(-t.config.overlap)
+			if window.from.Before(r.From) {
+				window.from = r.From
+			}
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+		if now := t.config.now(); now.After(window.to) {
+			window.to = now
+		}
+	}
+}
+
+// emit passes the items that the previous poll did not return to fn, ordered
+// by their timestamps. Windows only move forward, so an item missing from a
+// poll is never returned again and only the keys of the last poll need to be
+// kept.
+func (t *tailer[T]) emit(items []T, fn func(T) error) error {
+	slices.SortStableFunc(items, func(a, b T) int {
+		return cmp.Compare(t.timestamp(a), t.timestamp(b))
+	})
+	seen := make(map[string]struct{}, len(items))
+	for _, item := range items {
+		key := t.key(item)
+		if _, ok := seen[key]; ok {
+			continue
+		}
+		seen[key] = struct{}{}
+		if _, ok := t.seen[key]; ok {
+			continue
+		}
+		if err := fn(item); err != nil {
+			return err
+		}
+	}
+	t.seen = seen
+	return nil
+}
+
+// newTailConfig applies the options on top of the defaults.
+func newTailConfig(maxWait time.Duration, opts []TailOption) *tailConfig {
+	cfg := &tailConfig{
+		interval: DefaultTailInterval,
+		overlap:  DefaultTailOverlap,
+		maxWait:  maxWait,
+		now:      time.Now,
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	if cfg.interval <= 0 {
+		cfg.interval = DefaultTailInterval
+	}
+	if cfg.overlap < 0 {
+		cfg.overlap = 0
+	}
+	return cfg
+}
+
+// logRecordEntries flattens resource logs into entries.
+func logRecordEntries(resourceLogs []*ResourceLogs) []*LogRecordEntry {
+	var entries []*LogRecordEntry
+	for _, rl := range resourceLogs {
+		for i := range rl.ScopeLogs {
+			sl := &rl.ScopeLogs[i]
+			for j := range sl.LogRecords {
+				entries = append(entries, &LogRecordEntry{Resource: &rl.Resource, Scope: sl.Scope, LogRecord: &sl.LogRecords[j]})
+			}
+		}
+	}
+	return entries
+}
+
+// logRecordKey identifies a log record across polls by its ID. Records without
+// an ID are identified by their timestamps, trace context, severity, body and
+// service name.
+func logRecordKey(e *LogRecordEntry) string {
+	r := e.LogRecord
+	if id := StringValue(r.Id); id != "" {
+		return id
+	}
+	if id := StringValue(r.Dash0EventId); id != "" {
+		return id
+	}
+	key := r.TimeUnixNano + "|" + r.ObservedTimeUnixNano + "|" + e.Resource.ServiceName()
+	if r.TraceId != nil {
+		key += "|" + hex.EncodeToString(*r.TraceId)
+	}
+	if r.SpanId != nil {
+		key += "|" + hex.EncodeToString(*r.SpanId)
+	}
+	if r.SeverityNumber != nil {
+		key += "|" + strconv.Itoa(int(*r.SeverityNumber))
+	}
+	if r.Body != nil {
+		key += "|" + r.Body.String()
+	}
+	return key
+}
+
+// logRecordTimestamp returns the time of a log record in nanoseconds, falling
+// back to the observed time if the time is not set.
+func logRecordTimestamp(e *LogRecordEntry) int64 {
+	return logRecordUnixNano(e.LogRecord)
+}
+
+func logRecordUnixNano(r *LogRecord) int64 {
+	if t := UnixNano(r.TimeUnixNano); t != 0 {
+		return t
+	}
+	return UnixNano(r.ObservedTimeUnixNano)
+}
+
+// spanEntries flattens resource spans into entries.
+func spanEntries(resourceSpans []*ResourceSpans) []*SpanEntry {
+	var entries []*SpanEntry
+	for _, rs := range resourceSpans {
+		for i := range rs.ScopeSpans {
+			ss := &rs.ScopeSpans[i]
+			for j := range ss.Spans {
+				entries = append(entries, &SpanEntry{Resource: &rs.Resource, Scope: ss.Scope, Span: &ss.Spans[j]})
+			}
+		}
+	}
+	return entries
+}
+
+// spanKey identifies a span across polls by its trace ID and span ID.
+func spanKey(e *SpanEntry) string {
+	return hex.EncodeToString(e.Span.TraceId) + hex.EncodeToString(e.Span.SpanId)
+}
+
+// spanTimestamp returns the start time of a span in nanoseconds.
+func spanTimestamp(e *SpanEntry) int64 {
+	return UnixNano(e.Span.StartTimeUnixNano)
+}
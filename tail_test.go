@@ -0,0 +1,230 @@
+package dash0
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+// tailServer serves log records for tail tests. respond returns the status
+// code and log records of the n-th request, counting from 0. If spans is set,
+// it is called instead to serve spans.
+type tailServer struct {
+	t       *testing.T
+	respond func(n int) (int, []LogRecord)
+	spans   func(n int) []Span
+
+	mu      sync.Mutex
+	ranges  []TimeRange
+	filters []*FilterCriteria
+}
+
+func (s *tailServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		TimeRange TimeRange       `json:"timeRange"`
+		Filter    *FilterCriteria `json:"filter"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s.t.Errorf("failed to decode request: %v", err)
+	}
+	s.mu.Lock()
+	n := len(s.ranges)
+	s.ranges = append(s.ranges, req.TimeRange)
+	s.filters = append(s.filters, req.Filter)
+	s.mu.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	if s.spans != nil {
+		_ = json.NewEncoder(w).Encode(GetSpansResponse{ResourceSpans: []ResourceSpans{{
+			Resource:   Resource{Attributes: []KeyValue{stringAttribute("service.name", "checkout")}},
+			ScopeSpans: []ScopeSpans{{Spans: s.spans(n)}},
+		}}})
+		return
+	}
+	status, records := s.respond(n)
+	w.WriteHeader(status)
+	if status != http.StatusOK {
+		_, _ = w.Write([]byte(`{"error":{"message":"failed"}}`))
+		return
+	}
+	_ = json.NewEncoder(w).Encode(GetLogRecordsResponse{ResourceLogs: []ResourceLogs{{
+		Resource:  Resource{Attributes: []KeyValue{stringAttribute("service.name", "checkout")}},
+		ScopeLogs: []ScopeLogs{{LogRecords: records}},
+	}}})
+}
+
+func (s *tailServer) requests() []TimeRange {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]TimeRange(nil), s.ranges...)
+}
+
+func tailLogRecord(id, timeUnixNano, body string) LogRecord {
+	record := LogRecord{TimeUnixNano: timeUnixNano, Body: &AnyValue{StringValue: String(body)}}
+	if id != "" {
+		record.Id = String(id)
+	}
+	return record
+}
+
+func tailBodies(t *testing.T, client Client, ctx context.Context, limit int, opts ...TailOption) ([]string, error) {
+	t.Helper()
+	var bodies []string
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	err := client.TailLogRecords(ctx, &GetLogRecordsRequest{TimeRange: TimeRangeLast(5 * time.Minute)}, func(e *LogRecordEntry) error {
+		if e.Resource.ServiceName() != "checkout" {
+			t.Errorf("expected resource of the record, got %v", e.Resource)
+		}
+		bodies = append(bodies, e.LogRecord.Body.String())
+		if len(bodies) == limit {
+			cancel()
+		}
+		return nil
+	}, append([]TailOption{WithTailInterval(10 * time.Millisecond)}, opts...)...)
+	return bodies, err
+}
+
+func TestTailLogRecords(t *testing.T) {
+	t.Run("emits new records in order", func(t *testing.T) {
+		server := &tailServer{t: t, respond: func(n int) (int, []LogRecord) {
+			switch n {
+			case 0:
+				return http.StatusOK, []LogRecord{tailLogRecord("b", "3", "b"), tailLogRecord("a", "1", "a")}
+			case 1:
+				// b again, and c arrived late
+				return http.StatusOK, []LogRecord{tailLogRecord("b", "3", "b"), tailLogRecord("c", "2", "c")}
+			default:
+				// d has no ID and is identified by its content
+				return http.StatusOK, []LogRecord{tailLogRecord("", "5", "d")}
+			}
+		}}
+		client := newWindowTestClient(t, server)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+		defer cancel()
+		bodies, err := tailBodies(t, client, ctx, 0, WithTailOverlap(time.Minute))
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("expected deadline exceeded, got %v", err)
+		}
+		if want := []string{"a", "b", "c", "d"}; !slices.Equal(bodies, want) {
+			t.Errorf("expected %v, got %v", want, bodies)
+		}
+
+		requests := server.requests()
+		if len(requests) < 3 {
+			t.Fatalf("expected at least 3 polls, got %d", len(requests))
+		}
+		if got := requests[0].To.Sub(requests[0].From); got != 5*time.Minute {
+			t.Errorf("expected first poll to cover the request's time range, got %v", got)
+		}
+		if !requests[1].From.Equal(requests[0].To.Add(-time.Minute)) || !requests[1].To.After(requests[0].To) {
+			t.Errorf("expected second poll to overlap the first by a minute, got %v after %v", requests[1], requests[0])
+		}
+	})
+
+	t.Run("stops when fn fails", func(t *testing.T) {
+		server := &tailServer{t: t, respond: func(int) (int, []LogRecord) {
+			return http.StatusOK, []LogRecord{tailLogRecord("a", "1", "a")}
+		}}
+		client := newWindowTestClient(t, server)
+
+		errStop := errors.New("stop")
+		err := client.TailLogRecords(context.Background(), &GetLogRecordsRequest{TimeRange: TimeRangeLast(time.Minute)}, func(*LogRecordEntry) error {
+			return errStop
+		})
+		if !errors.Is(err, errStop) {
+			t.Errorf("expected fn error, got %v", err)
+		}
+	})
+
+	t.Run("backs off on rate limiting", func(t *testing.T) {
+		server := &tailServer{t: t, respond: func(n int) (int, []LogRecord) {
+			if n < 2 {
+				return http.StatusTooManyRequests, nil
+			}
+			return http.StatusOK, []LogRecord{tailLogRecord("a", "1", "a")}
+		}}
+		client := newWindowTestClient(t, server, WithMaxRetries(0))
+
+		bodies, err := tailBodies(t, client, context.Background(), 1)
+		if !errors.Is(err, context.Canceled) || !slices.Equal(bodies, []string{"a"}) {
+			t.Errorf("expected record after rate limiting, got %v (%v)", bodies, err)
+		}
+		if requests := server.requests(); !requests[2].From.Equal(requests[0].From) {
+			t.Errorf("expected the failed window to be polled again, got %v", requests)
+		}
+	})
+
+	t.Run("returns client errors", func(t *testing.T) {
+		server := &tailServer{t: t, respond: func(int) (int, []LogRecord) {
+			return http.StatusBadRequest, nil
+		}}
+		client := newWindowTestClient(t, server)
+
+		if _, err := tailBodies(t, client, context.Background(), 1); !IsBadRequest(err) {
+			t.Errorf("expected bad request error, got %v", err)
+		}
+	})
+
+	t.Run("rejects invalid time ranges", func(t *testing.T) {
+		client := newWindowTestClient(t, http.NotFoundHandler())
+		err := client.TailLogRecords(context.Background(), &GetLogRecordsRequest{TimeRange: TimeReferenceRange{From: "yesterday", To: "now"}}, func(*LogRecordEntry) error {
+			return nil
+		})
+		if err == nil {
+			t.Error("expected error for invalid time range")
+		}
+	})
+}
+
+func TestTailSpans(t *testing.T) {
+	root := testSpan("01", "", 1000)
+	child := testSpan("02", "01", 2000)
+	// The same span ID in another trace is a different span
+	other := testSpan("02", "01", 3000)
+	other.TraceId = mustDecodeHex("0af7651916cd43dd8448eb211c80319c")
+
+	server := &tailServer{t: t, spans: func(n int) []Span {
+		if n == 0 {
+			return []Span{child, root}
+		}
+		return []Span{child, other}
+	}}
+	client := newWindowTestClient(t, server)
+
+	filter, err := ParseFilter(`otel.span.status.code = 2`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var names []string
+	err = client.TailSpans(ctx, &GetSpansRequest{TimeRange: TimeRangeLast(time.Minute), Filter: &filter}, func(e *SpanEntry) error {
+		names = append(names, e.Span.Name+"@"+hex.EncodeToString(e.Span.TraceId)[:4])
+		if len(names) == 3 {
+			cancel()
+		}
+		return nil
+	}, WithTailInterval(10*time.Millisecond))
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context canceled, got %v", err)
+	}
+	if want := []string{"01@4bf9", "02@4bf9", "02@0af7"}; !slices.Equal(names, want) {
+		t.Errorf("expected %v, got %v", want, names)
+	}
+
+	server.mu.Lock()
+	defer server.mu.Unlock()
+	for i, f := range server.filters {
+		if f == nil || len(*f) != 1 {
+			t.Errorf("expected filter to be sent with request %d, got %v", i, f)
+		}
+	}
+}
@@ -0,0 +1,176 @@
+package dash0
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// NewRelativeTime returns a RelativeTime that is offset from now, e.g. "now-15m"
+// for -15*time.Minute or "now" for 0. The offset is expressed in the largest
+// unit out of weeks, days, hours, minutes and seconds that represents it
+// exactly. Fractions of a second are truncated.
+func NewRelativeTime(offset time.Duration) RelativeTime {
+	seconds := int64(offset / time.Second)
+	if seconds == 0 {
+		return "now"
+	}
+
+	sign := "+"
+	if seconds < 0 {
+		sign = "-"
+		seconds = -seconds
+	}
+	for _, u := range []struct {
+		unit    string
+		seconds int64
+	}{
+		{"w", 7 * 24 * 3600},
+		{"d", 24 * 3600},
+		{"h", 3600},
+		{"m", 60},
+	} {
+		if seconds%u.seconds == 0 {
+			return fmt.Sprintf("now%s%d%s", sign, seconds/u.seconds, u.unit)
+		}
+	}
+	return fmt.Sprintf("now%s%ds", sign, seconds)
+}
+
+// NewFixedTime returns t as a FixedTime in UTC.
+func NewFixedTime(t time.Time) FixedTime {
+	return t.UTC()
+}
+
+// NewFixedTimeUnix returns t as a FixedTimeUnix, i.e. the seconds since the
+// Unix epoch with the nanoseconds as decimal places, e.g. "1705329000.5".
+func NewFixedTimeUnix(t time.Time) FixedTimeUnix {
+	s := strconv.FormatInt(t.Unix(), 10)
+	if nanos := t.Nanosecond(); nanos != 0 {
+		s += strings.TrimRight(fmt.Sprintf(".%09d", nanos), "0")
+	}
+	return s
+}
+
+// TimeRangeLast returns the range from d ago until now, e.g. now-1h to now.
+func TimeRangeLast(d time.Duration) TimeReferenceRange {
+	return TimeReferenceRange{From: NewRelativeTime(-d), To: NewRelativeTime(0)}
+}
+
+var (
+	relativeTimePattern = regexp.MustCompile(`^now(?:([+-])(\d+)([a-zA-Z]+))?$`)
+	unixTimePattern     = regexp.MustCompile(`^(\d+)(?:\.(\d{1,9}))?$`)
+)
+
+// ParseTimeReference resolves a TimeReference to a concrete time, using now
+// for relative times. It accepts a RelativeTime (e.g. "now-15m"), an RFC 3339
+// string, a FixedTimeUnix string, a time.Time or a *time.Time.
+//
+// Relative times support the units s, m, h, d, w and M (months).
+func ParseTimeReference(ref TimeReference, now time.Time) (time.Time, error) {
+	t, err := parseTimeReference(ref, now)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("dash0: %w", err)
+	}
+	return t, nil
+}
+
+func parseTimeReference(ref TimeReference, now time.Time) (time.Time, error) {
+	switch ref := ref.(type) {
+	case time.Time:
+		return ref, nil
+	case *time.Time:
+		if ref == nil {
+			return time.Time{}, errors.New("invalid time reference: missing")
+		}
+		return *ref, nil
+	case string:
+		return parseTimeReferenceString(ref, now)
+	case nil:
+		return time.Time{}, errors.New("invalid time reference: missing")
+	default:
+		return time.Time{}, fmt.Errorf("invalid time reference: unsupported type %T", ref)
+	}
+}
+
+func parseTimeReferenceString(s string, now time.Time) (time.Time, error) {
+	if strings.HasPrefix(s, "now") {
+		m := relativeTimePattern.FindStringSubmatch(s)
+		if m == nil {
+			return time.Time{}, fmt.Errorf("invalid time reference %q: expected now[+/-duration], e.g. now-15m", s)
+		}
+		if m[1] == "" {
+			return now, nil
+		}
+		n, err := strconv.Atoi(m[2])
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid time reference %q: %w", s, err)
+		}
+		if m[1] == "-" {
+			n = -n
+		}
+		switch m[3] {
+		case "s":
+			return now.Add(time.Duration(n) * time.Second), nil
+		case "m":
+			return now.Add(time.Duration(n) * time.Minute), nil
+		case "h":
+			return now.Add(time.Duration(n) * time.Hour), nil
+		case "d":
+			return now.AddDate(0, 0, n), nil
+		case "w":
+			return now.AddDate(0, 0, 7*n), nil
+		case "M":
+			return now.AddDate(0, n, 0), nil
+		default:
+			return time.Time{}, fmt.Errorf("invalid time reference %q: unknown unit %q, expected one of s, m, h, d, w, M", s, m[3])
+		}
+	}
+
+	if m := unixTimePattern.FindStringSubmatch(s); m != nil {
+		seconds, err := strconv.ParseInt(m[1], 10, 64)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid time reference %q: %w", s, err)
+		}
+		var nanos int64
+		if m[2] != "" {
+			nanos, _ = strconv.ParseInt(m[2]+strings.Repeat("0", 9-len(m[2])), 10, 64)
+		}
+		return time.Unix(seconds, nanos).UTC(), nil
+	}
+
+	t, err := time.Parse(time.RFC3339Nano, s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid time reference %q: expected a relative time, an RFC 3339 time or a unix time", s)
+	}
+	return t, nil
+}
+
+// ResolveTimeRange resolves both ends of a TimeReferenceRange to concrete
+// times, using now for relative times. It returns an error if either end is
+// invalid or if From is after To.
+func ResolveTimeRange(r TimeReferenceRange, now time.Time) (TimeRange, error) {
+	from, err := parseTimeReference(r.From, now)
+	if err != nil {
+		return TimeRange{}, fmt.Errorf("dash0: invalid time range: from: %w", err)
+	}
+	to, err := parseTimeReference(r.To, now)
+	if err != nil {
+		return TimeRange{}, fmt.Errorf("dash0: invalid time range: to: %w", err)
+	}
+	if from.After(to) {
+		return TimeRange{}, fmt.Errorf("dash0: invalid time range: from %s is after to %s", from.Format(time.RFC3339), to.Format(time.RFC3339))
+	}
+	return TimeRange{From: from, To: to}, nil
+}
+
+// ValidateTimeRange checks that both ends of a TimeReferenceRange are valid
+// and that From is not after To. GetSpans and GetLogRecords call it before
+// sending a request.
+func ValidateTimeRange(r TimeReferenceRange) error {
+	_, err := ResolveTimeRange(r, time.Now())
+	return err
+}
@@ -0,0 +1,141 @@
+package dash0
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestNewRelativeTime(t *testing.T) {
+	tests := []struct {
+		offset time.Duration
+		want   string
+	}{
+		{0, "now"},
+		{-15 * time.Minute, "now-15m"},
+		{-90 * time.Second, "now-90s"},
+		{time.Hour, "now+1h"},
+		{-48 * time.Hour, "now-2d"},
+		{-14 * 24 * time.Hour, "now-2w"},
+		{-1500 * time.Millisecond, "now-1s"},
+	}
+	for _, tt := range tests {
+		if got := NewRelativeTime(tt.offset); got != tt.want {
+			t.Errorf("NewRelativeTime(%s): expected %q, got %q", tt.offset, tt.want, got)
+		}
+	}
+}
+
+func TestNewFixedTimeUnix(t *testing.T) {
+	if got := NewFixedTimeUnix(time.Unix(1705329000, 500000000)); got != "1705329000.5" {
+		t.Errorf("unexpected value: %s", got)
+	}
+	if got := NewFixedTimeUnix(time.Unix(1705329000, 0)); got != "1705329000" {
+		t.Errorf("unexpected value: %s", got)
+	}
+}
+
+func TestParseTimeReference(t *testing.T) {
+	now := time.Date(2024, 1, 31, 12, 0, 0, 0, time.UTC)
+
+	t.Run("resolves valid references", func(t *testing.T) {
+		fixed := time.Date(2024, 1, 15, 14, 30, 0, 0, time.UTC)
+		tests := []struct {
+			ref  TimeReference
+			want time.Time
+		}{
+			{"now", now},
+			{"now-30s", now.Add(-30 * time.Second)},
+			{"now-15m", now.Add(-15 * time.Minute)},
+			{"now+2h", now.Add(2 * time.Hour)},
+			{"now-1d", now.AddDate(0, 0, -1)},
+			{"now-1w", now.AddDate(0, 0, -7)},
+			{"now-1M", now.AddDate(0, -1, 0)},
+			{NewRelativeTime(-time.Hour), now.Add(-time.Hour)},
+			{"2024-01-15T14:30:00Z", fixed},
+			{"2024-01-15T22:30:00+08:00", fixed},
+			{"1705329000", fixed},
+			{NewFixedTimeUnix(fixed.Add(250 * time.Millisecond)), fixed.Add(250 * time.Millisecond)},
+			{NewFixedTime(fixed), fixed},
+			{&fixed, fixed},
+		}
+		for _, tt := range tests {
+			got, err := ParseTimeReference(tt.ref, now)
+			if err != nil {
+				t.Errorf("ParseTimeReference(%v): unexpected error: %v", tt.ref, err)
+				continue
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("ParseTimeReference(%v): expected %s, got %s", tt.ref, tt.want, got)
+			}
+		}
+	})
+
+	t.Run("rejects invalid references", func(t *testing.T) {
+		for _, ref := range []TimeReference{"now-1hr", "now-", "now-h", "nowish", "yesterday", "2024-01-15", "", nil, 42} {
+			if _, err := ParseTimeReference(ref, now); err == nil {
+				t.Errorf("ParseTimeReference(%#v): expected error", ref)
+			}
+		}
+	})
+}
+
+func TestResolveTimeRange(t *testing.T) {
+	now := time.Date(2024, 1, 31, 12, 0, 0, 0, time.UTC)
+
+	t.Run("resolves both ends", func(t *testing.T) {
+		r, err := ResolveTimeRange(TimeRangeLast(time.Hour), now)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !r.From.Equal(now.Add(-time.Hour)) || !r.To.Equal(now) {
+			t.Errorf("unexpected range: %+v", r)
+		}
+	})
+
+	t.Run("rejects inverted ranges", func(t *testing.T) {
+		if _, err := ResolveTimeRange(TimeReferenceRange{From: "now", To: "now-1h"}, now); err == nil {
+			t.Error("expected error")
+		}
+	})
+
+	t.Run("reports the invalid end", func(t *testing.T) {
+		_, err := ResolveTimeRange(TimeReferenceRange{From: "now-1h", To: "now-1hr"}, now)
+		want := `dash0: invalid time range: to: invalid time reference "now-1hr": unknown unit "hr", expected one of s, m, h, d, w, M`
+		if err == nil || err.Error() != want {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+}
+
+func TestClient_ValidatesTimeRange(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+	}))
+	defer server.Close()
+
+	client, err := NewClient(WithApiUrl(server.URL), WithAuthToken("auth_test123"))
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	_, err = client.GetSpans(context.Background(), &GetSpansRequest{TimeRange: TimeReferenceRange{From: "now-1hr", To: "now"}})
+	if err == nil {
+		t.Error("expected GetSpans to fail")
+	}
+	_, err = client.GetLogRecords(context.Background(), &GetLogRecordsRequest{})
+	if err == nil {
+		t.Error("expected GetLogRecords to fail")
+	}
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		t.Error("expected a validation error, not an API error")
+	}
+	if requests != 0 {
+		t.Errorf("expected no requests to be sent, got %d", requests)
+	}
+}
@@ -0,0 +1,348 @@
+package dash0
+
+import (
+	"cmp"
+	"context"
+	"encoding/hex"
+	"fmt"
+	"iter"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+)
+
+// GetTrace fetches all spans of a trace within the time range and assembles
+// them into a span tree. The trace ID is given in hexadecimal notation.
+// All pages of spans are fetched before the trace is returned.
+//
+// If no spans of the trace are found, GetTrace returns an error for which
+// IsNotFound is true.
+//
+// Example:
+//
+//	trace, err := client.GetTrace(ctx, "4bf92f3577b34da6a3ce929d0e0e4736", dash0.TimeRangeLast(time.Hour), nil)
+//	if err != nil {
+//	    // handle error
+//	}
+//	for node := range trace.DepthFirst() {
+//	    fmt.Printf("%*s%s\n", 2*node.Depth, "", node.Span.Name)
+//	}
+func (c *client) GetTrace(ctx context.Context, traceID string, timeRange TimeReferenceRange, dataset *string) (*Trace, error) {
+	id, err := hex.DecodeString(traceID)
+	if err != nil || len(id) != 16 {
+		return nil, fmt.Errorf("dash0: invalid trace ID %q: expected 32 hexadecimal characters", traceID)
+	}
+	filter, err := NewFilter().Is(FilterKeyTraceID, hex.EncodeToString(id)).Build()
+	if err != nil {
+		return nil, err
+	}
+
+	request := &GetSpansRequest{Dataset: dataset, Filter: &filter, TimeRange: timeRange}
+	var resourceSpans []ResourceSpans
+	for rs, err := range c.GetSpansIter(ctx, request).All() {
+		if err != nil {
+			return nil, err
+		}
+		resourceSpans = append(resourceSpans, *rs)
+	}
+
+	trace := NewTrace(resourceSpans)
+	if trace.Len() == 0 {
+		return nil, &APIError{
+			StatusCode: http.StatusNotFound,
+			Status:     http.StatusText(http.StatusNotFound),
+			Message:    fmt.Sprintf("trace %s not found", traceID),
+		}
+	}
+	return trace, nil
+}
+
+// Trace is a span tree assembled from the spans of a single trace.
+// Use GetTrace to fetch one, or NewTrace to assemble already fetched spans.
+type Trace struct {
+	// TraceID is the trace ID in hexadecimal notation.
+	TraceID string
+
+	// Roots are the spans without a parent in the trace, ordered by start
+	// time. This includes orphans, whose parent was not found.
+	Roots []*SpanNode
+
+	spans map[string]*SpanNode
+}
+
+// SpanNode is a span in a Trace, together with its resource, its
+// instrumentation scope and its position in the tree.
+type SpanNode struct {
+	Span     *Span
+	Resource *Resource
+	Scope    *InstrumentationScope
+
+	// Parent is nil for root spans and orphans.
+	Parent *SpanNode
+
+	// Children are ordered by start time.
+	Children []*SpanNode
+
+	// Depth is 0 for roots and increases by one per level.
+	Depth int
+
+	// Orphan is true if the span references a parent that is not part of
+	// the trace, e.g. because it has not been ingested yet or lies outside
+	// of the queried time range.
+	Orphan bool
+
+	// MissingChildren is true if the span reports children via the
+	// HasChildren schema extension, but none of them are part of the trace.
+	MissingChildren bool
+}
+
+// NewTrace assembles a span tree from resource spans. All spans are assumed
+// to belong to the same trace, and duplicate spans are only added once.
+// The nodes point into the given resource spans, which must not be modified
+// afterwards.
+//
+// Spans are linked to their parent via ParentSpanId. Spans without a
+// ParentSpanId are linked via Dash0TraceOriginParentSpanId instead, if the
+// OTLP schema extensions are present and the referenced span is part of the
+// trace.
+func NewTrace(resourceSpans []ResourceSpans) *Trace {
+	t := &Trace{spans: make(map[string]*SpanNode)}
+	var nodes []*SpanNode
+	for i := range resourceSpans {
+		rs := &resourceSpans[i]
+		for j := range rs.ScopeSpans {
+			ss := &rs.ScopeSpans[j]
+			for k := range ss.Spans {
+				span := &ss.Spans[k]
+				id := hex.EncodeToString(span.SpanId)
+				if _, ok := t.spans[id]; ok {
+					continue
+				}
+				if t.TraceID == "" {
+					t.TraceID = hex.EncodeToString(span.TraceId)
+				}
+				node := &SpanNode{Span: span, Resource: &rs.Resource, Scope: ss.Scope}
+				t.spans[id] = node
+				nodes = append(nodes, node)
+			}
+		}
+	}
+
+	for _, node := range nodes {
+		parentID := node.ParentSpanID()
+		if parentID == "" && node.Span.Dash0TraceOriginParentSpanId != nil {
+			if originID := hex.EncodeToString(*node.Span.Dash0TraceOriginParentSpanId); t.spans[originID] != nil {
+				parentID = originID
+			}
+		}
+		switch parent := t.spans[parentID]; {
+		case parentID == "":
+			t.Roots = append(t.Roots, node)
+		case parent == nil || parent == node:
+			node.Orphan = true
+			t.Roots = append(t.Roots, node)
+		default:
+			node.Parent = parent
+			parent.Children = append(parent.Children, node)
+		}
+	}
+
+	// Spans in or below a parent cycle are not reachable from any root. Follow
+	// the parents of each unreachable span to the first span that repeats,
+	// which is part of the cycle, and detach it as an orphan.
+	reachable := make(map[*SpanNode]bool, len(nodes))
+	for _, root := range t.Roots {
+		for n := range root.DepthFirst() {
+			reachable[n] = true
+		}
+	}
+	for _, node := range nodes {
+		if reachable[node] {
+			continue
+		}
+		visited := make(map[*SpanNode]bool)
+		cycle := node
+		for !visited[cycle] {
+			visited[cycle] = true
+			cycle = cycle.Parent
+		}
+		parent := cycle.Parent
+		parent.Children = slices.DeleteFunc(parent.Children, func(n *SpanNode) bool { return n == cycle })
+		cycle.Parent = nil
+		cycle.Orphan = true
+		t.Roots = append(t.Roots, cycle)
+		for n := range cycle.DepthFirst() {
+			reachable[n] = true
+		}
+	}
+
+	sortSpanNodes(t.Roots)
+	for _, node := range nodes {
+		sortSpanNodes(node.Children)
+		node.MissingChildren = BoolValue(node.Span.HasChildren) && len(node.Children) == 0
+	}
+	for n := range t.DepthFirst() {
+		if n.Parent != nil {
+			n.Depth = n.Parent.Depth + 1
+		}
+	}
+	return t
+}
+
+// Len returns the number of spans in the trace.
+func (t *Trace) Len() int {
+	return len(t.spans)
+}
+
+// Span returns the span with the given ID in hexadecimal notation, or nil if
+// it is not part of the trace.
+func (t *Trace) Span(spanID string) *SpanNode {
+	return t.spans[spanID]
+}
+
+// Root returns the earliest root span that is not an orphan, or nil if all
+// roots are orphans.
+func (t *Trace) Root() *SpanNode {
+	for _, root := range t.Roots {
+		if !root.Orphan {
+			return root
+		}
+	}
+	return nil
+}
+
+// Orphans returns the spans whose parent is not part of the trace.
+func (t *Trace) Orphans() []*SpanNode {
+	var orphans []*SpanNode
+	for _, root := range t.Roots {
+		if root.Orphan {
+			orphans = append(orphans, root)
+		}
+	}
+	return orphans
+}
+
+// Complete reports whether the trace has exactly one root, no orphans and no
+// spans with missing children.
+func (t *Trace) Complete() bool {
+	if len(t.Roots) != 1 || t.Roots[0].Orphan {
+		return false
+	}
+	for n := range t.DepthFirst() {
+		if n.MissingChildren {
+			return false
+		}
+	}
+	return true
+}
+
+// DepthFirst returns an iterator over all spans of the trace in depth-first
+// pre-order, i.e. every span is followed by its descendants. Roots and
+// children are visited in start time order.
+func (t *Trace) DepthFirst() iter.Seq[*SpanNode] {
+	return func(yield func(*SpanNode) bool) {
+		for _, root := range t.Roots {
+			if !root.walkDepthFirst(yield) {
+				return
+			}
+		}
+	}
+}
+
+// BreadthFirst returns an iterator over all spans of the trace level by
+// level, starting with the roots.
+func (t *Trace) BreadthFirst() iter.Seq[*SpanNode] {
+	return walkBreadthFirst(t.Roots)
+}
+
+// DepthFirst returns an iterator over the span and its descendants in
+// depth-first pre-order.
+func (n *SpanNode) DepthFirst() iter.Seq[*SpanNode] {
+	return func(yield func(*SpanNode) bool) {
+		n.walkDepthFirst(yield)
+	}
+}
+
+// BreadthFirst returns an iterator over the span and its descendants level
+// by level.
+func (n *SpanNode) BreadthFirst() iter.Seq[*SpanNode] {
+	return walkBreadthFirst([]*SpanNode{n})
+}
+
+func (n *SpanNode) walkDepthFirst(yield func(*SpanNode) bool) bool {
+	if !yield(n) {
+		return false
+	}
+	for _, child := range n.Children {
+		if !child.walkDepthFirst(yield) {
+			return false
+		}
+	}
+	return true
+}
+
+func walkBreadthFirst(level []*SpanNode) iter.Seq[*SpanNode] {
+	return func(yield func(*SpanNode) bool) {
+		queue := slices.Clone(level)
+		for len(queue) > 0 {
+			n := queue[0]
+			queue = queue[1:]
+			if !yield(n) {
+				return
+			}
+			queue = append(queue, n.Children...)
+		}
+	}
+}
+
+// SpanID returns the span ID in hexadecimal notation.
+func (n *SpanNode) SpanID() string {
+	return hex.EncodeToString(n.Span.SpanId)
+}
+
+// ParentSpanID returns the parent span ID in hexadecimal notation, or an
+// empty string if the span has no parent.
+func (n *SpanNode) ParentSpanID() string {
+	if n.Span.ParentSpanId == nil {
+		return ""
+	}
+	return hex.EncodeToString(*n.Span.ParentSpanId)
+}
+
+// StartTime returns the start time of the span.
+func (n *SpanNode) StartTime() time.Time {
+	return parseUnixNano(n.Span.StartTimeUnixNano)
+}
+
+// EndTime returns the end time of the span.
+func (n *SpanNode) EndTime() time.Time {
+	return parseUnixNano(n.Span.EndTimeUnixNano)
+}
+
+// Duration returns the duration of the span.
+func (n *SpanNode) Duration() time.Duration {
+	return n.EndTime().Sub(n.StartTime())
+}
+
+// sortSpanNodes orders nodes by start time, keeping the order of spans that
+// started at the same time.
+func sortSpanNodes(nodes []*SpanNode) {
+	slices.SortStableFunc(nodes, func(a, b *SpanNode) int {
+		return cmp.Compare(UnixNano(a.Span.StartTimeUnixNano), UnixNano(b.Span.StartTimeUnixNano))
+	})
+}
+
+// parseUnixNano converts a decimal timestamp in nanoseconds since the Unix
+// epoch to a time. Invalid timestamps result in the Unix epoch.
+func parseUnixNano(s string) time.Time {
+	return time.Unix(0, UnixNano(s)).UTC()
+}
+
+// UnixNano parses a decimal timestamp in nanoseconds since the Unix epoch, as
+// used by the time fields of spans and log records, e.g. StartTimeUnixNano.
+// Invalid timestamps are 0.
+func UnixNano(s string) int64 {
+	n, _ := strconv.ParseInt(s, 10, 64)
+	return n
+}
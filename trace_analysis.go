@@ -0,0 +1,232 @@
+package dash0
+
+import (
+	"slices"
+	"time"
+)
+
+// UnknownService is the service name reported for spans whose resource has
+// no service.name attribute.
+const UnknownService = "unknown_service"
+
+// TraceAnalysis holds timing statistics of a trace. Use Trace.Analyze to
+// compute one.
+type TraceAnalysis struct {
+	// Start and End are the earliest start and the latest end of any span.
+	Start time.Time
+	End   time.Time
+
+	// Spans holds the timings of all spans in depth-first order.
+	Spans []SpanTiming
+
+	// CriticalPath is the chain of span sections that determines the
+	// duration of the root span, in chronological order.
+	CriticalPath []CriticalPathSegment
+
+	// ByService aggregates the span timings by the service.name resource
+	// attribute. Spans without one are counted as UnknownService.
+	ByService map[string]TimeBreakdown
+
+	// ByKind aggregates the span timings by span kind.
+	ByKind map[SpanKind]TimeBreakdown
+
+	timings map[*SpanNode]int
+}
+
+// SpanTiming holds the timing statistics of a single span.
+type SpanTiming struct {
+	Node *SpanNode
+
+	// Duration is the time between the start and the end of the span.
+	Duration time.Duration
+
+	// SelfTime is the part of the duration in which none of the children of
+	// the span were running.
+	SelfTime time.Duration
+
+	// CriticalPathTime is the part of the duration that lies on the critical
+	// path of the trace.
+	CriticalPathTime time.Duration
+}
+
+// CriticalPathSegment is a section of a span on the critical path, during
+// which the span itself was doing work rather than waiting for a child.
+type CriticalPathSegment struct {
+	Node  *SpanNode
+	Start time.Time
+	End   time.Time
+}
+
+// Duration returns the length of the segment.
+func (s CriticalPathSegment) Duration() time.Duration {
+	return s.End.Sub(s.Start)
+}
+
+// TimeBreakdown aggregates the timings of a group of spans.
+type TimeBreakdown struct {
+	Spans            int
+	Duration         time.Duration
+	SelfTime         time.Duration
+	CriticalPathTime time.Duration
+}
+
+// Duration returns the time between the earliest start and the latest end of
+// any span.
+func (a *TraceAnalysis) Duration() time.Duration {
+	return a.End.Sub(a.Start)
+}
+
+// Timing returns the timing of a span of the analyzed trace.
+func (a *TraceAnalysis) Timing(node *SpanNode) (SpanTiming, bool) {
+	i, ok := a.timings[node]
+	if !ok {
+		return SpanTiming{}, false
+	}
+	return a.Spans[i], true
+}
+
+// Analyze computes self times, the critical path and the time spent per
+// service and span kind.
+//
+// Children are clipped to the time of their parent, so asynchronous children
+// that outlive their parent only count while the parent runs. The critical
+// path starts at the root returned by Root, or at the first orphan if the
+// trace has no regular root. It follows the child that finished last, then
+// the child that finished last before that child started, and so on.
+// Children running concurrently with a child on the critical path are not
+// part of it.
+func (t *Trace) Analyze() *TraceAnalysis {
+	a := &TraceAnalysis{
+		ByService: make(map[string]TimeBreakdown),
+		ByKind:    make(map[SpanKind]TimeBreakdown),
+		timings:   make(map[*SpanNode]int, t.Len()),
+	}
+
+	var start, end int64
+	for n := range t.DepthFirst() {
+		s, e := spanInterval(n)
+		if len(a.Spans) == 0 || s < start {
+			start = s
+		}
+		if len(a.Spans) == 0 || e > end {
+			end = e
+		}
+		a.timings[n] = len(a.Spans)
+		a.Spans = append(a.Spans, SpanTiming{
+			Node:     n,
+			Duration: time.Duration(e - s),
+			SelfTime: time.Duration(selfTime(n)),
+		})
+	}
+	a.Start = time.Unix(0, start).UTC()
+	a.End = time.Unix(0, end).UTC()
+
+	root := t.Root()
+	if root == nil && len(t.Roots) > 0 {
+		root = t.Roots[0]
+	}
+	if root != nil {
+		s, e := spanInterval(root)
+		a.CriticalPath = criticalPath(root, s, e, nil)
+		slices.Reverse(a.CriticalPath)
+	}
+	for _, segment := range a.CriticalPath {
+		a.Spans[a.timings[segment.Node]].CriticalPathTime += segment.Duration()
+	}
+
+	for _, timing := range a.Spans {
+		service := timing.Node.ServiceName()
+		a.ByService[service] = a.ByService[service].add(timing)
+		kind := timing.Node.Span.Kind
+		a.ByKind[kind] = a.ByKind[kind].add(timing)
+	}
+	return a
+}
+
+func (b TimeBreakdown) add(timing SpanTiming) TimeBreakdown {
+	b.Spans++
+	b.Duration += timing.Duration
+	b.SelfTime += timing.SelfTime
+	b.CriticalPathTime += timing.CriticalPathTime
+	return b
+}
+
+// ServiceName returns the service.name attribute of the span's resource, or
+// UnknownService if it is not set.
+func (n *SpanNode) ServiceName() string {
+	return n.Resource.ServiceName()
+}
+
+// ServiceName returns the service.name attribute of the resource, or
+// UnknownService if it is not set or the resource is nil.
+func (r *Resource) ServiceName() string {
+	if r != nil {
+		if name, ok := lookupAttribute("service.name", [][]KeyValue{r.Attributes}); ok && name != "" {
+			return name
+		}
+	}
+	return UnknownService
+}
+
+// spanInterval returns the start and end of a span in nanoseconds. Spans
+// that end before they start are treated as instantaneous.
+func spanInterval(n *SpanNode) (int64, int64) {
+	start, end := UnixNano(n.Span.StartTimeUnixNano), UnixNano(n.Span.EndTimeUnixNano)
+	return start, max(start, end)
+}
+
+// selfTime returns the nanoseconds of a span that are not covered by any of
+// its children.
+func selfTime(n *SpanNode) int64 {
+	start, end := spanInterval(n)
+	self := end - start
+	// Children are ordered by start time, so overlapping children can be
+	// merged in a single pass.
+	covered, coveredEnd := int64(0), start
+	for _, child := range n.Children {
+		s, e := spanInterval(child)
+		s, e = max(s, coveredEnd), min(e, end)
+		if e > s {
+			covered += e - s
+			coveredEnd = e
+		}
+	}
+	return self - covered
+}
+
+// criticalPath appends the critical path segments of n within [start, end)
+// to path in reverse chronological order.
+func criticalPath(n *SpanNode, start, end int64, path []CriticalPathSegment) []CriticalPathSegment {
+	cursor := end
+	for {
+		// Find the child that finished last before the cursor
+		var next *SpanNode
+		var nextStart, nextEnd int64
+		for _, child := range n.Children {
+			s, e := spanInterval(child)
+			s, e = max(s, start), min(e, end)
+			if e <= s || e > cursor {
+				continue
+			}
+			if next == nil || e > nextEnd {
+				next, nextStart, nextEnd = child, s, e
+			}
+		}
+		if next == nil {
+			break
+		}
+		if nextEnd < cursor {
+			path = append(path, newCriticalPathSegment(n, nextEnd, cursor))
+		}
+		path = criticalPath(next, nextStart, nextEnd, path)
+		cursor = nextStart
+	}
+	if cursor > start {
+		path = append(path, newCriticalPathSegment(n, start, cursor))
+	}
+	return path
+}
+
+func newCriticalPathSegment(n *SpanNode, start, end int64) CriticalPathSegment {
+	return CriticalPathSegment{Node: n, Start: time.Unix(0, start).UTC(), End: time.Unix(0, end).UTC()}
+}
@@ -0,0 +1,87 @@
+package dash0
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTrace_Analyze(t *testing.T) {
+	withKind := func(span Span, kind SpanKind, end int64) Span {
+		span.Kind = kind
+		span.EndTimeUnixNano = fmt.Sprint(end)
+		return span
+	}
+	trace := NewTrace([]ResourceSpans{
+		testResourceSpans("frontend",
+			withKind(testSpan("root", "", 0), 2, 100),
+			// Asynchronous child that outlives its parent
+			withKind(testSpan("c", "root", 70), 4, 120),
+		),
+		testResourceSpans("backend",
+			withKind(testSpan("a", "root", 10), 3, 40),
+			withKind(testSpan("b", "root", 20), 3, 60),
+			withKind(testSpan("b1", "b", 30), 1, 50),
+		),
+	})
+
+	a := trace.Analyze()
+
+	t.Run("computes the trace duration", func(t *testing.T) {
+		if a.Duration() != 120 || a.Start.UnixNano() != 0 {
+			t.Errorf("unexpected bounds %v - %v", a.Start, a.End)
+		}
+	})
+
+	t.Run("computes self times", func(t *testing.T) {
+		want := map[string]time.Duration{"root": 20, "a": 30, "b": 20, "b1": 20, "c": 50}
+		if len(a.Spans) != len(want) {
+			t.Fatalf("expected %d timings, got %d", len(want), len(a.Spans))
+		}
+		for _, timing := range a.Spans {
+			if timing.SelfTime != want[timing.Node.Span.Name] {
+				t.Errorf("expected self time %v for %s, got %v", want[timing.Node.Span.Name], timing.Node.Span.Name, timing.SelfTime)
+			}
+		}
+	})
+
+	t.Run("follows the last finishing children", func(t *testing.T) {
+		var got []string
+		for _, segment := range a.CriticalPath {
+			got = append(got, fmt.Sprintf("%s[%d,%d]", segment.Node.Span.Name, segment.Start.UnixNano(), segment.End.UnixNano()))
+		}
+		want := "root[0,20] b[20,30] b1[30,50] b[50,60] root[60,70] c[70,100]"
+		if strings.Join(got, " ") != want {
+			t.Errorf("expected %s, got %s", want, strings.Join(got, " "))
+		}
+
+		timing, ok := a.Timing(trace.Span(fmt.Sprintf("%x", testSpanID("b"))))
+		if !ok || timing.CriticalPathTime != 20 {
+			t.Errorf("expected 20ns on the critical path, got %+v", timing)
+		}
+		if timing, _ := a.Timing(trace.Span(fmt.Sprintf("%x", testSpanID("a")))); timing.CriticalPathTime != 0 {
+			t.Errorf("expected concurrent span to be off the critical path, got %v", timing.CriticalPathTime)
+		}
+	})
+
+	t.Run("aggregates by service and kind", func(t *testing.T) {
+		if got, want := a.ByService["frontend"], (TimeBreakdown{Spans: 2, Duration: 150, SelfTime: 70, CriticalPathTime: 60}); got != want {
+			t.Errorf("expected %+v, got %+v", want, got)
+		}
+		if got, want := a.ByService["backend"], (TimeBreakdown{Spans: 3, Duration: 90, SelfTime: 70, CriticalPathTime: 40}); got != want {
+			t.Errorf("expected %+v, got %+v", want, got)
+		}
+		if got, want := a.ByKind[3], (TimeBreakdown{Spans: 2, Duration: 70, SelfTime: 50, CriticalPathTime: 20}); got != want {
+			t.Errorf("expected %+v, got %+v", want, got)
+		}
+	})
+
+	t.Run("handles traces without resources", func(t *testing.T) {
+		trace := NewTrace([]ResourceSpans{{ScopeSpans: []ScopeSpans{{Spans: []Span{testSpan("lost", "missing", 0)}}}}})
+		a := trace.Analyze()
+		if a.ByService[UnknownService].Spans != 1 || len(a.CriticalPath) != 1 {
+			t.Errorf("unexpected analysis %+v", a)
+		}
+	})
+}
@@ -0,0 +1,262 @@
+package dash0
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"iter"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+const testTraceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+
+// testSpan returns a span of the test trace. IDs are given as short strings
+// and padded to 8 bytes. An empty parent means no parent.
+func testSpan(id, parent string, start int64) Span {
+	span := Span{
+		Name:              id,
+		TraceId:           mustDecodeHex(testTraceID),
+		SpanId:            testSpanID(id),
+		StartTimeUnixNano: strconv.FormatInt(start, 10),
+		EndTimeUnixNano:   strconv.FormatInt(start+10, 10),
+	}
+	if parent != "" {
+		span.ParentSpanId = Ptr(testSpanID(parent))
+	}
+	return span
+}
+
+func testSpanID(id string) []byte {
+	b := make([]byte, 8)
+	copy(b, id)
+	return b
+}
+
+func mustDecodeHex(s string) []byte {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+func testResourceSpans(service string, spans ...Span) ResourceSpans {
+	return ResourceSpans{
+		Resource:   Resource{Attributes: []KeyValue{stringAttribute("service.name", service)}},
+		ScopeSpans: []ScopeSpans{{Scope: &InstrumentationScope{Name: Ptr("scope-" + service)}, Spans: spans}},
+	}
+}
+
+func spanNames(nodes iter.Seq[*SpanNode]) string {
+	var names []string
+	for n := range nodes {
+		names = append(names, n.Span.Name)
+	}
+	return strings.Join(names, ",")
+}
+
+func TestNewTrace(t *testing.T) {
+	t.Run("links spans across resources", func(t *testing.T) {
+		trace := NewTrace([]ResourceSpans{
+			testResourceSpans("frontend", testSpan("root", "", 0), testSpan("b", "root", 20)),
+			testResourceSpans("backend", testSpan("a", "root", 10), testSpan("a1", "a", 11)),
+		})
+
+		if trace.TraceID != testTraceID || trace.Len() != 4 {
+			t.Fatalf("unexpected trace %s with %d spans", trace.TraceID, trace.Len())
+		}
+		if got := spanNames(trace.DepthFirst()); got != "root,a,a1,b" {
+			t.Errorf("unexpected depth-first order: %s", got)
+		}
+		if got := spanNames(trace.BreadthFirst()); got != "root,a,b,a1" {
+			t.Errorf("unexpected breadth-first order: %s", got)
+		}
+		if !trace.Complete() {
+			t.Error("expected trace to be complete")
+		}
+
+		a1 := trace.Span(hex.EncodeToString(testSpanID("a1")))
+		if a1 == nil || a1.Depth != 2 || a1.Parent.Span.Name != "a" {
+			t.Fatalf("unexpected node %+v", a1)
+		}
+		if name, _ := lookupAttribute("service.name", [][]KeyValue{a1.Resource.Attributes}); name != "backend" {
+			t.Errorf("expected backend resource, got %s", name)
+		}
+		if StringValue(a1.Scope.Name) != "scope-backend" {
+			t.Errorf("expected backend scope, got %s", StringValue(a1.Scope.Name))
+		}
+		if a1.Duration() != 10 {
+			t.Errorf("expected duration 10ns, got %v", a1.Duration())
+		}
+	})
+
+	t.Run("keeps orphans as roots", func(t *testing.T) {
+		trace := NewTrace([]ResourceSpans{
+			testResourceSpans("frontend", testSpan("root", "", 5), testSpan("lost", "missing", 0), testSpan("child", "lost", 1)),
+		})
+
+		if got := spanNames(trace.DepthFirst()); got != "lost,child,root" {
+			t.Errorf("unexpected order: %s", got)
+		}
+		if trace.Root().Span.Name != "root" {
+			t.Errorf("expected root, got %s", trace.Root().Span.Name)
+		}
+		if orphans := trace.Orphans(); len(orphans) != 1 || orphans[0].Span.Name != "lost" {
+			t.Errorf("unexpected orphans: %v", orphans)
+		}
+		if trace.Complete() {
+			t.Error("expected trace to be incomplete")
+		}
+	})
+
+	t.Run("uses schema extensions", func(t *testing.T) {
+		linked := testSpan("linked", "", 1)
+		linked.Dash0TraceOriginParentSpanId = Ptr(testSpanID("root"))
+		remote := testSpan("remote", "", 2)
+		remote.Dash0TraceOriginParentSpanId = Ptr(testSpanID("elsewhere"))
+		root := testSpan("root", "", 0)
+		root.HasChildren = Ptr(true)
+		leaf := testSpan("leaf", "remote", 3)
+		leaf.HasChildren = Ptr(true)
+
+		trace := NewTrace([]ResourceSpans{testResourceSpans("frontend", root, linked, remote, leaf)})
+
+		if got := spanNames(trace.DepthFirst()); got != "root,linked,remote,leaf" {
+			t.Errorf("unexpected order: %s", got)
+		}
+		if len(trace.Orphans()) != 0 {
+			t.Errorf("expected no orphans, got %v", trace.Orphans())
+		}
+		if trace.Span(hex.EncodeToString(testSpanID("root"))).MissingChildren {
+			t.Error("expected root to have its children")
+		}
+		if !trace.Span(hex.EncodeToString(testSpanID("leaf"))).MissingChildren {
+			t.Error("expected leaf to miss its children")
+		}
+	})
+
+	t.Run("breaks parent cycles", func(t *testing.T) {
+		trace := NewTrace([]ResourceSpans{
+			testResourceSpans("frontend", testSpan("a", "b", 0), testSpan("b", "a", 1), testSpan("self", "self", 2)),
+		})
+
+		if got := spanNames(trace.DepthFirst()); got != "a,b,self" {
+			t.Errorf("unexpected order: %s", got)
+		}
+		if len(trace.Orphans()) != 2 {
+			t.Errorf("expected 2 orphans, got %d", len(trace.Orphans()))
+		}
+	})
+
+	t.Run("keeps children of parent cycles attached", func(t *testing.T) {
+		trace := NewTrace([]ResourceSpans{
+			testResourceSpans("frontend", testSpan("c", "a", 3), testSpan("a", "b", 0), testSpan("b", "a", 1)),
+		})
+
+		if got := spanNames(trace.DepthFirst()); got != "a,b,c" {
+			t.Errorf("unexpected order: %s", got)
+		}
+		if orphans := trace.Orphans(); len(orphans) != 1 || orphans[0].Span.Name != "a" {
+			t.Errorf("expected a to be the only orphan, got %v", orphans)
+		}
+		if c := trace.Span(hex.EncodeToString(testSpanID("c"))); c.Orphan || c.Parent.Span.Name != "a" || c.Depth != 1 {
+			t.Errorf("expected c to stay a child of a, got %+v", c)
+		}
+	})
+
+	t.Run("ignores duplicate spans", func(t *testing.T) {
+		trace := NewTrace([]ResourceSpans{
+			testResourceSpans("frontend", testSpan("root", "", 0)),
+			testResourceSpans("frontend", testSpan("root", "", 0)),
+		})
+		if trace.Len() != 1 || len(trace.Roots) != 1 {
+			t.Errorf("expected a single span, got %d", trace.Len())
+		}
+	})
+
+	t.Run("stops walking early", func(t *testing.T) {
+		trace := NewTrace([]ResourceSpans{
+			testResourceSpans("frontend", testSpan("root", "", 0), testSpan("a", "root", 1), testSpan("b", "root", 2)),
+		})
+		for _, walk := range []iter.Seq[*SpanNode]{trace.DepthFirst(), trace.BreadthFirst()} {
+			count := 0
+			for range walk {
+				count++
+				if count == 2 {
+					break
+				}
+			}
+			if count != 2 {
+				t.Errorf("expected 2 spans, got %d", count)
+			}
+		}
+	})
+}
+
+func TestClient_GetTrace(t *testing.T) {
+	t.Run("fetches all pages of the trace", func(t *testing.T) {
+		var filters []string
+		client := newWindowTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var req GetSpansRequest
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				t.Errorf("failed to decode request: %v", err)
+			}
+			filters = append(filters, FormatFilter(*req.Filter))
+
+			resp := GetSpansResponse{ResourceSpans: []ResourceSpans{testResourceSpans("frontend", testSpan("root", "", 0))}}
+			if req.Pagination == nil || req.Pagination.Cursor == nil {
+				resp.ResourceSpans = []ResourceSpans{testResourceSpans("backend", testSpan("child", "root", 1))}
+				resp.Cursors = &NextCursors{After: Ptr("next")}
+			}
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(resp)
+		}))
+
+		trace, err := client.GetTrace(context.Background(), strings.ToUpper(testTraceID), TimeRangeLast(0), nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got := spanNames(trace.DepthFirst()); got != "root,child" {
+			t.Errorf("unexpected spans: %s", got)
+		}
+		if len(filters) != 2 || filters[0] != FilterKeyTraceID+` = "`+testTraceID+`"` {
+			t.Errorf("unexpected filters: %v", filters)
+		}
+	})
+
+	t.Run("returns not found for unknown traces", func(t *testing.T) {
+		client := newWindowTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"resourceSpans":[]}`))
+		}))
+
+		_, err := client.GetTrace(context.Background(), testTraceID, TimeRangeLast(0), nil)
+		if !IsNotFound(err) {
+			t.Errorf("expected not found error, got %v", err)
+		}
+	})
+
+	t.Run("rejects invalid trace IDs", func(t *testing.T) {
+		client := newWindowTestClient(t, http.NotFoundHandler())
+
+		for _, id := range []string{"", "abc", testTraceID + "00", strings.Repeat("x", 32)} {
+			if _, err := client.GetTrace(context.Background(), id, TimeRangeLast(0), nil); err == nil || IsNotFound(err) {
+				t.Errorf("expected validation error for %q, got %v", id, err)
+			}
+		}
+	})
+}
+
+func TestUnixNano(t *testing.T) {
+	if got := UnixNano("1705329000000000000"); got != 1705329000000000000 {
+		t.Errorf("expected 1705329000000000000, got %d", got)
+	}
+	for _, s := range []string{"", "abc", "1.5"} {
+		if got := UnixNano(s); got != 0 {
+			t.Errorf("expected 0 for %q, got %d", s, got)
+		}
+	}
+}
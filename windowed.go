@@ -0,0 +1,249 @@
+package dash0
+
+import (
+	"context"
+	"time"
+)
+
+// DefaultWindowSize is the default size of the sub-windows of windowed queries.
+const DefaultWindowSize = time.Hour
+
+// windowBufferPages is the number of pages a window prefetches before it waits
+// for the iterator to catch up.
+const windowBufferPages = 4
+
+// WindowOption configures windowed queries such as GetSpansWindowedIter.
+type WindowOption func(*windowConfig)
+
+type windowConfig struct {
+	size      time.Duration
+	minSize   time.Duration
+	lookahead int
+	now       func() time.Time
+}
+
+// WithWindowSize sets the size of the sub-windows the time range is split into.
+// Defaults to DefaultWindowSize.
+func WithWindowSize(size time.Duration) WindowOption {
+	return func(c *windowConfig) {
+		c.size = size
+	}
+}
+
+// WithAdaptiveWindows enables splitting of dense windows. A window whose first
+// page is not its last page is split in half, and the halves are queried in
+// parallel instead, until windows reach the given minimum size.
+func WithAdaptiveWindows(minSize time.Duration) WindowOption {
+	return func(c *windowConfig) {
+		c.minSize = minSize
+	}
+}
+
+// WithWindowLookahead sets how many windows are queried ahead of the window
+// that is currently being iterated. Defaults to the client's maximum number of
+// concurrent requests (see WithMaxConcurrentRequests). The client's limit on
+// concurrent requests applies regardless of this setting.
+func WithWindowLookahead(n int) WindowOption {
+	return func(c *windowConfig) {
+		c.lookahead = n
+	}
+}
+
+// timeWindow is a half-open interval [from, to).
+type timeWindow struct {
+	from, to time.Time
+}
+
+// timeRange returns the window as a TimeReferenceRange of fixed times.
+func (w timeWindow) timeRange() TimeReferenceRange {
+	return TimeReferenceRange{From: NewFixedTime(w.from), To: NewFixedTime(w.to)}
+}
+
+// splitWindows splits [from, to) into consecutive windows of the given size,
+// ordered newest first if descending is true.
+func splitWindows(from, to time.Time, size time.Duration, descending bool) []timeWindow {
+	var windows []timeWindow
+	for start := from; start.Before(to); start = start.Add(size) {
+		end := start.Add(size)
+		if end.After(to) {
+			end = to
+		}
+		windows = append(windows, timeWindow{from: start, to: end})
+	}
+	if descending {
+		for i, j := 0, len(windows)-1; i < j; i, j = i+1, j-1 {
+			windows[i], windows[j] = windows[j], windows[i]
+		}
+	}
+	return windows
+}
+
+// windowFetchFunc fetches a single page of a window.
+type windowFetchFunc[T any] func(ctx context.Context, window timeWindow, cursor *string) ([]*T, *string, error)
+
+// windowPage is a message from a window worker to the iterator.
+type windowPage[T any] struct {
+	items []*T
+	err   error
+
+	// split holds the windows that replace a dense window.
+	split []timeWindow
+}
+
+// windowRun is a window in the iteration queue.
+type windowRun[T any] struct {
+	window  timeWindow
+	pages   chan windowPage[T]
+	started bool
+}
+
+// windowedQuery queries windows in parallel and yields their pages in window order.
+type windowedQuery[T any] struct {
+	ctx        context.Context
+	cancel     context.CancelFunc
+	fetch      windowFetchFunc[T]
+	config     *windowConfig
+	descending bool
+	queue      []*windowRun[T]
+}
+
+// newWindowedIter returns an iterator over the items of all windows of r.
+// Windows are emitted newest first if descending is true, oldest first
+// otherwise. Within a window, items keep the order of the API.
+func newWindowedIter[T any](ctx context.Context, r TimeRange, descending bool, cfg *windowConfig, fetch windowFetchFunc[T]) *Iter[T] {
+	ctx, cancel := context.WithCancel(ctx)
+	q := &windowedQuery[T]{
+		ctx:        ctx,
+		cancel:     cancel,
+		fetch:      fetch,
+		config:     cfg,
+		descending: descending,
+	}
+	for _, w := range splitWindows(r.From, r.To, cfg.size, descending) {
+		q.queue = append(q.queue, &windowRun[T]{window: w})
+	}
+
+	more := ""
+	it := newIter(nil, true, nil, func(*string) ([]*T, *string, error) {
+		items, ok, err := q.next()
+		if !ok || err != nil {
+			return items, nil, err
+		}
+		return items, &more, nil
+	})
+	it.opaque = true
+	it.close = cancel
+	return it
+}
+
+// next returns the next non-empty page. It returns false once all windows are
+// exhausted.
+func (q *windowedQuery[T]) next() ([]*T, bool, error) {
+	for {
+		if len(q.queue) == 0 {
+			q.cancel()
+			return nil, false, nil
+		}
+		q.start()
+
+		run := q.queue[0]
+		select {
+		case page, ok := <-run.pages:
+			switch {
+			case !ok:
+				q.queue = q.queue[1:]
+			case page.err != nil:
+				q.cancel()
+				return nil, false, page.err
+			case page.split != nil:
+				replacement := make([]*windowRun[T], len(page.split))
+				for i, w := range page.split {
+					replacement[i] = &windowRun[T]{window: w}
+				}
+				q.queue = append(replacement, q.queue[1:]...)
+			case len(page.items) > 0:
+				return page.items, true, nil
+			}
+		case <-q.ctx.Done():
+			return nil, false, q.ctx.Err()
+		}
+	}
+}
+
+// start starts the workers of the first windows in the queue, up to the lookahead.
+func (q *windowedQuery[T]) start() {
+	for i := 0; i < len(q.queue) && i < q.config.lookahead; i++ {
+		run := q.queue[i]
+		if !run.started {
+			run.started = true
+			run.pages = make(chan windowPage[T], windowBufferPages)
+			go q.run(run)
+		}
+	}
+}
+
+// run fetches all pages of a window, or splits it if it is too dense.
+func (q *windowedQuery[T]) run(run *windowRun[T]) {
+	defer close(run.pages)
+
+	var cursor *string
+	for first := true; ; first = false {
+		items, next, err := q.fetch(q.ctx, run.window, cursor)
+		if err != nil {
+			q.send(run, windowPage[T]{err: err})
+			return
+		}
+		if first && next != nil && q.splittable(run.window) {
+			q.send(run, windowPage[T]{split: q.split(run.window)})
+			return
+		}
+		if !q.send(run, windowPage[T]{items: items}) || next == nil {
+			return
+		}
+		cursor = next
+	}
+}
+
+func (q *windowedQuery[T]) send(run *windowRun[T], page windowPage[T]) bool {
+	select {
+	case run.pages <- page:
+		return true
+	case <-q.ctx.Done():
+		return false
+	}
+}
+
+// splittable reports whether a window may be split under adaptive windowing.
+func (q *windowedQuery[T]) splittable(w timeWindow) bool {
+	return q.config.minSize > 0 && w.to.Sub(w.from)/2 >= q.config.minSize
+}
+
+// split halves a window, keeping the emission order.
+func (q *windowedQuery[T]) split(w timeWindow) []timeWindow {
+	return splitWindows(w.from, w.to, (w.to.Sub(w.from)+1)/2, q.descending)
+}
+
+// newWindowConfig applies the options on top of the defaults.
+func newWindowConfig(maxConcurrent int64, opts []WindowOption) *windowConfig {
+	cfg := &windowConfig{
+		size:      DefaultWindowSize,
+		lookahead: int(maxConcurrent),
+		now:       time.Now,
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	if cfg.size <= 0 {
+		cfg.size = DefaultWindowSize
+	}
+	if cfg.lookahead < 1 {
+		cfg.lookahead = 1
+	}
+	return cfg
+}
+
+// isDescending reports whether results are ordered newest first, which is the
+// API default unless the first ordering criterion is ascending.
+func isDescending(ordering *OrderingCriteria) bool {
+	return ordering == nil || len(*ordering) == 0 || (*ordering)[0].Direction != Ascending
+}
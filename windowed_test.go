@@ -0,0 +1,238 @@
+package dash0
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestSplitWindows(t *testing.T) {
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := from.Add(150 * time.Minute)
+
+	windows := splitWindows(from, to, time.Hour, false)
+	if len(windows) != 3 {
+		t.Fatalf("expected 3 windows, got %d", len(windows))
+	}
+	if !windows[0].from.Equal(from) || !windows[2].to.Equal(to) || windows[2].to.Sub(windows[2].from) != 30*time.Minute {
+		t.Errorf("unexpected windows: %v", windows)
+	}
+
+	descending := splitWindows(from, to, time.Hour, true)
+	if !descending[0].to.Equal(to) || !descending[2].from.Equal(from) {
+		t.Errorf("expected newest window first, got %v", descending)
+	}
+
+	if len(splitWindows(from, from, time.Hour, false)) != 0 {
+		t.Error("expected no windows for an empty range")
+	}
+}
+
+// windowServer serves spans for windowed queries. Every window yields one
+// ResourceSpans per page, with SchemaUrl set to the window start and the page
+// number. pages decides how many pages a window has.
+type windowServer struct {
+	t        *testing.T
+	pages    func(from, to time.Time) int
+	delay    time.Duration
+	inFlight atomic.Int32
+	maxSeen  atomic.Int32
+
+	mu       sync.Mutex
+	requests int
+}
+
+func (s *windowServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	n := s.inFlight.Add(1)
+	defer s.inFlight.Add(-1)
+	for {
+		m := s.maxSeen.Load()
+		if n <= m || s.maxSeen.CompareAndSwap(m, n) {
+			break
+		}
+	}
+	s.mu.Lock()
+	s.requests++
+	s.mu.Unlock()
+	time.Sleep(s.delay)
+
+	var req struct {
+		TimeRange struct {
+			From time.Time `json:"from"`
+			To   time.Time `json:"to"`
+		} `json:"timeRange"`
+		Pagination *CursorPagination `json:"pagination"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s.t.Errorf("failed to decode request: %v", err)
+	}
+
+	page := 0
+	if req.Pagination != nil && req.Pagination.Cursor != nil {
+		page = len(*req.Pagination.Cursor)
+	}
+	resp := GetSpansResponse{ResourceSpans: []ResourceSpans{{
+		SchemaUrl: Ptr(req.TimeRange.From.Format("15:04") + "#" + string(rune('0'+page))),
+	}}}
+	if page+1 < s.pages(req.TimeRange.From, req.TimeRange.To) {
+		// The cursor encodes the next page number as its length
+		cursor := string(make([]byte, page+1))
+		resp.Cursors = &NextCursors{After: &cursor}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+func newWindowTestClient(t *testing.T, handler http.Handler, opts ...ClientOption) Client {
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	client, err := NewClient(append([]ClientOption{WithApiUrl(server.URL), WithAuthToken("auth_test123")}, opts...)...)
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	return client
+}
+
+func collectSchemaURLs(t *testing.T, iter *Iter[ResourceSpans]) []string {
+	t.Helper()
+	var result []string
+	for resourceSpans, err := range iter.All() {
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		result = append(result, StringValue(resourceSpans.SchemaUrl))
+	}
+	return result
+}
+
+func TestClient_GetSpansWindowedIter(t *testing.T) {
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	request := &GetSpansRequest{
+		TimeRange: TimeReferenceRange{From: NewFixedTime(from), To: NewFixedTime(from.Add(3 * time.Hour))},
+	}
+
+	t.Run("yields windows newest first and pages in order", func(t *testing.T) {
+		client := newWindowTestClient(t, &windowServer{t: t, pages: func(from, to time.Time) int {
+			if from.Hour() == 1 {
+				return 3
+			}
+			return 1
+		}})
+
+		got := collectSchemaURLs(t, client.GetSpansWindowedIter(context.Background(), request))
+		want := []string{"02:00#0", "01:00#0", "01:00#1", "01:00#2", "00:00#0"}
+		if len(got) != len(want) {
+			t.Fatalf("expected %v, got %v", want, got)
+		}
+		for i := range want {
+			if got[i] != want[i] {
+				t.Errorf("expected %v, got %v", want, got)
+				break
+			}
+		}
+	})
+
+	t.Run("yields windows oldest first for ascending ordering", func(t *testing.T) {
+		client := newWindowTestClient(t, &windowServer{t: t, pages: func(from, to time.Time) int { return 1 }})
+
+		ascending := *request
+		ascending.Ordering = &OrderingCriteria{{Key: "timestamp", Direction: Ascending}}
+		got := collectSchemaURLs(t, client.GetSpansWindowedIter(context.Background(), &ascending))
+		if len(got) != 3 || got[0] != "00:00#0" || got[2] != "02:00#0" {
+			t.Errorf("unexpected order: %v", got)
+		}
+	})
+
+	t.Run("splits dense windows when adaptive", func(t *testing.T) {
+		client := newWindowTestClient(t, &windowServer{t: t, pages: func(from, to time.Time) int {
+			if from.Hour() == 2 && to.Sub(from) > 15*time.Minute {
+				return 2
+			}
+			return 1
+		}})
+
+		got := collectSchemaURLs(t, client.GetSpansWindowedIter(context.Background(), request, WithAdaptiveWindows(15*time.Minute)))
+		want := []string{"02:45#0", "02:30#0", "02:15#0", "02:00#0", "01:00#0", "00:00#0"}
+		if len(got) != len(want) {
+			t.Fatalf("expected %v, got %v", want, got)
+		}
+		for i := range want {
+			if got[i] != want[i] {
+				t.Errorf("expected %v, got %v", want, got)
+				break
+			}
+		}
+	})
+
+	t.Run("queries windows in parallel within the client limit", func(t *testing.T) {
+		server := &windowServer{t: t, delay: 20 * time.Millisecond, pages: func(from, to time.Time) int { return 1 }}
+		client := newWindowTestClient(t, server, WithMaxConcurrentRequests(2))
+
+		long := &GetSpansRequest{TimeRange: TimeReferenceRange{From: NewFixedTime(from), To: NewFixedTime(from.Add(8 * time.Hour))}}
+		got := collectSchemaURLs(t, client.GetSpansWindowedIter(context.Background(), long, WithWindowLookahead(4)))
+		if len(got) != 8 {
+			t.Errorf("expected 8 results, got %v", got)
+		}
+		if m := server.maxSeen.Load(); m != 2 {
+			t.Errorf("expected 2 concurrent requests, got %d", m)
+		}
+	})
+
+	t.Run("stops at the first error", func(t *testing.T) {
+		client := newWindowTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadRequest)
+		}))
+
+		iter := client.GetSpansWindowedIter(context.Background(), request)
+		if iter.Next() {
+			t.Fatal("expected Next to fail")
+		}
+		var apiErr *APIError
+		if !errors.As(iter.Err(), &apiErr) {
+			t.Errorf("expected APIError, got %v", iter.Err())
+		}
+		if iter.Cursor() != nil {
+			t.Error("expected no cursor for windowed iterators")
+		}
+	})
+
+	t.Run("stops prefetching when closed", func(t *testing.T) {
+		server := &windowServer{t: t, pages: func(from, to time.Time) int { return 100 }}
+		client := newWindowTestClient(t, server)
+
+		iter := client.GetSpansWindowedIter(context.Background(), request, WithWindowLookahead(1))
+		if !iter.Next() {
+			t.Fatalf("expected an item, got %v", iter.Err())
+		}
+		iter.Close()
+		if iter.Next() {
+			t.Error("expected Next to return false after Close")
+		}
+
+		time.Sleep(20 * time.Millisecond)
+		server.mu.Lock()
+		requests := server.requests
+		server.mu.Unlock()
+		if requests > 1+windowBufferPages+1 {
+			t.Errorf("expected prefetching to stop, got %d requests", requests)
+		}
+	})
+
+	t.Run("rejects invalid time ranges", func(t *testing.T) {
+		client := newWindowTestClient(t, http.NotFoundHandler())
+
+		iter := client.GetSpansWindowedIter(context.Background(), &GetSpansRequest{TimeRange: TimeReferenceRange{From: "now-1hr", To: "now"}})
+		if iter.Next() || iter.Err() == nil {
+			t.Error("expected an error")
+		}
+	})
+}